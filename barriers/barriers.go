@@ -148,4 +148,5 @@ func init() {
 	tn := errbase.GetTypeKey((*barrierErr)(nil))
 	errbase.RegisterLeafDecoder(tn, decodeBarrier)
 	errbase.RegisterLeafEncoder(tn, encodeBarrier)
+	errbase.RegisterMaskingLayer(tn)
 }
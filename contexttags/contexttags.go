@@ -66,6 +66,29 @@ func GetContextTags(err error) (res []*logtags.Buffer) {
 	return res
 }
 
+// ResolveContextTags merges the k/v pairs stored in the error into a
+// single map, with one value per key. When a key is present at
+// multiple levels of cause, the innermost value wins, as it is the
+// closest to the origin of the error.
+//
+// Like with GetContextTags(), only the string representation of the
+// values is available. The values are not redacted; the redacted
+// representation of the tags remains available separately, via
+// `errors.GetSafeDetails()`.
+func ResolveContextTags(err error) map[string]interface{} {
+	tagsets := GetContextTags(err)
+	if len(tagsets) == 0 {
+		return nil
+	}
+	res := make(map[string]interface{})
+	for _, b := range tagsets {
+		for _, t := range b.Get() {
+			res[t.Key()] = t.ValueStr()
+		}
+	}
+	return res
+}
+
 func hasNonStringValue(b *logtags.Buffer) bool {
 	for _, t := range b.Get() {
 		v := t.Value()
@@ -88,6 +88,36 @@ func TestWithContext(t *testing.T) {
 	tt.Run("remote", func(tt testutils.T) { theTest(tt, newErr) })
 }
 
+func TestResolveContextTags(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.Check(contexttags.ResolveContextTags(errors.New("hello")) == nil)
+
+	ctx := context.Background()
+	ctx = logtags.AddTag(ctx, "node", 1)
+	ctx = logtags.AddTag(ctx, "inner", nil)
+
+	ctx2 := context.Background()
+	ctx2 = logtags.AddTag(ctx2, "node", 2)
+	ctx2 = logtags.AddTag(ctx2, "outer", "universe")
+
+	err := errors.WithContextTags(errors.New("hello"), ctx)
+	err = errors.Wrap(err, "wrap")
+	err = errors.WithContextTags(err, ctx2)
+
+	expected := map[string]interface{}{
+		"node":  "1",
+		"inner": "",
+		"outer": "universe",
+	}
+
+	tt.CheckDeepEqual(contexttags.ResolveContextTags(err), expected)
+
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+	tt.CheckDeepEqual(contexttags.ResolveContextTags(newErr), expected)
+}
+
 func TestTagRedactionInSafeDetails(t *testing.T) {
 	tt := testutils.T{T: t}
 
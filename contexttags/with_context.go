@@ -112,4 +112,5 @@ func decodeWithContext(
 func init() {
 	errbase.RegisterWrapperEncoder(errbase.GetTypeKey((*withContext)(nil)), encodeWithContext)
 	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withContext)(nil)), decodeWithContext)
+	errbase.RegisterStrippableAnnotation(errbase.GetTypeKey((*withContext)(nil)))
 }
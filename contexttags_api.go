@@ -45,3 +45,16 @@ func WithContextTags(err error, ctx context.Context) error {
 // The returned logtags.Buffer only know about the string
 // representation of the values originally captured by the error.
 func GetContextTags(err error) []*logtags.Buffer { return contexttags.GetContextTags(err) }
+
+// ResolveContextTags merges the k/v pairs stored in the error into a
+// single map, with one value per key. When a key is present at
+// multiple levels of cause, the innermost value wins, as it is the
+// closest to the origin of the error.
+//
+// Like with GetContextTags(), only the string representation of the
+// values is available. The values are not redacted; the redacted
+// representation of the tags remains available separately, via
+// `errors.GetSafeDetails()`.
+func ResolveContextTags(err error) map[string]interface{} {
+	return contexttags.ResolveContextTags(err)
+}
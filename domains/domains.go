@@ -48,6 +48,52 @@ func GetDomain(err error) Domain {
 	return NoDomain
 }
 
+// GetLayerDomain retrieves the domain carried by the error (not its
+// causes), or false if it is not a domain annotation.
+func GetLayerDomain(err error) (Domain, bool) {
+	if b, ok := err.(*withDomain); ok {
+		return b.domain, true
+	}
+	return NoDomain, false
+}
+
+// GetAllDomains retrieves the domains of all the domain annotations
+// in the error's direct causal chain, from the outermost to the
+// innermost. Layers annotated with NoDomain are skipped. The search
+// stops at barriers, like GetDomain(). The result is nil if there is
+// no domain annotation.
+func GetAllDomains(err error) []Domain {
+	var res []Domain
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		if b, ok := c.(*withDomain); ok && b.domain != NoDomain {
+			res = append(res, b.domain)
+		}
+	}
+	return res
+}
+
+// HasConflictingDomains returns true if the error's causal chain
+// contains domain annotations with two or more different domains.
+// This can indicate that the error was mis-wrapped while crossing
+// component boundaries, and is meant to power consistency checks in
+// tests. Layers without a domain are ignored. The search stops at
+// barriers, like GetDomain().
+func HasConflictingDomains(err error) bool {
+	found := NoDomain
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		b, ok := c.(*withDomain)
+		if !ok || b.domain == NoDomain {
+			continue
+		}
+		if found == NoDomain {
+			found = b.domain
+		} else if b.domain != found {
+			return true
+		}
+	}
+	return false
+}
+
 // WithDomain wraps an error so that it appears to come from the given domain.
 //
 // Domain is shown:
@@ -145,11 +191,41 @@ func PackageDomain() Domain {
 
 // PackageDomainAtDepth returns an error domain that describes the
 // package at the given call depth.
+//
+// If a default domain was registered for that package with
+// RegisterPackageDefault(), that domain is returned instead.
 func PackageDomainAtDepth(depth int) Domain {
 	_, f, _, _ := runtime.Caller(1 + depth)
-	return Domain("error domain: pkg " + filepath.Dir(f))
+	dir := filepath.Dir(f)
+	if d, ok := packageDefaults[dir]; ok {
+		return d
+	}
+	return Domain("error domain: pkg " + dir)
 }
 
+// RegisterPackageDefault declares the given domain as the implicit
+// domain of its caller's package. After this is called, errors
+// created in that package via New() or Handled(), as well as
+// PackageDomain(), use this domain instead of the one computed from
+// the package location. This makes the domain stable across
+// refactors and import path changes.
+//
+// This is meant to be called from an init() function.
+func RegisterPackageDefault(d Domain) {
+	RegisterPackageDefaultAtDepth(1, d)
+}
+
+// RegisterPackageDefaultAtDepth is like RegisterPackageDefault but
+// declares the default domain for the package at the given call
+// depth.
+func RegisterPackageDefaultAtDepth(depth int, d Domain) {
+	_, f, _, _ := runtime.Caller(1 + depth)
+	packageDefaults[filepath.Dir(f)] = d
+}
+
+// packageDefaults is the registry for RegisterPackageDefault.
+var packageDefaults = map[string]Domain{}
+
 // NamedDomain returns an error domain identified by the given string.
 func NamedDomain(domainName string) Domain {
 	return Domain(fmt.Sprintf("error domain: %q", domainName))
@@ -22,6 +22,7 @@ import (
 
 	"github.com/cockroachdb/errors/domains"
 	"github.com/cockroachdb/errors/domains/internal"
+	"github.com/cockroachdb/errors/domains/internal/pkgdefault"
 	"github.com/cockroachdb/errors/errbase"
 	"github.com/cockroachdb/errors/markers"
 	"github.com/cockroachdb/errors/testutils"
@@ -94,6 +95,24 @@ func TestPackageDomain(t *testing.T) {
 	tt.Check(domains.NotInDomain(hereErr, domains.NoDomain))
 }
 
+func TestRegisterPackageDefault(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	// Errors created in a package with a registered default
+	// use that default domain.
+	err := pkgdefault.NewError("hello")
+	tt.CheckEqual(domains.GetDomain(err), pkgdefault.ThisDomain)
+	tt.Check(!domains.NotInDomain(err, pkgdefault.ThisDomain))
+
+	err = pkgdefault.HandledError(errors.New("hello"))
+	tt.CheckEqual(domains.GetDomain(err), pkgdefault.ThisDomain)
+
+	// Other packages are not affected.
+	hereErr := domains.New("hello")
+	tt.CheckEqual(domains.GetDomain(hereErr), domains.PackageDomain())
+	tt.Check(domains.GetDomain(hereErr) != pkgdefault.ThisDomain)
+}
+
 // This test demonstrates how the original domain becomes invisible
 // via WithDomain(), but the original error remains visible as cause.
 func TestWithDomain(t *testing.T) {
@@ -140,6 +159,51 @@ func TestHandledInDomain(t *testing.T) {
 	tt.CheckEqual(err.Error(), "hello")
 }
 
+func TestHasConflictingDomains(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := errors.New("hello")
+	tt.Check(!domains.HasConflictingDomains(err))
+	tt.Check(!domains.HasConflictingDomains(nil))
+
+	// A single domain, possibly repeated, is not a conflict.
+	err = domains.WithDomain(err, "mydomain")
+	tt.Check(!domains.HasConflictingDomains(err))
+	err = domains.WithDomain(errors.Wrap(err, "woo"), "mydomain")
+	tt.Check(!domains.HasConflictingDomains(err))
+
+	// Two different domains are.
+	err = domains.WithDomain(errors.Wrap(err, "waa"), "otherdomain")
+	tt.Check(domains.HasConflictingDomains(err))
+
+	// Barriers hide the domains of their cause.
+	err = domains.HandledInDomain(err, "mydomain")
+	tt.Check(!domains.HasConflictingDomains(err))
+}
+
+func TestGetAllDomains(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := errors.New("hello")
+	tt.Check(domains.GetAllDomains(err) == nil)
+	tt.Check(domains.GetAllDomains(nil) == nil)
+
+	err = domains.WithDomain(err, "inner")
+	err = domains.WithDomain(errors.Wrap(err, "woo"), "outer")
+	tt.CheckDeepEqual(domains.GetAllDomains(err), []domains.Domain{"outer", "inner"})
+
+	// GetLayerDomain only looks at the given layer.
+	d, ok := domains.GetLayerDomain(err)
+	tt.Check(ok)
+	tt.CheckEqual(d, domains.Domain("outer"))
+	_, ok = domains.GetLayerDomain(errbase.UnwrapOnce(err))
+	tt.Check(!ok)
+
+	// Barriers hide the domains of their cause.
+	err = domains.HandledInDomain(err, "mydomain")
+	tt.CheckDeepEqual(domains.GetAllDomains(err), []domains.Domain{"mydomain"})
+}
+
 // This test demonstrates that Handled() overrides an error's original
 // domain with the current package's local domain.
 func TestHandled(t *testing.T) {
@@ -176,6 +240,31 @@ func TestDomainsBreakErrorEquivalence(t *testing.T) {
 	tt.Check(!markers.Is(err2, err1))
 }
 
+func TestIsIgnoringDomains(t *testing.T) {
+	baseErr := errors.New("hello")
+
+	err1 := domains.WithDomain(baseErr, domains.NamedDomain("woo"))
+	err2 := domains.WithDomain(baseErr, domains.NamedDomain("waa"))
+	domainFamily := errbase.GetTypeMark(err1).FamilyName
+
+	tt := testutils.T{T: t}
+
+	tt.Check(markers.IsIgnoring(err1, err2, domainFamily))
+	tt.Check(markers.IsIgnoring(err2, err1, domainFamily))
+	tt.Check(!markers.IsIgnoring(err1, err2))
+	tt.Check(!markers.IsIgnoring(err1, err2, "some/other/family"))
+
+	// The domain may also be missing altogether on one side.
+	tt.Check(markers.IsIgnoring(err1, baseErr, domainFamily))
+	tt.Check(markers.IsIgnoring(baseErr, err1, domainFamily))
+
+	// Other differences are still detected.
+	err3 := domains.WithDomain(errors.New("world"), domains.NamedDomain("waa"))
+	tt.Check(!markers.IsIgnoring(err1, err3, domainFamily))
+	err4 := domains.WithDomain(goErr.New("hello"), domains.NamedDomain("waa"))
+	tt.Check(!markers.IsIgnoring(err1, err4, domainFamily))
+}
+
 func TestFormat(t *testing.T) {
 	tt := testutils.T{t}
 
@@ -0,0 +1,34 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pkgdefault
+
+import "github.com/cockroachdb/errors/domains"
+
+// ThisDomain is a helper for tests.
+var ThisDomain = domains.NamedDomain("pkgdefault")
+
+func init() {
+	domains.RegisterPackageDefault(ThisDomain)
+}
+
+// NewError is a helper for tests.
+func NewError(msg string) error {
+	return domains.New(msg)
+}
+
+// HandledError is a helper for tests.
+func HandledError(err error) error {
+	return domains.Handled(err)
+}
@@ -81,4 +81,5 @@ func decodeWithDomain(
 func init() {
 	tn := errbase.GetTypeKey((*withDomain)(nil))
 	errbase.RegisterWrapperDecoder(tn, decodeWithDomain)
+	errbase.RegisterStrippableAnnotation(tn)
 }
@@ -34,6 +34,16 @@ func PackageDomain() Domain { return domains.PackageDomainAtDepth(1) }
 // package at the given call depth.
 func PackageDomainAtDepth(depth int) Domain { return domains.PackageDomainAtDepth(depth) }
 
+// RegisterPackageDefault declares the given domain as the implicit
+// domain of its caller's package. After this is called, errors
+// created in that package via New() or Handled(), as well as
+// PackageDomain(), use this domain instead of the one computed from
+// the package location. This makes the domain stable across
+// refactors and import path changes.
+//
+// This is meant to be called from an init() function.
+func RegisterPackageDefault(d Domain) { domains.RegisterPackageDefaultAtDepth(1, d) }
+
 // WithDomain wraps an error so that it appears to come from the given domain.
 //
 // Domain is shown:
@@ -73,6 +83,21 @@ func HandledInDomainWithMessage(err error, domain Domain, msg string) error {
 	return domains.HandledInDomainWithMessage(err, domain, msg)
 }
 
+// HasConflictingDomains returns true if the error's causal chain
+// contains domain annotations with two or more different domains.
+// This can indicate that the error was mis-wrapped while crossing
+// component boundaries, and is meant to power consistency checks in
+// tests. Layers without a domain are ignored. The search stops at
+// barriers, like GetDomain().
+func HasConflictingDomains(err error) bool { return domains.HasConflictingDomains(err) }
+
 // GetDomain extracts the domain of the given error, or NoDomain if
 // the error's cause does not have a domain annotation.
 func GetDomain(err error) Domain { return domains.GetDomain(err) }
+
+// GetAllDomains retrieves the domains of all the domain annotations
+// in the error's direct causal chain, from the outermost to the
+// innermost. Layers annotated with NoDomain are skipped. The search
+// stops at barriers, like GetDomain(). The result is nil if there is
+// no domain annotation.
+func GetAllDomains(err error) []Domain { return domains.GetAllDomains(err) }
@@ -192,11 +192,13 @@ func init() {
 
 	pkgE := pkgErr.New("")
 	RegisterLeafEncoder(GetTypeKey(pkgE), encodePkgFundamental)
+	RegisterStackTraceCarrier(GetTypeKey(pkgE))
 
 	RegisterWrapperDecoder(GetTypeKey(pkgErr.WithMessage(baseErr, "")), decodeWithMessage)
 
 	ws := pkgErr.WithStack(baseErr)
 	RegisterWrapperEncoder(GetTypeKey(ws), encodePkgWithStack)
+	RegisterStackTraceCarrier(GetTypeKey(ws))
 
 	registerOsPathErrorMigration() // Needed for Go 1.16.
 	pKey := GetTypeKey(&os.PathError{})
@@ -211,4 +213,11 @@ func init() {
 	RegisterWrapperDecoder(pKey, decodeSyscallError)
 
 	RegisterLeafEncoder(GetTypeKey(&OpaqueErrno{}), encodeOpaqueErrno)
+
+	// The standard library wrappers and multi-errors are reproduced
+	// faithfully by the opaque types. The multi-error types are named
+	// explicitly, as they do not exist in Go versions prior to 1.20.
+	RegisterOpaqueDecoding(GetTypeKey(fmt.Errorf("%w", baseErr)))
+	RegisterOpaqueDecoding(TypeKey(makeTypeKey("fmt", "*fmt.wrapErrors")))
+	RegisterOpaqueDecoding(TypeKey(makeTypeKey("errors", "*errors.joinError")))
 }
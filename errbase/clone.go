@@ -0,0 +1,87 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase
+
+import "context"
+
+// Cloner is implemented by wrapper types which cannot be rebuilt from
+// their encoding, for example because they have mutable or local-only
+// state, to let Clone(), StripAnnotations() and CompactStacks() copy
+// them. The wrapper types which do not implement it are rebuilt using
+// their registered encoder and decoder.
+type Cloner interface {
+	// CloneWithCause returns a copy of the layer, with the given cause
+	// in place of its own. The copy must not share any mutable state
+	// with the original.
+	CloneWithCause(cause error) error
+}
+
+// Clone returns a copy of err that is independent from it, so that
+// modifying a layer of one in place does not affect the other. The
+// copy has the same message, the same error marks (as per
+// markers.Is()) and the same annotations as err.
+//
+// The layers which implement Cloner are copied with CloneWithCause().
+// In this library, these are the stack traces and the wrappers with
+// local-only state: WithLocalMetadata(), WithLogged(),
+// errmeta.WithValue() and the secondary error summaries. The other
+// error types of this library are immutable.
+//
+// The layers above a copied layer are rebuilt around the copy: using
+// CloneWithCause() if they implement Cloner, and otherwise using the
+// encoder and decoder registered for their type, as if they had been
+// received from the network. The wrapper types which have not been
+// registered with the library are thus rebuilt as opaque wrappers,
+// which preserve their message, safe details and error mark but not
+// their Go type. The layers below the copied layers are immutable and
+// are shared between err and the copy. When err contains no layer
+// which implements Cloner, Clone returns err itself.
+func Clone(err error) error {
+	c, _ := cloneError(context.Background(), err)
+	return c
+}
+
+// cloneError implements Clone. It returns true iff the result is
+// different from err.
+func cloneError(ctx context.Context, err error) (error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	if cause := UnwrapOnce(err); cause != nil {
+		newCause, changed := cloneError(ctx, cause)
+		if c, ok := err.(Cloner); ok {
+			return c.CloneWithCause(newCause), true
+		}
+		if !changed {
+			return err, false
+		}
+		return replaceCause(ctx, err, cause, newCause), true
+	}
+	causes := UnwrapMulti(err)
+	var newCauses []error
+	for i, cause := range causes {
+		newCause, changed := cloneError(ctx, cause)
+		if changed && newCauses == nil {
+			newCauses = append([]error(nil), causes...)
+		}
+		if newCauses != nil {
+			newCauses[i] = newCause
+		}
+	}
+	if newCauses == nil {
+		return err, false
+	}
+	return replaceCauses(ctx, err, newCauses), true
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase_test
+
+import (
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errmeta"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/secondary"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+func TestClone(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.Check(errbase.Clone(nil) == nil)
+
+	// Immutable errors are returned as-is.
+	err := fmt.Errorf("woo: %w", errutil.WithMessage(goErr.New("hello"), "waa"))
+	tt.Check(errbase.Clone(err) == err)
+	err = secondary.WithSecondaryError(errutil.WithMessage(goErr.New("hello"), "waa"), goErr.New("other"))
+	tt.Check(errbase.Clone(err) == err)
+
+	// Mutable layers are copied.
+	counter := &countingWrapper{cause: makeStackedError(2)}
+	err = errutil.Wrap(counter, "woo")
+	c := errbase.Clone(err)
+	tt.Check(c != err)
+	tt.Check(markers.Is(c, err))
+	tt.CheckStringEqual(c.Error(), err.Error())
+	verbose := fmt.Sprintf("%+v", err)
+	tt.CheckStringEqual(fmt.Sprintf("%+v", c), verbose)
+	tt.CheckDeepEqual(errbase.GetAllSafeDetails(c), errbase.GetAllSafeDetails(err))
+
+	// Modifying the original does not affect the copy.
+	counter.count++
+	var cc *countingWrapper
+	tt.Check(goErr.As(c, &cc))
+	tt.CheckEqual(cc.count, 0)
+	// The stack traces below the copied layer are copied too, as
+	// they implement Cloner.
+	tt.Check(cc.cause != counter.cause)
+
+	// The multi-errors are copied.
+	err = goErr.Join(goErr.New("hello"), &countingWrapper{cause: goErr.New("world")})
+	c = errbase.Clone(err)
+	tt.Check(c != err)
+	tt.Check(markers.Is(c, err))
+	tt.CheckStringEqual(c.Error(), err.Error())
+}
+
+func TestCloneRebuildsWrappersAboveCopies(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	// The wrappers of this library which are not encoded on the
+	// network keep their state. The wrappers which have not been
+	// registered with the library are rebuilt as opaque wrappers.
+	base := &countingWrapper{cause: goErr.New("hello")}
+	err := errutil.WithLocalMetadata(base, map[string]string{"k": "v"})
+	err = errmeta.WithValue(err, "key", 42)
+	err = secondary.WithSecondaryErrorSummary(err, goErr.New("other"), "summary")
+	err = &localWrapper{cause: err}
+
+	// The strict encoding mode does not apply when rebuilding the
+	// unregistered wrapper.
+	errbase.SetStrictEncoding(true)
+	defer errbase.SetStrictEncoding(false)
+
+	c := errbase.Clone(err)
+	tt.Check(c != err)
+	tt.Check(markers.Is(c, err))
+	tt.CheckStringEqual(c.Error(), err.Error())
+
+	var lw *localWrapper
+	tt.Check(!goErr.As(c, &lw))
+	tt.CheckDeepEqual(errutil.GetLocalMetadata(c), map[string]string{"k": "v"})
+	v, ok := errmeta.Value[int](c, "key")
+	tt.Check(ok)
+	tt.CheckEqual(v, 42)
+	tt.CheckEqual(len(secondary.GetSecondaryErrors(c)), 1)
+
+	var cc *countingWrapper
+	tt.Check(goErr.As(c, &cc))
+	tt.Check(cc != base)
+}
+
+// countingWrapper is a wrapper with mutable state.
+type countingWrapper struct {
+	cause error
+	count int
+}
+
+func (w *countingWrapper) Error() string { return w.cause.Error() }
+func (w *countingWrapper) Unwrap() error { return w.cause }
+func (w *countingWrapper) CloneWithCause(cause error) error {
+	return &countingWrapper{cause: cause, count: w.count}
+}
+
+type localWrapper struct{ cause error }
+
+func (w *localWrapper) Error() string { return "local: " + w.cause.Error() }
+func (w *localWrapper) Unwrap() error { return w.cause }
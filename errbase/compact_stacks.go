@@ -0,0 +1,89 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase
+
+import "context"
+
+// StackCompactor can be implemented by error layers carrying a stack
+// trace, to let CompactStacks() release the memory held by the stack
+// trace.
+type StackCompactor interface {
+	StackTraceProvider
+
+	// CompactedWithCause returns a copy of the layer, with the given
+	// cause in place of its own, which does not hold the stack trace.
+	// StackTrace() on the copy returns an empty stack trace, and the
+	// copy should indicate that its stack trace was compacted when
+	// formatted with %+v. The layer itself must not be modified.
+	CompactedWithCause(cause error) error
+}
+
+// CompactStacks reduces the memory retained by the stack traces in
+// the direct causal chain of err: the innermost stack trace is
+// preserved, and the stack traces of the layers above it are
+// released if they implement StackCompactor. The branches of
+// multi-errors are compacted in the same way, each independently. The
+// identity of the error, as per markers.Is(), is not modified.
+//
+// This is meant to be used for long-lived errors, for example errors
+// stored in a cache. Like the other functions of this library,
+// CompactStacks does not modify err: it returns a new error where the
+// compacted layers, and the layers above them, are copies. The layers
+// above the compacted ones are rebuilt like in Clone(), so the wrapper
+// types which have not been registered with the library become opaque
+// wrappers. The other layers are shared with err. When there is
+// nothing to compact, err itself is returned.
+func CompactStacks(err error) error {
+	res, _, _ := compactStacks(context.Background(), err)
+	return res
+}
+
+// compactStacks implements CompactStacks. It returns whether the
+// result is different from err, and whether it has a stack trace in
+// its direct causal chain.
+func compactStacks(ctx context.Context, err error) (res error, changed, hasStack bool) {
+	if err == nil {
+		return nil, false, false
+	}
+	st, ok := err.(StackTraceProvider)
+	ownStack := ok && len(st.StackTrace()) > 0
+	if cause := UnwrapOnce(err); cause != nil {
+		newCause, changed, stackBelow := compactStacks(ctx, cause)
+		if ownStack && stackBelow {
+			if sc, ok := err.(StackCompactor); ok {
+				return sc.CompactedWithCause(newCause), true, true
+			}
+		}
+		if changed {
+			err = replaceCause(ctx, err, cause, newCause)
+		}
+		return err, changed, ownStack || stackBelow
+	}
+	causes := UnwrapMulti(err)
+	var newCauses []error
+	for i, cause := range causes {
+		newCause, changed, _ := compactStacks(ctx, cause)
+		if changed && newCauses == nil {
+			newCauses = append([]error(nil), causes...)
+		}
+		if newCauses != nil {
+			newCauses[i] = newCause
+		}
+	}
+	if newCauses == nil {
+		return err, false, ownStack
+	}
+	return replaceCauses(ctx, err, newCauses), true, ownStack
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/join"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/withstack"
+)
+
+func makeStackedError(layers int) error {
+	err := errutil.New("hello")
+	for i := 1; i < layers; i++ {
+		err = withstack.WithStack(err)
+	}
+	return err
+}
+
+// stackBytes computes the memory used by the program counters of the
+// stack traces in the direct causal chain of err.
+func stackBytes(err error) int {
+	n := 0
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		if st, ok := c.(errbase.StackTraceProvider); ok {
+			n += len(st.StackTrace()) * int(unsafe.Sizeof(uintptr(0)))
+		}
+	}
+	return n
+}
+
+func TestCompactStacks(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.CheckEqual(errbase.CompactStacks(nil), nil)
+
+	ref := makeStackedError(3)
+	orig := makeStackedError(3)
+	origVerbose := fmt.Sprintf("%+v", orig)
+	innermost := errbase.UnwrapOnce(errbase.UnwrapOnce(orig))
+	innermostStack := innermost.(errbase.StackTraceProvider).StackTrace()
+	before := stackBytes(orig)
+
+	err := errbase.CompactStacks(orig)
+	tt.Check(err != orig)
+
+	// Only the innermost stack trace is retained, and shared with
+	// the original error.
+	tt.CheckEqual(stackBytes(err), stackBytes(innermost))
+	tt.Check(stackBytes(err) < before)
+	tt.Check(errbase.UnwrapOnce(errbase.UnwrapOnce(err)) == innermost)
+	tt.CheckDeepEqual(innermost.(errbase.StackTraceProvider).StackTrace(), innermostStack)
+	file, _, _, ok := withstack.GetOneLineSource(err)
+	tt.Check(ok)
+	tt.CheckStringEqual(file, "compact_stacks_test.go")
+
+	// The original error is not modified.
+	tt.CheckEqual(stackBytes(orig), before)
+	tt.CheckStringEqual(fmt.Sprintf("%+v", orig), origVerbose)
+
+	// The identity of the error is preserved.
+	tt.Check(markers.Is(err, ref))
+	tt.CheckStringEqual(err.Error(), ref.Error())
+
+	// The compacted layers are still visible when formatting.
+	s := fmt.Sprintf("%+v", err)
+	tt.CheckEqual(strings.Count(s, "attached stack trace (compacted; see the innermost stack trace)"), 2)
+	tt.CheckEqual(strings.Count(s, "-- stack trace:"), 1)
+
+	// Compacting twice is harmless, and there is nothing left to
+	// compact.
+	tt.Check(errbase.CompactStacks(err) == err)
+
+	// The layers above the compacted ones are copied. The local-only
+	// wrappers of this library keep their state, and the unregistered
+	// wrappers are rebuilt as opaque wrappers.
+	orig = &localWrapper{cause: errutil.WithLocalMetadata(makeStackedError(2), map[string]string{"k": "v"})}
+	err = errbase.CompactStacks(orig)
+	tt.Check(err != orig)
+	_, isLocal := err.(*localWrapper)
+	tt.Check(!isLocal)
+	tt.CheckStringEqual(err.Error(), orig.Error())
+	tt.Check(markers.Is(err, orig))
+	tt.CheckDeepEqual(errutil.GetLocalMetadata(err), map[string]string{"k": "v"})
+	tt.CheckEqual(stackBytes(err), stackBytes(errbase.UnwrapOnce(errbase.UnwrapOnce(errbase.UnwrapOnce(err)))))
+
+	// The branches of multi-errors are compacted independently.
+	b1, b2 := makeStackedError(2), makeStackedError(2)
+	multi := errbase.CompactStacks(withstack.WithStack(join.Join(b1, b2)))
+	tt.Check(stackBytes(multi) > 0)
+	branches := errbase.UnwrapMulti(errbase.UnwrapOnce(multi))
+	tt.CheckEqual(len(branches), 2)
+	for _, b := range branches {
+		tt.CheckEqual(stackBytes(b), stackBytes(errbase.UnwrapOnce(b)))
+	}
+	tt.Check(stackBytes(b1) > stackBytes(errbase.UnwrapOnce(b1)))
+}
+
+func BenchmarkCompactStacks(b *testing.B) {
+	for _, compact := range []bool{false, true} {
+		b.Run(fmt.Sprintf("compact=%v", compact), func(b *testing.B) {
+			var retained int
+			for i := 0; i < b.N; i++ {
+				err := makeStackedError(10)
+				if compact {
+					err = errbase.CompactStacks(err)
+				}
+				retained = stackBytes(err)
+			}
+			b.ReportMetric(float64(retained), "stack-bytes/err")
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase
+
+import "sync/atomic"
+
+// constructionHook holds the function registered via
+// SetConstructionHook(). It is wrapped in a struct because
+// atomic.Value cannot store a nil value.
+type constructionHook struct {
+	fn func(err error)
+}
+
+var theConstructionHook atomic.Value // constructionHook
+
+// SetConstructionHook registers a function to be called every time
+// the library constructs an error with a stack trace, for example via
+// New(), Newf(), Wrap(), Wrapf() or WithStack(). The function
+// receives the newly constructed error. This can be used e.g. to
+// count or sample errors by type at the point they are created.
+//
+// The hook is called synchronously on the goroutine constructing the
+// error, so it should be fast and must not block. It must also not
+// construct errors using the library itself, to avoid infinite
+// recursion.
+//
+// Passing nil removes the hook. It is safe to call
+// SetConstructionHook() concurrently with the construction of errors.
+func SetConstructionHook(fn func(err error)) {
+	theConstructionHook.Store(constructionHook{fn: fn})
+}
+
+// CallConstructionHook calls the function registered via
+// SetConstructionHook(), if any, with the given error. It is meant
+// to be called by the error constructors in this library and is
+// exported only for this purpose.
+func CallConstructionHook(err error) {
+	if h, ok := theConstructionHook.Load().(constructionHook); ok && h.fn != nil {
+		h.fn(err)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase_test
+
+import (
+	goErr "errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/withstack"
+)
+
+func TestConstructionHook(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	var count int64
+	var last error
+	errbase.SetConstructionHook(func(err error) {
+		atomic.AddInt64(&count, 1)
+		last = err
+	})
+	defer errbase.SetConstructionHook(nil)
+
+	// Each constructor calls the hook exactly once, with the
+	// error it returns.
+	err := errutil.New("hello")
+	tt.CheckEqual(atomic.LoadInt64(&count), int64(1))
+	tt.CheckEqual(last, err)
+
+	err = errutil.Newf("hello %s", "world")
+	tt.CheckEqual(atomic.LoadInt64(&count), int64(2))
+	tt.CheckEqual(last, err)
+
+	err = errutil.Wrap(err, "woo")
+	tt.CheckEqual(atomic.LoadInt64(&count), int64(3))
+	tt.CheckEqual(last, err)
+
+	err = errutil.Wrapf(err, "woo %d", 123)
+	tt.CheckEqual(atomic.LoadInt64(&count), int64(4))
+	tt.CheckEqual(last, err)
+
+	err = withstack.WithStack(goErr.New("hello"))
+	tt.CheckEqual(atomic.LoadInt64(&count), int64(5))
+	tt.CheckEqual(last, err)
+
+	// Wrapping nil does not construct an error.
+	tt.CheckEqual(errutil.Wrap(nil, "woo"), nil)
+	tt.CheckEqual(atomic.LoadInt64(&count), int64(5))
+
+	// The hook is safe for concurrent use.
+	last = nil
+	errbase.SetConstructionHook(func(err error) { atomic.AddInt64(&count, 1) })
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = errutil.New("hello")
+		}()
+	}
+	wg.Wait()
+	tt.CheckEqual(atomic.LoadInt64(&count), int64(15))
+
+	// After the hook is removed, it is not called any more.
+	errbase.SetConstructionHook(nil)
+	_ = errutil.New("hello")
+	tt.CheckEqual(atomic.LoadInt64(&count), int64(15))
+}
@@ -16,6 +16,8 @@ package errbase
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 
 	"github.com/cockroachdb/errors/errorspb"
 	"github.com/gogo/protobuf/proto"
@@ -24,20 +26,106 @@ import (
 
 // DecodeError decodes an error.
 //
-// Can only be called if the EncodedError is set (see IsSet()).
+// The EncodedError is expected to be set (see IsSet()). If it is
+// not, for example because it was encoded by a newer version of the
+// library using a kind of layer unknown to this version, the result
+// is an opaque leaf error that reports the encoding format version.
 func DecodeError(ctx context.Context, enc EncodedError) error {
+	return decodeError(ctx, enc, enc.FormatVersion, false /* lite */)
+}
+
+// DecodeErrorLite is like DecodeError, but skips the stack traces
+// embedded in the encoded error. This reduces the cost of decoding
+// for callers that only care about the messages, error marks and
+// other details of the error. In particular, markers.Is() works
+// the same on the result.
+//
+// The layers of the result that carried a stack trace, as declared
+// via RegisterStackTraceCarrier(), do not report it anywhere: not in
+// their safe details, not when formatting with %+v, and not when
+// the error is encoded again.
+//
+// Like DecodeError, it tolerates an EncodedError that is not set.
+func DecodeErrorLite(ctx context.Context, enc EncodedError) error {
+	return decodeError(ctx, enc, enc.FormatVersion, true /* lite */)
+}
+
+// decodeError implements DecodeError. The format version is that of
+// the outermost EncodedError: the causes do not carry it.
+func decodeError(ctx context.Context, enc EncodedError, version uint32, lite bool) error {
+	if w := enc.GetWrapper(); w != nil {
+		return decodeWrapper(ctx, w, version, lite)
+	}
+	if l := enc.GetLeaf(); l != nil {
+		return decodeLeaf(ctx, l, version, lite)
+	}
+	return decodeUnknownFormat(ctx, version)
+}
+
+// decodeUnknownFormat is used for an EncodedError where neither a
+// leaf nor a wrapper is set. This occurs when the error was encoded
+// by a newer version of the library using a kind of layer that this
+// version does not know about: the unknown field is skipped when
+// unmarshaling.
+func decodeUnknownFormat(ctx context.Context, version uint32) error {
+	warningFn(ctx, "unknown error encoding (format version %d, current %d)",
+		version, EncodedFormatVersion)
+	return &opaqueLeaf{
+		msg: fmt.Sprintf("undecodable error (format version %d)", version),
+	}
+}
+
+// DecodeErrorAs is like DecodeError, but for use by callers that
+// expect the outermost layer of the encoded error to be of the same
+// type as sample. It returns true iff the encoded error is of the
+// sample's type, as per GetTypeKey(), and the error was decoded into
+// that Go type using the decoder registered for it.
+//
+// If the types differ, or the error cannot be decoded into the
+// sample's type, for example because no decoder is registered, the
+// error is decoded normally and false is returned.
+func DecodeErrorAs(ctx context.Context, enc EncodedError, sample error) (error, bool) {
+	err := DecodeError(ctx, enc)
+	var mark errorspb.ErrorTypeMark
 	if w := enc.GetWrapper(); w != nil {
-		return decodeWrapper(ctx, w)
+		mark = w.Details.ErrorTypeMark
+	} else if l := enc.GetLeaf(); l != nil {
+		mark = l.Details.ErrorTypeMark
 	}
-	return decodeLeaf(ctx, enc.GetLeaf())
+	ok := TypeKey(migratedFamily(mark.FamilyName)) == GetTypeKey(sample) &&
+		reflect.TypeOf(err) == reflect.TypeOf(sample)
+	return err, ok
 }
 
-func decodeLeaf(ctx context.Context, enc *errorspb.EncodedErrorLeaf) error {
+func decodeLeaf(
+	ctx context.Context, enc *errorspb.EncodedErrorLeaf, version uint32, lite bool,
+) error {
+	var causes []error
+	if len(enc.MultierrorCauses) > 0 {
+		causes = make([]error, len(enc.MultierrorCauses))
+		for i, e := range enc.MultierrorCauses {
+			causes[i] = decodeError(ctx, *e, version, lite)
+		}
+	}
+	return decodeLeafLayer(ctx, enc, causes, lite)
+}
+
+// decodeLeafLayer decodes the leaf or multi-cause error enc with the
+// given, already decoded, causes. The MultierrorCauses field of enc
+// is ignored.
+func decodeLeafLayer(
+	ctx context.Context, enc *errorspb.EncodedErrorLeaf, causes []error, lite bool,
+) error {
+	details := enc.Details
+	if lite {
+		details = withoutStackTrace(details)
+	}
+
 	// In case there is a detailed payload, decode it.
 	var payload proto.Message
-	if enc.Details.FullDetails != nil {
+	if details.FullDetails != nil {
 		var d types.DynamicAny
-		err := types.UnmarshalAny(enc.Details.FullDetails, &d)
+		err := types.UnmarshalAny(details.FullDetails, &d)
 		if err != nil {
 			// It's OK if we can't decode. We'll use
 			// the opaque type below.
@@ -48,21 +136,17 @@ func decodeLeaf(ctx context.Context, enc *errorspb.EncodedErrorLeaf) error {
 	}
 
 	// Do we have a leaf decoder for this type?
-	typeKey := TypeKey(enc.Details.ErrorTypeMark.FamilyName)
+	typeKey := TypeKey(migratedFamily(details.ErrorTypeMark.FamilyName))
 	if decoder, ok := leafDecoders[typeKey]; ok {
 		// Yes, use it.
-		genErr := decoder(ctx, enc.Message, enc.Details.ReportablePayload, payload)
+		genErr := decoder(ctx, enc.Message, details.ReportablePayload, payload)
 		if genErr != nil {
 			// Decoding succeeded. Use this.
 			return genErr
 		}
 		// Decoding failed, we'll drop through to opaqueLeaf{} below.
 	} else if decoder, ok := multiCauseDecoders[typeKey]; ok {
-		causes := make([]error, len(enc.MultierrorCauses))
-		for i, e := range enc.MultierrorCauses {
-			causes[i] = DecodeError(ctx, *e)
-		}
-		genErr := decoder(ctx, causes, enc.Message, enc.Details.ReportablePayload, payload)
+		genErr := decoder(ctx, causes, enc.Message, details.ReportablePayload, payload)
 		if genErr != nil {
 			return genErr
 		}
@@ -75,16 +159,12 @@ func decodeLeaf(ctx context.Context, enc *errorspb.EncodedErrorLeaf) error {
 		}
 	}
 
-	if len(enc.MultierrorCauses) > 0 {
-		causes := make([]error, len(enc.MultierrorCauses))
-		for i, e := range enc.MultierrorCauses {
-			causes[i] = DecodeError(ctx, *e)
-		}
+	if len(causes) > 0 {
 		leaf := &opaqueLeafCauses{
 			causes: causes,
 		}
 		leaf.msg = enc.Message
-		leaf.details = enc.Details
+		leaf.details = details
 		return leaf
 	}
 
@@ -93,19 +173,33 @@ func decodeLeaf(ctx context.Context, enc *errorspb.EncodedErrorLeaf) error {
 	// network again).
 	return &opaqueLeaf{
 		msg:     enc.Message,
-		details: enc.Details,
+		details: details,
 	}
 }
 
-func decodeWrapper(ctx context.Context, enc *errorspb.EncodedWrapper) error {
+func decodeWrapper(
+	ctx context.Context, enc *errorspb.EncodedWrapper, version uint32, lite bool,
+) error {
 	// First decode the cause.
-	cause := DecodeError(ctx, enc.Cause)
+	cause := decodeError(ctx, enc.Cause, version, lite)
+	return decodeWrapperLayer(ctx, enc, cause, lite)
+}
+
+// decodeWrapperLayer decodes the wrapper enc around the given,
+// already decoded, cause. The Cause field of enc is ignored.
+func decodeWrapperLayer(
+	ctx context.Context, enc *errorspb.EncodedWrapper, cause error, lite bool,
+) error {
+	details := enc.Details
+	if lite {
+		details = withoutStackTrace(details)
+	}
 
 	// In case there is a detailed payload, decode it.
 	var payload proto.Message
-	if enc.Details.FullDetails != nil {
+	if details.FullDetails != nil {
 		var d types.DynamicAny
-		err := types.UnmarshalAny(enc.Details.FullDetails, &d)
+		err := types.UnmarshalAny(details.FullDetails, &d)
 		if err != nil {
 			// It's OK if we can't decode. We'll use
 			// the opaque type below.
@@ -116,10 +210,10 @@ func decodeWrapper(ctx context.Context, enc *errorspb.EncodedWrapper) error {
 	}
 
 	// Do we have a wrapper decoder for this?
-	typeKey := TypeKey(enc.Details.ErrorTypeMark.FamilyName)
+	typeKey := TypeKey(migratedFamily(details.ErrorTypeMark.FamilyName))
 	if decoder, ok := decoders[typeKey]; ok {
 		// Yes, use it.
-		genErr := decoder(ctx, cause, enc.Message, enc.Details.ReportablePayload, payload)
+		genErr := decoder(ctx, cause, enc.Message, details.ReportablePayload, payload)
 		if genErr != nil {
 			// Decoding succeeded. Use this.
 			return genErr
@@ -131,11 +225,36 @@ func decodeWrapper(ctx context.Context, enc *errorspb.EncodedWrapper) error {
 	return &opaqueWrapper{
 		cause:       cause,
 		prefix:      enc.Message,
-		details:     enc.Details,
+		details:     details,
 		messageType: MessageType(enc.MessageType),
 	}
 }
 
+// withoutStackTrace returns a copy of details without the printed
+// stack trace, if the error type has been declared via
+// RegisterStackTraceCarrier().
+func withoutStackTrace(details errorspb.EncodedErrorDetails) errorspb.EncodedErrorDetails {
+	typeKey := TypeKey(migratedFamily(details.ErrorTypeMark.FamilyName))
+	if _, ok := stackTraceCarriers[typeKey]; ok && len(details.ReportablePayload) > 0 {
+		details.ReportablePayload = details.ReportablePayload[1:]
+	}
+	return details
+}
+
+// RegisterStackTraceCarrier declares that the first safe detail of
+// the encoded form of the given error type is a printed stack trace,
+// as is the case for the error types that carry a stack trace in
+// github.com/pkg/errors and in the withstack package. This is used by
+// DecodeErrorLite() to skip the stack trace.
+//
+// This is meant to be called from an init() function.
+func RegisterStackTraceCarrier(theType TypeKey) {
+	stackTraceCarriers[theType] = struct{}{}
+}
+
+// registry for RegisterStackTraceCarrier.
+var stackTraceCarriers = map[TypeKey]struct{}{}
+
 // RegisterLeafDecoder can be used to register new leaf error types to
 // the library. Registered types will be decoded using their own
 // Go type when an error is decoded. Wrappers that have not been
@@ -0,0 +1,132 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/withstack"
+	"github.com/gogo/protobuf/proto"
+	pkgErr "github.com/pkg/errors"
+)
+
+type decodeAsErr struct{ msg string }
+
+func (e *decodeAsErr) Error() string { return e.msg }
+
+func TestDecodeErrorAs(t *testing.T) {
+	tt := testutils.T{T: t}
+	ctx := context.Background()
+
+	origErr := &decodeAsErr{msg: "hello"}
+	enc := errbase.EncodeError(ctx, origErr)
+
+	// Without a decoder, the error cannot be decoded into
+	// the expected type.
+	dec, ok := errbase.DecodeErrorAs(ctx, enc, (*decodeAsErr)(nil))
+	tt.Check(!ok)
+	tt.CheckStringEqual(dec.Error(), "hello")
+
+	errbase.RegisterLeafDecoder(errbase.GetTypeKey(origErr),
+		func(_ context.Context, msg string, _ []string, _ proto.Message) error {
+			return &decodeAsErr{msg: msg}
+		})
+	defer errbase.RegisterLeafDecoder(errbase.GetTypeKey(origErr), nil)
+
+	// With a decoder, the type is recognized.
+	dec, ok = errbase.DecodeErrorAs(ctx, enc, (*decodeAsErr)(nil))
+	tt.Check(ok)
+	tt.CheckDeepEqual(dec, origErr)
+
+	// The outermost layer must be of the expected type.
+	enc = errbase.EncodeError(ctx, pkgErr.WithMessage(origErr, "woo"))
+	dec, ok = errbase.DecodeErrorAs(ctx, enc, (*decodeAsErr)(nil))
+	tt.Check(!ok)
+	tt.CheckStringEqual(dec.Error(), "woo: hello")
+
+	// Other types are decoded normally.
+	enc = errbase.EncodeError(ctx, goErr.New("world"))
+	dec, ok = errbase.DecodeErrorAs(ctx, enc, (*decodeAsErr)(nil))
+	tt.Check(!ok)
+	tt.CheckStringEqual(dec.Error(), "world")
+}
+
+func TestDecodeErrorLite(t *testing.T) {
+	tt := testutils.T{T: t}
+	ctx := context.Background()
+
+	origErr := errutil.Wrapf(pkgErr.WithStack(pkgErr.New("hello")), "woo %s", "waa")
+	enc := errbase.EncodeError(ctx, origErr)
+
+	full := errbase.DecodeError(ctx, enc)
+	lite := errbase.DecodeErrorLite(ctx, enc)
+
+	// The messages and the marks are preserved.
+	tt.CheckStringEqual(lite.Error(), origErr.Error())
+	tt.Check(markers.Is(lite, origErr))
+	tt.Check(markers.Is(lite, full))
+
+	// The stack traces are omitted, but not the other details.
+	numLayers := 0
+	for c, l := full, lite; c != nil; c, l = errbase.UnwrapOnce(c), errbase.UnwrapOnce(l) {
+		numLayers++
+		fullDetails := errbase.GetSafeDetails(c).SafeDetails
+		liteDetails := errbase.GetSafeDetails(l).SafeDetails
+		if withstack.GetReportableStackTrace(c) != nil {
+			tt.Check(withstack.GetReportableStackTrace(l) == nil)
+			tt.CheckDeepEqual(liteDetails, fullDetails[1:])
+		} else {
+			tt.CheckDeepEqual(liteDetails, fullDetails)
+		}
+	}
+	tt.CheckEqual(numLayers, 4)
+	tt.Check(strings.Contains(fmt.Sprintf("%+v", full), "TestDecodeErrorLite"))
+	tt.Check(!strings.Contains(fmt.Sprintf("%+v", lite), "TestDecodeErrorLite"))
+}
+
+func BenchmarkDecodeErrorLite(b *testing.B) {
+	ctx := context.Background()
+	enc := errbase.EncodeError(ctx, makeStackedError(10))
+	for _, lite := range []bool{false, true} {
+		b.Run(fmt.Sprintf("lite=%v", lite), func(b *testing.B) {
+			b.ReportAllocs()
+			var err error
+			for i := 0; i < b.N; i++ {
+				if lite {
+					err = errbase.DecodeErrorLite(ctx, enc)
+				} else {
+					err = errbase.DecodeError(ctx, enc)
+				}
+			}
+			// Report the size of the details retained by the decoded
+			// error.
+			retained := 0
+			for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+				for _, d := range errbase.GetSafeDetails(c).SafeDetails {
+					retained += len(d)
+				}
+			}
+			b.ReportMetric(float64(retained), "detail-bytes/err")
+		})
+	}
+}
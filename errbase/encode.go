@@ -16,6 +16,7 @@ package errbase
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"reflect"
 	"strings"
@@ -28,9 +29,34 @@ import (
 // EncodedError is the type of an encoded (and protobuf-encodable) error.
 type EncodedError = errorspb.EncodedError
 
+// EncodedFormatVersion is the version of the encoding format
+// produced by EncodeError(). It is stamped on the outermost
+// EncodedError only, since a whole error is always encoded with the
+// same version, so that decoders can detect version skew, for
+// example in mixed-version clusters or when decoding long-lived
+// persisted errors. The EncodedError of the causes report version
+// zero.
+//
+// Errors encoded before the version was introduced report version
+// zero. DecodeError() accepts errors encoded with any version:
+// fields unknown to the decoder are ignored, and fields missing
+// from older encodings take their default value.
+const EncodedFormatVersion uint32 = 1
+
 // EncodeError encodes an error.
 func EncodeError(ctx context.Context, err error) EncodedError {
+	enc := encodeError(ctx, err)
+	enc.FormatVersion = EncodedFormatVersion
+	return enc
+}
+
+// encodeError implements EncodeError, without the format version.
+// It is used for the causes.
+func encodeError(ctx context.Context, err error) EncodedError {
 	if cause := UnwrapOnce(err); cause != nil {
+		if _, ok := localOnlyWrappers[GetTypeKey(err)]; ok {
+			return encodeError(ctx, cause)
+		}
 		return encodeWrapper(ctx, err, cause)
 	}
 	return encodeLeaf(ctx, err, UnwrapMulti(err))
@@ -42,6 +68,20 @@ func EncodeError(ctx context.Context, err error) EncodedError {
 // introducing this functionality since the Wrapper type already has a
 // required single `cause` field.
 func encodeLeaf(ctx context.Context, err error, causes []error) EncodedError {
+	l := encodeLeafLayer(ctx, err)
+	if len(causes) > 0 {
+		l.MultierrorCauses = make([]*EncodedError, len(causes))
+		for i, ee := range causes {
+			ee := encodeError(ctx, ee)
+			l.MultierrorCauses[i] = &ee
+		}
+	}
+	return EncodedError{Error: &errorspb.EncodedError_Leaf{Leaf: l}}
+}
+
+// encodeLeafLayer encodes the leaf or multi-cause error err without
+// its causes. The MultierrorCauses field of the result is left empty.
+func encodeLeafLayer(ctx context.Context, err error) *errorspb.EncodedErrorLeaf {
 	var msg string
 	var details errorspb.EncodedErrorDetails
 
@@ -75,28 +115,24 @@ func encodeLeaf(ctx context.Context, err error, causes []error) EncodedError {
 			// payload. DecodeLeaf() will know how to turn that back into a
 			// full error if there is no decoder.
 			payload, _ = err.(proto.Message)
+
+			if strictEncoding && !isRebuilding(ctx) {
+				_, hasDecoder := leafDecoders[typeKey]
+				_, hasMultiDecoder := multiCauseDecoders[typeKey]
+				_, isErrorPayload := payload.(error)
+				_, isOpaqueOK := opaqueDecodingTypes[typeKey]
+				if !hasDecoder && !hasMultiDecoder && !isErrorPayload && !isOpaqueOK {
+					panic(makeStrictEncodingError(details.OriginalTypeName))
+				}
+			}
 		}
 		// If there is a detail payload, encode it.
 		details.FullDetails = encodeAsAny(ctx, err, payload)
 	}
 
-	var cs []*EncodedError
-	if len(causes) > 0 {
-		cs = make([]*EncodedError, len(causes))
-		for i, ee := range causes {
-			ee := EncodeError(ctx, ee)
-			cs[i] = &ee
-		}
-	}
-
-	return EncodedError{
-		Error: &errorspb.EncodedError_Leaf{
-			Leaf: &errorspb.EncodedErrorLeaf{
-				Message:          msg,
-				Details:          details,
-				MultierrorCauses: cs,
-			},
-		},
+	return &errorspb.EncodedErrorLeaf{
+		Message: msg,
+		Details: details,
 	}
 }
 
@@ -111,6 +147,51 @@ func SetWarningFn(fn func(context.Context, string, ...interface{})) {
 	warningFn = fn
 }
 
+// strictEncoding can be enabled using SetStrictEncoding() below.
+var strictEncoding = false
+
+// SetStrictEncoding enables or disables the strict encoding mode,
+// which is off by default. In strict mode, EncodeError panics when
+// it encounters an error type which has neither a registered encoder
+// nor a registered decoder, and would thus be decoded as an opaque
+// error on the other side.
+//
+// This is intended for use in tests, to surface missing
+// registrations at the point of encoding. It should not be enabled
+// in production code.
+func SetStrictEncoding(strict bool) {
+	strictEncoding = strict
+}
+
+// RegisterOpaqueDecoding declares that the given error type is
+// intentionally left without a decoder, because decoding it as an
+// opaque error is sufficient. Such types are not reported by the
+// strict encoding mode enabled with SetStrictEncoding().
+func RegisterOpaqueDecoding(theType TypeKey) {
+	opaqueDecodingTypes[theType] = struct{}{}
+}
+
+// registry for RegisterOpaqueDecoding.
+var opaqueDecodingTypes = map[TypeKey]struct{}{}
+
+// RegisterLocalOnlyWrapper declares that the wrappers of the given
+// type carry local-only state, which is not meant to leave the
+// process. EncodeError() does not encode such a wrapper: its cause is
+// encoded in its place, so that the wrapper does not appear in the
+// encoded form of the error.
+//
+// This is meant to be called from an init() function.
+func RegisterLocalOnlyWrapper(theType TypeKey) {
+	localOnlyWrappers[theType] = struct{}{}
+}
+
+// registry for RegisterLocalOnlyWrapper.
+var localOnlyWrappers = map[TypeKey]struct{}{}
+
+func makeStrictEncodingError(typeName string) error {
+	return fmt.Errorf("strict encoding: no encoder or decoder registered for error type %s", typeName)
+}
+
 func encodeAsAny(ctx context.Context, err error, payload proto.Message) *types.Any {
 	if payload == nil {
 		return nil
@@ -129,6 +210,14 @@ func encodeAsAny(ctx context.Context, err error, payload proto.Message) *types.A
 
 // encodeWrapper encodes an error wrapper.
 func encodeWrapper(ctx context.Context, err, cause error) EncodedError {
+	w := encodeWrapperLayer(ctx, err, cause)
+	w.Cause = encodeError(ctx, cause)
+	return EncodedError{Error: &errorspb.EncodedError_Wrapper{Wrapper: w}}
+}
+
+// encodeWrapperLayer encodes the wrapper err, whose cause is cause,
+// without its cause. The Cause field of the result is left empty.
+func encodeWrapperLayer(ctx context.Context, err, cause error) *errorspb.EncodedWrapper {
 	var msg string
 	var details errorspb.EncodedErrorDetails
 	messageType := Prefix
@@ -163,21 +252,24 @@ func encodeWrapper(ctx context.Context, err, cause error) EncodedError {
 				details.ReportablePayload = s.SafeDetails()
 			}
 
+			if strictEncoding && !isRebuilding(ctx) {
+				_, hasDecoder := decoders[typeKey]
+				_, isOpaqueOK := opaqueDecodingTypes[typeKey]
+				if !hasDecoder && !isOpaqueOK {
+					panic(makeStrictEncodingError(details.OriginalTypeName))
+				}
+			}
+
 			// That's all we can get.
 		}
 		// If there is a detail payload, encode it.
 		details.FullDetails = encodeAsAny(ctx, err, payload)
 	}
 
-	return EncodedError{
-		Error: &errorspb.EncodedError_Wrapper{
-			Wrapper: &errorspb.EncodedWrapper{
-				Cause:       EncodeError(ctx, cause),
-				Message:     msg,
-				Details:     details,
-				MessageType: errorspb.MessageType(messageType),
-			},
-		},
+	return &errorspb.EncodedWrapper{
+		Message:     msg,
+		Details:     details,
+		MessageType: errorspb.MessageType(messageType),
 	}
 }
 
@@ -219,23 +311,22 @@ func getTypeDetails(
 	err error, onlyFamily bool,
 ) (origTypeName string, typeKeyFamily string, typeKeyExtension string) {
 	// If we have received an error of type not known locally,
-	// we still know its type name. Return that.
+	// we still know its type name. Return that. The family name
+	// is normalized through the migration registry, in case the
+	// sender used a type name that was migrated locally.
 	switch t := err.(type) {
 	case *opaqueLeaf:
-		return t.details.OriginalTypeName, t.details.ErrorTypeMark.FamilyName, t.details.ErrorTypeMark.Extension
+		return t.details.OriginalTypeName, migratedFamily(t.details.ErrorTypeMark.FamilyName), t.details.ErrorTypeMark.Extension
 	case *opaqueLeafCauses:
-		return t.details.OriginalTypeName, t.details.ErrorTypeMark.FamilyName, t.details.ErrorTypeMark.Extension
+		return t.details.OriginalTypeName, migratedFamily(t.details.ErrorTypeMark.FamilyName), t.details.ErrorTypeMark.Extension
 	case *opaqueWrapper:
-		return t.details.OriginalTypeName, t.details.ErrorTypeMark.FamilyName, t.details.ErrorTypeMark.Extension
+		return t.details.OriginalTypeName, migratedFamily(t.details.ErrorTypeMark.FamilyName), t.details.ErrorTypeMark.Extension
 	}
 
 	// Compute the full error name, for reporting and printing details.
 	tn := getFullTypeName(err)
 	// Compute a family name, used to find decoders and to compare error identities.
-	fm := tn
-	if prevKey, ok := backwardRegistry[TypeKey(tn)]; ok {
-		fm = string(prevKey)
-	}
+	fm := migratedFamily(tn)
 
 	if onlyFamily {
 		return tn, fm, ""
@@ -305,6 +396,19 @@ func GetTypeMark(err error) errorspb.ErrorTypeMark {
 	return errorspb.ErrorTypeMark{FamilyName: familyName, Extension: extension}
 }
 
+// GetOriginalTypeName retrieves the original Go type name of the
+// outermost layer of the given error, including its package path.
+// For errors decoded from the network whose type is not known
+// locally, this is the type name that was recorded when the error
+// was encoded. The boolean return is false if err is nil.
+func GetOriginalTypeName(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+	typeName, _, _ := getTypeDetails(err, true /*onlyFamily*/)
+	return typeName, true
+}
+
 // RegisterLeafEncoder can be used to register new leaf error types to
 // the library. Registered types will be encoded using their own
 // Go type when an error is encoded. Wrappers that have not been
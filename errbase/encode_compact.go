@@ -0,0 +1,271 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errorspb"
+	"github.com/gogo/protobuf/types"
+)
+
+// The first byte of the compact encoding identifies the format of the
+// remainder.
+const (
+	// compactFormatProto indicates that the remainder is the protobuf
+	// encoding of the EncodedError.
+	compactFormatProto byte = 0
+	// compactFormatChain indicates that the remainder is the compact
+	// encoding of a chain of wrappers around a single leaf.
+	compactFormatChain byte = 1
+)
+
+// Flags describing the fields present in a compact layer encoding.
+const (
+	compactHasFamilyName byte = 1 << iota
+	compactHasExtension
+	compactHasFullDetails
+)
+
+// EncodeCompact encodes an error like EncodeError, and serializes the
+// result into a byte slice. For the common case of a chain of
+// wrappers around a single leaf error, this uses a compact encoding
+// which is smaller than the protobuf encoding of EncodedError. Other
+// errors, e.g. multi-errors, are serialized as the protobuf encoding
+// of EncodedError. In both cases, the representation is lossless: the
+// error can be reconstructed with DecodeCompact(). A nil error is
+// encoded as an unset EncodedError, and decoded as nil.
+func EncodeCompact(ctx context.Context, err error) []byte {
+	if err == nil {
+		return []byte{compactFormatProto}
+	}
+	enc := EncodeError(ctx, err)
+	if b, ok := appendCompactChain([]byte{compactFormatChain}, &enc); ok {
+		return b
+	}
+	b, mErr := enc.Marshal()
+	if mErr != nil {
+		// Marshaling a well-formed EncodedError cannot fail.
+		panic(mErr)
+	}
+	return append([]byte{compactFormatProto}, b...)
+}
+
+// DecodeCompact decodes an error serialized with EncodeCompact. The
+// second return value is non-nil if the payload is malformed.
+func DecodeCompact(ctx context.Context, data []byte) (error, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty compact error encoding")
+	}
+	var enc EncodedError
+	switch data[0] {
+	case compactFormatProto:
+		if err := enc.Unmarshal(data[1:]); err != nil {
+			return nil, err
+		}
+	case compactFormatChain:
+		d := compactDecoder{data: data[1:]}
+		enc = d.chain()
+		if d.err == nil && len(d.data) > 0 {
+			d.err = fmt.Errorf("trailing bytes in compact error encoding")
+		}
+		if d.err != nil {
+			return nil, d.err
+		}
+	default:
+		return nil, fmt.Errorf("unknown compact error encoding format: %d", data[0])
+	}
+	if !enc.IsSet() {
+		return nil, nil
+	}
+	return DecodeError(ctx, enc), nil
+}
+
+// appendCompactChain appends the compact encoding of enc to b. It
+// returns false if enc is not a chain of wrappers around a leaf
+// without multi-error causes.
+//
+// The encoding is the number of wrappers, then each wrapper from the
+// outermost to the innermost, then the leaf.
+func appendCompactChain(b []byte, enc *EncodedError) ([]byte, bool) {
+	var wrappers []*errorspb.EncodedWrapper
+	c := enc
+	for {
+		w := c.GetWrapper()
+		if w == nil {
+			break
+		}
+		wrappers = append(wrappers, w)
+		c = &w.Cause
+	}
+	leaf := c.GetLeaf()
+	if leaf == nil || len(leaf.MultierrorCauses) > 0 {
+		return nil, false
+	}
+	b = binary.AppendUvarint(b, uint64(len(wrappers)))
+	for _, w := range wrappers {
+		b = binary.AppendUvarint(b, uint64(w.MessageType))
+		b = appendCompactLayer(b, w.Message, &w.Details)
+	}
+	return appendCompactLayer(b, leaf.Message, &leaf.Details), true
+}
+
+// appendCompactLayer appends the encoding of a single layer to b: a
+// byte of flags, the message, the original type name, then the
+// optional fields indicated by the flags and the safe details. The
+// family name is omitted in the common case where it is equal to the
+// original type name.
+func appendCompactLayer(b []byte, msg string, d *errorspb.EncodedErrorDetails) []byte {
+	var flags byte
+	if d.ErrorTypeMark.FamilyName != d.OriginalTypeName {
+		flags |= compactHasFamilyName
+	}
+	if d.ErrorTypeMark.Extension != "" {
+		flags |= compactHasExtension
+	}
+	if d.FullDetails != nil {
+		flags |= compactHasFullDetails
+	}
+	b = append(b, flags)
+	b = appendCompactString(b, msg)
+	b = appendCompactString(b, d.OriginalTypeName)
+	if flags&compactHasFamilyName != 0 {
+		b = appendCompactString(b, d.ErrorTypeMark.FamilyName)
+	}
+	if flags&compactHasExtension != 0 {
+		b = appendCompactString(b, d.ErrorTypeMark.Extension)
+	}
+	b = binary.AppendUvarint(b, uint64(len(d.ReportablePayload)))
+	for _, s := range d.ReportablePayload {
+		b = appendCompactString(b, s)
+	}
+	if flags&compactHasFullDetails != 0 {
+		b = appendCompactString(b, d.FullDetails.TypeUrl)
+		b = appendCompactString(b, string(d.FullDetails.Value))
+	}
+	return b
+}
+
+func appendCompactString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// compactDecoder decodes the compact encoding produced by
+// appendCompactChain. The first decoding error is retained in err,
+// after which the decoding functions return zero values.
+type compactDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *compactDecoder) chain() EncodedError {
+	n := d.uvarint()
+	if d.err == nil && n > uint64(len(d.data)) {
+		// Each wrapper takes at least one byte.
+		d.err = fmt.Errorf("invalid number of wrappers in compact error encoding: %d", n)
+	}
+	if d.err != nil {
+		return EncodedError{}
+	}
+	wrappers := make([]*errorspb.EncodedWrapper, n)
+	for i := range wrappers {
+		w := &errorspb.EncodedWrapper{}
+		w.MessageType = errorspb.MessageType(d.uvarint())
+		w.Message, w.Details = d.layer()
+		wrappers[i] = w
+	}
+	leaf := &errorspb.EncodedErrorLeaf{}
+	leaf.Message, leaf.Details = d.layer()
+	if d.err != nil {
+		return EncodedError{}
+	}
+	enc := EncodedError{Error: &errorspb.EncodedError_Leaf{Leaf: leaf}}
+	for i := len(wrappers) - 1; i >= 0; i-- {
+		wrappers[i].Cause = enc
+		enc = EncodedError{Error: &errorspb.EncodedError_Wrapper{Wrapper: wrappers[i]}}
+	}
+	return enc
+}
+
+func (d *compactDecoder) layer() (msg string, details errorspb.EncodedErrorDetails) {
+	flags := d.byte()
+	msg = d.string()
+	details.OriginalTypeName = d.string()
+	details.ErrorTypeMark.FamilyName = details.OriginalTypeName
+	if flags&compactHasFamilyName != 0 {
+		details.ErrorTypeMark.FamilyName = d.string()
+	}
+	if flags&compactHasExtension != 0 {
+		details.ErrorTypeMark.Extension = d.string()
+	}
+	if n := d.uvarint(); n > 0 {
+		if n > uint64(len(d.data)) {
+			// Each string takes at least one byte.
+			d.fail()
+			return
+		}
+		details.ReportablePayload = make([]string, n)
+		for i := range details.ReportablePayload {
+			details.ReportablePayload[i] = d.string()
+		}
+	}
+	if flags&compactHasFullDetails != 0 {
+		details.FullDetails = &types.Any{TypeUrl: d.string(), Value: []byte(d.string())}
+	}
+	return msg, details
+}
+
+func (d *compactDecoder) byte() byte {
+	if d.err != nil || len(d.data) == 0 {
+		d.fail()
+		return 0
+	}
+	c := d.data[0]
+	d.data = d.data[1:]
+	return c
+}
+
+func (d *compactDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *compactDecoder) string() string {
+	n := d.uvarint()
+	if d.err != nil || n > uint64(len(d.data)) {
+		d.fail()
+		return ""
+	}
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s
+}
+
+func (d *compactDecoder) fail() {
+	if d.err == nil {
+		d.err = fmt.Errorf("truncated compact error encoding")
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/gogo/protobuf/proto"
+	pkgErr "github.com/pkg/errors"
+)
+
+// compactTestErr is an error type without registered encoder.
+type compactTestErr struct{ msg string }
+
+func (e *compactTestErr) Error() string { return e.msg }
+
+func TestEncodeCompactRoundTrip(t *testing.T) {
+	tt := testutils.T{T: t}
+	ctx := context.Background()
+
+	testData := []error{
+		goErr.New("hello"),
+		fmt.Errorf("woo: %w", goErr.New("hello")),
+		pkgErr.Wrap(pkgErr.New("hello"), "woo"),
+		errutil.Newf("hello %s", "world"),
+		&compactTestErr{msg: "hello"},
+		fmt.Errorf("%w and %w", goErr.New("hello"), pkgErr.New("world")),
+		goErr.Join(goErr.New("hello"), pkgErr.WithStack(goErr.New("world"))),
+	}
+
+	for _, err := range testData {
+		tt.Run(err.Error(), func(tt testutils.T) {
+			b := errbase.EncodeCompact(ctx, err)
+			newErr, derr := errbase.DecodeCompact(ctx, b)
+			tt.AssertEqual(derr, nil)
+
+			// The round-trip is lossless.
+			enc := errbase.EncodeError(ctx, err)
+			newEnc := errbase.EncodeError(ctx, newErr)
+			tt.Check(proto.Equal(&newEnc, &enc))
+
+			tt.CheckStringEqual(newErr.Error(), err.Error())
+			tt.Check(markers.Is(newErr, err))
+			tt.CheckStringEqual(fmt.Sprintf("%+v", newErr),
+				fmt.Sprintf("%+v", errbase.DecodeError(ctx, enc)))
+		})
+	}
+
+	newErr, derr := errbase.DecodeCompact(ctx, errbase.EncodeCompact(ctx, nil))
+	tt.Check(newErr == nil)
+	tt.CheckEqual(derr, nil)
+}
+
+func TestEncodeCompactSize(t *testing.T) {
+	tt := testutils.T{T: t}
+	ctx := context.Background()
+
+	for _, err := range []error{
+		goErr.New("hello"),
+		fmt.Errorf("woo: %w", goErr.New("hello")),
+		errutil.New("hello"),
+	} {
+		enc := errbase.EncodeError(ctx, err)
+		protoSize := enc.Size()
+		compactSize := len(errbase.EncodeCompact(ctx, err))
+		t.Logf("%q: protobuf %d bytes, compact %d bytes", err, protoSize, compactSize)
+		tt.Check(compactSize < protoSize)
+	}
+}
+
+func TestDecodeCompactErrors(t *testing.T) {
+	tt := testutils.T{T: t}
+	ctx := context.Background()
+
+	b := errbase.EncodeCompact(ctx, fmt.Errorf("woo: %w", goErr.New("hello")))
+	for _, data := range [][]byte{
+		nil,
+		{42},
+		{0, 42},
+		b[:len(b)-1],
+		append(b[:len(b):len(b)], 0),
+		{1, 255, 255, 255, 255, 1},
+	} {
+		_, err := errbase.DecodeCompact(ctx, data)
+		tt.Check(err != nil)
+	}
+}
+
+func BenchmarkEncodeSimpleError(b *testing.B) {
+	ctx := context.Background()
+	err := fmt.Errorf("woo: %w", goErr.New("hello"))
+
+	b.Run("protobuf", func(b *testing.B) {
+		var size int
+		for i := 0; i < b.N; i++ {
+			enc := errbase.EncodeError(ctx, err)
+			data, _ := enc.Marshal()
+			size = len(data)
+			var newEnc errbase.EncodedError
+			_ = newEnc.Unmarshal(data)
+			_ = errbase.DecodeError(ctx, newEnc)
+		}
+		b.ReportMetric(float64(size), "bytes/err")
+	})
+
+	b.Run("compact", func(b *testing.B) {
+		var size int
+		for i := 0; i < b.N; i++ {
+			data := errbase.EncodeCompact(ctx, err)
+			size = len(data)
+			_, _ = errbase.DecodeCompact(ctx, data)
+		}
+		b.ReportMetric(float64(size), "bytes/err")
+	})
+}
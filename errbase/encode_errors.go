@@ -0,0 +1,50 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors/errorspb"
+)
+
+// EncodedErrors is the type of an encoded (and protobuf-encodable)
+// list of errors.
+type EncodedErrors = errorspb.EncodedErrors
+
+// EncodeErrors encodes a list of errors, for example the per-item
+// results of a batch operation. The positions of the errors are
+// preserved: nil errors are encoded as an unset EncodedError.
+func EncodeErrors(ctx context.Context, errs []error) EncodedErrors {
+	res := EncodedErrors{Errors: make([]EncodedError, len(errs))}
+	for i, err := range errs {
+		if err != nil {
+			res.Errors[i] = EncodeError(ctx, err)
+		}
+	}
+	return res
+}
+
+// DecodeErrors decodes a list of errors encoded with EncodeErrors.
+// The unset entries are decoded as nil errors.
+func DecodeErrors(ctx context.Context, enc EncodedErrors) []error {
+	res := make([]error, len(enc.Errors))
+	for i := range enc.Errors {
+		if enc.Errors[i].IsSet() {
+			res[i] = DecodeError(ctx, enc.Errors[i])
+		}
+	}
+	return res
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase_test
+
+import (
+	"context"
+	goErr "errors"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/testutils"
+	pkgErr "github.com/pkg/errors"
+)
+
+func TestEncodeErrors(t *testing.T) {
+	tt := testutils.T{T: t}
+	ctx := context.Background()
+
+	errs := []error{
+		nil,
+		goErr.New("hello"),
+		nil,
+		nil,
+		pkgErr.WithMessage(goErr.New("world"), "woo"),
+		nil,
+	}
+	enc := errbase.EncodeErrors(ctx, errs)
+	tt.CheckEqual(len(enc.Errors), len(errs))
+
+	// Simulate a network transfer.
+	b, err := enc.Marshal()
+	tt.AssertEqual(err, nil)
+	var newEnc errbase.EncodedErrors
+	tt.AssertEqual(newEnc.Unmarshal(b), nil)
+
+	// The positions of nil errors are preserved.
+	decoded := errbase.DecodeErrors(ctx, newEnc)
+	tt.Assert(len(decoded) == len(errs))
+	for i := range errs {
+		if errs[i] == nil {
+			tt.CheckEqual(decoded[i], nil)
+		} else {
+			tt.CheckDeepEqual(decoded[i], errs[i])
+		}
+	}
+
+	// Empty lists round-trip too.
+	tt.CheckEqual(len(errbase.DecodeErrors(ctx, errbase.EncodeErrors(ctx, nil))), 0)
+}
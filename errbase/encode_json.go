@@ -0,0 +1,205 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errorspb"
+	"github.com/gogo/protobuf/types"
+)
+
+// MarshalJSON produces a compact JSON representation of an encoded
+// error, suitable for transporting errors through logs or other
+// systems that do not carry protobufs. The representation is
+// lossless: the result of UnmarshalJSON() can be passed to
+// DecodeError() to reconstruct the error.
+//
+// The JSON field names mirror those of the protobuf definition and
+// are stable across versions. Full details payloads are included as
+// their type URL and base64-encoded protobuf bytes.
+func MarshalJSON(enc EncodedError) ([]byte, error) {
+	j, err := toJSONError(&enc)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON is the inverse of MarshalJSON.
+func UnmarshalJSON(data []byte) (EncodedError, error) {
+	var j jsonError
+	if err := json.Unmarshal(data, &j); err != nil {
+		return EncodedError{}, err
+	}
+	return fromJSONError(&j)
+}
+
+// jsonError is the JSON representation of errorspb.EncodedError.
+// Exactly one of Leaf and Wrapper is set.
+type jsonError struct {
+	Leaf          *jsonLeaf    `json:"leaf,omitempty"`
+	Wrapper       *jsonWrapper `json:"wrapper,omitempty"`
+	FormatVersion uint32       `json:"format_version,omitempty"`
+}
+
+// jsonLeaf is the JSON representation of errorspb.EncodedErrorLeaf.
+type jsonLeaf struct {
+	Message          string       `json:"message"`
+	Details          jsonDetails  `json:"details"`
+	MultierrorCauses []*jsonError `json:"multierror_causes,omitempty"`
+}
+
+// jsonWrapper is the JSON representation of errorspb.EncodedWrapper.
+type jsonWrapper struct {
+	Cause       jsonError   `json:"cause"`
+	Message     string      `json:"message,omitempty"`
+	Details     jsonDetails `json:"details"`
+	MessageType string      `json:"message_type,omitempty"`
+}
+
+// jsonDetails is the JSON representation of
+// errorspb.EncodedErrorDetails.
+type jsonDetails struct {
+	OriginalTypeName  string        `json:"original_type_name"`
+	ErrorTypeMark     jsonTypeMark  `json:"error_type_mark"`
+	ReportablePayload []string      `json:"reportable_payload,omitempty"`
+	FullDetails       *jsonAnyBytes `json:"full_details,omitempty"`
+}
+
+// jsonTypeMark is the JSON representation of errorspb.ErrorTypeMark.
+type jsonTypeMark struct {
+	FamilyName string `json:"family_name"`
+	Extension  string `json:"extension,omitempty"`
+}
+
+// jsonAnyBytes is the JSON representation of a protobuf Any. The
+// value is base64-encoded by encoding/json.
+type jsonAnyBytes struct {
+	TypeURL string `json:"type_url"`
+	Value   []byte `json:"value,omitempty"`
+}
+
+func toJSONError(enc *EncodedError) (*jsonError, error) {
+	switch e := enc.Error.(type) {
+	case *errorspb.EncodedError_Leaf:
+		l := &jsonLeaf{
+			Message: e.Leaf.Message,
+			Details: toJSONDetails(&e.Leaf.Details),
+		}
+		for _, c := range e.Leaf.MultierrorCauses {
+			jc, err := toJSONError(c)
+			if err != nil {
+				return nil, err
+			}
+			l.MultierrorCauses = append(l.MultierrorCauses, jc)
+		}
+		return &jsonError{Leaf: l, FormatVersion: enc.FormatVersion}, nil
+
+	case *errorspb.EncodedError_Wrapper:
+		cause, err := toJSONError(&e.Wrapper.Cause)
+		if err != nil {
+			return nil, err
+		}
+		w := &jsonWrapper{
+			Cause:   *cause,
+			Message: e.Wrapper.Message,
+			Details: toJSONDetails(&e.Wrapper.Details),
+		}
+		if e.Wrapper.MessageType != errorspb.MessageType_PREFIX {
+			w.MessageType = e.Wrapper.MessageType.String()
+		}
+		return &jsonError{Wrapper: w, FormatVersion: enc.FormatVersion}, nil
+	}
+	return nil, fmt.Errorf("unknown encoded error type: %T", enc.Error)
+}
+
+func toJSONDetails(d *errorspb.EncodedErrorDetails) jsonDetails {
+	jd := jsonDetails{
+		OriginalTypeName: d.OriginalTypeName,
+		ErrorTypeMark: jsonTypeMark{
+			FamilyName: d.ErrorTypeMark.FamilyName,
+			Extension:  d.ErrorTypeMark.Extension,
+		},
+		ReportablePayload: d.ReportablePayload,
+	}
+	if d.FullDetails != nil {
+		jd.FullDetails = &jsonAnyBytes{TypeURL: d.FullDetails.TypeUrl, Value: d.FullDetails.Value}
+	}
+	return jd
+}
+
+func fromJSONError(j *jsonError) (EncodedError, error) {
+	switch {
+	case j.Leaf != nil && j.Wrapper == nil:
+		l := &errorspb.EncodedErrorLeaf{
+			Message: j.Leaf.Message,
+			Details: fromJSONDetails(&j.Leaf.Details),
+		}
+		for _, jc := range j.Leaf.MultierrorCauses {
+			if jc == nil {
+				return EncodedError{}, fmt.Errorf("missing multi-error cause")
+			}
+			c, err := fromJSONError(jc)
+			if err != nil {
+				return EncodedError{}, err
+			}
+			l.MultierrorCauses = append(l.MultierrorCauses, &c)
+		}
+		return EncodedError{
+			Error:         &errorspb.EncodedError_Leaf{Leaf: l},
+			FormatVersion: j.FormatVersion,
+		}, nil
+
+	case j.Wrapper != nil && j.Leaf == nil:
+		cause, err := fromJSONError(&j.Wrapper.Cause)
+		if err != nil {
+			return EncodedError{}, err
+		}
+		w := &errorspb.EncodedWrapper{
+			Cause:   cause,
+			Message: j.Wrapper.Message,
+			Details: fromJSONDetails(&j.Wrapper.Details),
+		}
+		if j.Wrapper.MessageType != "" {
+			mt, ok := errorspb.MessageType_value[j.Wrapper.MessageType]
+			if !ok {
+				return EncodedError{}, fmt.Errorf("unknown message type: %q", j.Wrapper.MessageType)
+			}
+			w.MessageType = errorspb.MessageType(mt)
+		}
+		return EncodedError{
+			Error:         &errorspb.EncodedError_Wrapper{Wrapper: w},
+			FormatVersion: j.FormatVersion,
+		}, nil
+	}
+	return EncodedError{}, fmt.Errorf("encoded error must contain exactly one of leaf or wrapper")
+}
+
+func fromJSONDetails(jd *jsonDetails) errorspb.EncodedErrorDetails {
+	d := errorspb.EncodedErrorDetails{
+		OriginalTypeName: jd.OriginalTypeName,
+		ErrorTypeMark: errorspb.ErrorTypeMark{
+			FamilyName: jd.ErrorTypeMark.FamilyName,
+			Extension:  jd.ErrorTypeMark.Extension,
+		},
+		ReportablePayload: jd.ReportablePayload,
+	}
+	if jd.FullDetails != nil {
+		d.FullDetails = &types.Any{TypeUrl: jd.FullDetails.TypeURL, Value: jd.FullDetails.Value}
+	}
+	return d
+}
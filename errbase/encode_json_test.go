@@ -0,0 +1,123 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errorspb"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	pkgErr "github.com/pkg/errors"
+)
+
+func TestMarshalJSONGolden(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	payload, err := types.MarshalAny(&errorspb.StringsPayload{Details: []string{"a", "b"}})
+	tt.AssertEqual(err, nil)
+
+	leaf := errorspb.EncodedError{Error: &errorspb.EncodedError_Leaf{Leaf: &errorspb.EncodedErrorLeaf{
+		Message: "hello",
+		Details: errorspb.EncodedErrorDetails{
+			OriginalTypeName:  "pkg/*pkg.leaf",
+			ErrorTypeMark:     errorspb.ErrorTypeMark{FamilyName: "pkg/*pkg.leaf", Extension: "ext"},
+			ReportablePayload: []string{"safe"},
+			FullDetails:       payload,
+		},
+	}}}
+	multi := errorspb.EncodedError{Error: &errorspb.EncodedError_Leaf{Leaf: &errorspb.EncodedErrorLeaf{
+		Message: "hello hello",
+		Details: errorspb.EncodedErrorDetails{
+			OriginalTypeName: "pkg/*pkg.multi",
+			ErrorTypeMark:    errorspb.ErrorTypeMark{FamilyName: "pkg/*pkg.multi"},
+		},
+		MultierrorCauses: []*errorspb.EncodedError{&leaf, &leaf},
+	}}}
+	enc := errorspb.EncodedError{Error: &errorspb.EncodedError_Wrapper{Wrapper: &errorspb.EncodedWrapper{
+		Cause:   multi,
+		Message: "woo",
+		Details: errorspb.EncodedErrorDetails{
+			OriginalTypeName: "pkg/*pkg.wrapper",
+			ErrorTypeMark:    errorspb.ErrorTypeMark{FamilyName: "pkg/*pkg.wrapper"},
+		},
+		MessageType: errorspb.MessageType_FULL_MESSAGE,
+	}}}
+
+	j, err := errbase.MarshalJSON(enc)
+	tt.AssertEqual(err, nil)
+
+	const leafJSON = `{"leaf":{"message":"hello","details":{"original_type_name":"pkg/*pkg.leaf",` +
+		`"error_type_mark":{"family_name":"pkg/*pkg.leaf","extension":"ext"},"reportable_payload":["safe"],` +
+		`"full_details":{"type_url":"type.googleapis.com/cockroach.errorspb.StringsPayload","value":"CgFhCgFi"}}}}`
+	tt.CheckStringEqual(string(j), `{"wrapper":{"cause":{"leaf":{"message":"hello hello",`+
+		`"details":{"original_type_name":"pkg/*pkg.multi","error_type_mark":{"family_name":"pkg/*pkg.multi"}},`+
+		`"multierror_causes":[`+leafJSON+`,`+leafJSON+`]}},"message":"woo",`+
+		`"details":{"original_type_name":"pkg/*pkg.wrapper","error_type_mark":{"family_name":"pkg/*pkg.wrapper"}},`+
+		`"message_type":"FULL_MESSAGE"}}`)
+
+	dec, err := errbase.UnmarshalJSON(j)
+	tt.AssertEqual(err, nil)
+	tt.Check(proto.Equal(&dec, &enc))
+}
+
+func TestMarshalJSONRoundTrip(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	testData := []error{
+		goErr.New("hello"),
+		fmt.Errorf("woo: %w", goErr.New("hello")),
+		pkgErr.Wrap(pkgErr.New("hello"), "woo"),
+		fmt.Errorf("%w and %w", goErr.New("hello"), pkgErr.New("world")),
+		goErr.Join(goErr.New("hello"), pkgErr.WithStack(goErr.New("world"))),
+	}
+
+	for _, err := range testData {
+		tt.Run(err.Error(), func(tt testutils.T) {
+			enc := errbase.EncodeError(context.Background(), err)
+			j, jerr := errbase.MarshalJSON(enc)
+			tt.AssertEqual(jerr, nil)
+			newEnc, jerr := errbase.UnmarshalJSON(j)
+			tt.AssertEqual(jerr, nil)
+			tt.Check(proto.Equal(&newEnc, &enc))
+
+			newErr := errbase.DecodeError(context.Background(), newEnc)
+			tt.CheckStringEqual(newErr.Error(), err.Error())
+			tt.Check(markers.Is(newErr, err))
+			tt.CheckStringEqual(fmt.Sprintf("%+v", newErr), fmt.Sprintf("%+v", errbase.DecodeError(context.Background(), enc)))
+		})
+	}
+}
+
+func TestUnmarshalJSONErrors(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	for _, s := range []string{
+		``,
+		`{}`,
+		`{"leaf":{"message":"a"},"wrapper":{"cause":{"leaf":{}}}}`,
+		`{"wrapper":{"cause":{}}}`,
+		`{"wrapper":{"cause":{"leaf":{}},"message_type":"UNKNOWN"}}`,
+	} {
+		_, err := errbase.UnmarshalJSON([]byte(s))
+		tt.Check(err != nil)
+	}
+}
@@ -15,10 +15,16 @@
 package errbase_test
 
 import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
 	"github.com/cockroachdb/errors/testutils"
+	"github.com/gogo/protobuf/proto"
 )
 
 type myE struct{ marker string }
@@ -44,3 +50,68 @@ func TestTypeName(t *testing.T) {
 	tt.CheckEqual(tn1.FamilyName, tn2.FamilyName)
 	tt.Check(tn1.Extension != tn2.Extension)
 }
+
+func TestGetOriginalTypeName(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	const expected = "github.com/cockroachdb/errors/errbase_test/*errbase_test.myError"
+
+	// A local error reports its Go type.
+	origErr := &myError{val: 123}
+	typeName, ok := errbase.GetOriginalTypeName(origErr)
+	tt.Check(ok)
+	tt.CheckStringEqual(typeName, expected)
+
+	// After a network round trip, the error is opaque but its
+	// original type name is preserved.
+	newErr := network(t, origErr)
+	tt.Check(reflect.TypeOf(newErr) != reflect.TypeOf(origErr))
+	typeName, ok = errbase.GetOriginalTypeName(newErr)
+	tt.Check(ok)
+	tt.CheckStringEqual(typeName, expected)
+
+	_, ok = errbase.GetOriginalTypeName(nil)
+	tt.Check(!ok)
+}
+
+func TestStrictEncoding(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	errbase.SetStrictEncoding(true)
+	defer errbase.SetStrictEncoding(false)
+
+	encodePanics := func(err error) (panicked bool) {
+		defer func() {
+			if r := recover(); r != nil {
+				panicked = true
+			}
+		}()
+		_ = errbase.EncodeError(context.Background(), err)
+		return false
+	}
+
+	// Standard errors are allowed.
+	tt.Check(!encodePanics(goErr.New("hello")))
+	tt.Check(!encodePanics(fmt.Errorf("woo: %w", goErr.New("hello"))))
+	tt.Check(!encodePanics(errutil.Wrap(goErr.New("hello"), "woo")))
+
+	// Errors received from the network are re-encoded as-is.
+	errbase.SetStrictEncoding(false)
+	remote := network(t, &myError{val: 123})
+	errbase.SetStrictEncoding(true)
+	tt.Check(!encodePanics(remote))
+
+	// Custom types without a decoder are flagged, also when they
+	// are nested inside other errors.
+	tt.Check(encodePanics(&myError{val: 123}))
+	tt.Check(encodePanics(errutil.Wrap(&myError{val: 123}, "woo")))
+	tt.Check(encodePanics(&fooWrap{cause: goErr.New("hello")}))
+
+	// Once a decoder is registered, the type is allowed.
+	tn := errbase.GetTypeKey((*myError)(nil))
+	errbase.RegisterLeafDecoder(tn, func(_ context.Context, _ string, _ []string, _ proto.Message) error {
+		return &myError{}
+	})
+	defer errbase.RegisterLeafDecoder(tn, nil)
+	tt.Check(!encodePanics(&myError{val: 123}))
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errorspb"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/gogo/protobuf/proto"
+)
+
+// appendField appends a length-delimited field to a protobuf
+// encoding.
+func appendField(b []byte, fieldNum int, payload []byte) []byte {
+	b = append(b, proto.EncodeVarint(uint64(fieldNum<<3|2))...)
+	b = append(b, proto.EncodeVarint(uint64(len(payload)))...)
+	return append(b, payload...)
+}
+
+// appendVarintField appends a varint field to a protobuf encoding.
+func appendVarintField(b []byte, fieldNum int, v uint64) []byte {
+	b = append(b, proto.EncodeVarint(uint64(fieldNum<<3))...)
+	return append(b, proto.EncodeVarint(v)...)
+}
+
+func TestEncodedFormatVersion(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := fmt.Errorf("woo: %w", goErr.Join(goErr.New("hello"), goErr.New("world")))
+	enc := errbase.EncodeError(context.Background(), err)
+
+	// The version is stamped on the outermost layer only.
+	tt.CheckEqual(enc.FormatVersion, errbase.EncodedFormatVersion)
+	cause := enc.GetWrapper().Cause
+	tt.CheckEqual(cause.FormatVersion, uint32(0))
+	for _, c := range cause.GetLeaf().MultierrorCauses {
+		tt.CheckEqual(c.FormatVersion, uint32(0))
+	}
+
+	// The version survives a protobuf round trip.
+	b, merr := proto.Marshal(&enc)
+	tt.AssertEqual(merr, nil)
+	var newEnc errbase.EncodedError
+	tt.AssertEqual(proto.Unmarshal(b, &newEnc), nil)
+	tt.Check(proto.Equal(&newEnc, &enc))
+}
+
+func TestDecodeOlderFormatVersion(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	// An error encoded before the version was introduced.
+	enc := errbase.EncodedError{Error: &errorspb.EncodedError_Leaf{Leaf: &errorspb.EncodedErrorLeaf{
+		Message: "hello",
+		Details: errorspb.EncodedErrorDetails{
+			OriginalTypeName: "errors/*errors.errorString",
+			ErrorTypeMark:    errorspb.ErrorTypeMark{FamilyName: "errors/*errors.errorString"},
+		},
+	}}}
+	b, merr := proto.Marshal(&enc)
+	tt.AssertEqual(merr, nil)
+
+	var newEnc errbase.EncodedError
+	tt.AssertEqual(proto.Unmarshal(b, &newEnc), nil)
+	tt.CheckEqual(newEnc.FormatVersion, uint32(0))
+
+	newErr := errbase.DecodeError(context.Background(), newEnc)
+	tt.CheckStringEqual(newErr.Error(), "hello")
+	tt.Check(markers.Is(newErr, goErr.New("hello")))
+}
+
+func TestDecodeNewerFormatVersion(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := fmt.Errorf("woo: %w", goErr.New("hello"))
+	enc := errbase.EncodeError(context.Background(), origErr)
+
+	// Simulate a payload produced by a newer version of the library,
+	// with additional fields at the outer level and in the cause.
+	leaf, merr := proto.Marshal(enc.GetWrapper().Cause.GetLeaf())
+	tt.AssertEqual(merr, nil)
+	leaf = appendField(leaf, 15, []byte("unknown leaf field"))
+	var cause []byte
+	cause = appendField(cause, 1, leaf)
+	cause = appendVarintField(cause, 3, 2)
+
+	w := *enc.GetWrapper()
+	w.Cause = errbase.EncodedError{}
+	wrapper, merr := proto.Marshal(&w)
+	tt.AssertEqual(merr, nil)
+	wrapper = appendField(wrapper, 1, cause)
+	wrapper = appendVarintField(wrapper, 14, 42)
+
+	var b []byte
+	b = appendField(b, 2, wrapper)
+	b = appendVarintField(b, 3, 2)
+	b = appendField(b, 15, []byte("unknown field"))
+
+	// The unknown fields are ignored.
+	var newEnc errbase.EncodedError
+	tt.AssertEqual(proto.Unmarshal(b, &newEnc), nil)
+	tt.CheckEqual(newEnc.FormatVersion, uint32(2))
+
+	newErr := errbase.DecodeError(context.Background(), newEnc)
+	tt.CheckStringEqual(newErr.Error(), origErr.Error())
+	tt.Check(markers.Is(newErr, origErr))
+}
+
+func TestDecodeUnknownLayer(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	// Simulate a payload produced by a newer version of the library
+	// with a kind of layer unknown to this version.
+	var b []byte
+	b = appendField(b, 4, []byte("unknown layer"))
+	b = appendVarintField(b, 3, 2)
+
+	var enc errbase.EncodedError
+	tt.AssertEqual(proto.Unmarshal(b, &enc), nil)
+	tt.Check(!enc.IsSet())
+
+	err := errbase.DecodeError(context.Background(), enc)
+	tt.CheckStringEqual(err.Error(), "undecodable error (format version 2)")
+	tt.CheckRegexpEqual(fmt.Sprintf("%+v", err), `^undecodable error \(format version 2\)\n\(1\)`)
+
+	_, ok := errbase.DecodeErrorAs(context.Background(), enc, goErr.New("hello"))
+	tt.Check(!ok)
+
+	// An unknown kind of layer in a cause reports the version of the
+	// outermost layer.
+	enc = errbase.EncodeError(context.Background(), fmt.Errorf("woo: %w", goErr.New("hello")))
+	w := *enc.GetWrapper()
+	w.Cause = errbase.EncodedError{}
+	wrapper, merr := proto.Marshal(&w)
+	tt.AssertEqual(merr, nil)
+	var cause []byte
+	cause = appendField(cause, 4, []byte("unknown layer"))
+	wrapper = appendField(wrapper, 1, cause)
+	b = nil
+	b = appendField(b, 2, wrapper)
+	b = appendVarintField(b, 3, 2)
+	enc = errbase.EncodedError{}
+	tt.AssertEqual(proto.Unmarshal(b, &enc), nil)
+	err = errbase.DecodeError(context.Background(), enc)
+	tt.CheckStringEqual(err.Error(), "woo: undecodable error (format version 2)")
+}
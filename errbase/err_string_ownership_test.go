@@ -95,6 +95,7 @@ func TestEncodeDecodeNewVersion(t *testing.T) {
 				MessageType: errorspb.MessageType_FULL_MESSAGE,
 			},
 		},
+		FormatVersion: errbase.EncodedFormatVersion,
 	}
 
 	tt.CheckDeepEqual(errNewEncoded, errNew)
@@ -29,6 +29,7 @@ import (
 	"io"
 	"reflect"
 	"strings"
+	"sync/atomic"
 
 	"github.com/cockroachdb/redact"
 	"github.com/kr/pretty"
@@ -48,7 +49,7 @@ import (
 //
 // Otherwise, its Error() text is printed.
 func FormatError(err error, s fmt.State, verb rune) {
-	formatErrorInternal(err, s, verb, false /* redactableOutput */)
+	formatErrorInternal(err, s, verb, false /* redactableOutput */, formatOptions{})
 }
 
 // FormatRedactableError formats an error as a safe object.
@@ -57,7 +58,21 @@ func FormatError(err error, s fmt.State, verb rune) {
 // supported, and result in a rendering that considers the entire
 // object as unsafe. For example, %q, %#v are not yet supported.
 func FormatRedactableError(err error, s redact.SafePrinter, verb rune) {
-	formatErrorInternal(err, s, verb, true /* redactable */)
+	formatErrorInternal(err, s, verb, true /* redactable */, formatOptions{})
+}
+
+// FormatRedactableErrorWithOptions is like FormatRedactableError,
+// with the rendering customized by the given options like in
+// Formattable(). This can be used to implement
+// redact.SafeFormatter.
+func FormatRedactableErrorWithOptions(
+	err error, s redact.SafePrinter, verb rune, opts ...FormatOption,
+) {
+	var o formatOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	formatErrorInternal(err, s, verb, true /* redactable */, o)
 }
 
 func init() {
@@ -70,8 +85,26 @@ func init() {
 // Formattable wraps an error into a fmt.Formatter which
 // will provide "smart" formatting even if the outer layer
 // of the error does not implement the Formatter interface.
-func Formattable(err error) fmt.Formatter {
-	return &errorFormatter{err}
+//
+// The optional FormatOption arguments customize the rendering,
+// see the documentation of each option for details.
+func Formattable(err error, opts ...FormatOption) fmt.Formatter {
+	ef := &errorFormatter{err: err}
+	for _, o := range opts {
+		o(&ef.opts)
+	}
+	return ef
+}
+
+// FormatSingleLine produces the message of the error, like Error(),
+// but using the given separator between the messages of successive
+// layers instead of ": ". Layers that do not contribute a message of
+// their own are skipped and do not produce a separator.
+func FormatSingleLine(err error, sep string) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%v", Formattable(err, FormatCauseSeparator(sep)))
 }
 
 // formatErrorInternal is the shared logic between FormatError
@@ -84,14 +117,19 @@ func Formattable(err error) fmt.Formatter {
 // combinations (in particular, %q, %#v etc), then the redactableOutput
 // argument is ignored. This limitation may be lifted in a later
 // version.
-func formatErrorInternal(err error, s fmt.State, verb rune, redactableOutput bool) {
+//
+// The opts argument carries the customizations requested via
+// Formattable().
+func formatErrorInternal(
+	err error, s fmt.State, verb rune, redactableOutput bool, opts formatOptions,
+) {
 	// Assuming this function is only called from the Format method, and given
 	// that FormatError takes precedence over Format, it cannot be called from
 	// any package that supports errors.Formatter. It is therefore safe to
 	// disregard that State may be a specific printer implementation and use one
 	// of our choice instead.
 
-	p := state{State: s, redactableOutput: redactableOutput}
+	p := state{State: s, redactableOutput: redactableOutput, opts: opts}
 
 	switch {
 	case verb == 'v' && s.Flag('+') && !s.Flag('#'):
@@ -121,7 +159,12 @@ func formatErrorInternal(err error, s fmt.State, verb rune, redactableOutput boo
 		// requested. This is because the structured output may emit
 		// arbitrary unsafe strings without redaction markers,
 		// or improperly balanced/escaped redaction markers.
-		if stringer, ok := err.(fmt.GoStringer); ok {
+		if IsSecuritySensitive(err) {
+			// The structured output would reveal the unsafe parts of
+			// the error. Print its redacted message instead.
+			p.formatRecursive(err, true /* isOutermost */, false /* withDetail */, false /* withDepth */, 0 /* depth */)
+			p.formatSingleLineOutput()
+		} else if stringer, ok := err.(fmt.GoStringer); ok {
 			io.WriteString(&p.finalBuf, stringer.GoString())
 		} else {
 			// Not a GoStringer: delegate to the pretty library.
@@ -200,13 +243,19 @@ func (s *state) formatEntries(err error) {
 	s.formatSingleLineOutput()
 	s.finalBuf.WriteString("\n(1)")
 
-	s.printEntry(s.entries[len(s.entries)-1])
+	i := len(s.entries) - 1
+	last := s.collapsedRun(i)
+	if last < i {
+		fmt.Fprintf(&s.finalBuf, "-(%d)", i-last+1)
+	}
+	s.printEntries(i, last)
 
 	// All the entries that follow are printed as follows:
 	//
 	// Wraps: (N) <details>
 	//
-	for i, j := len(s.entries)-2, 2; i >= 0; i, j = i-1, j+1 {
+	j := i - last + 2
+	for i := last - 1; i >= 0; i = last - 1 {
 		s.finalBuf.WriteByte('\n')
 		// Extra indentation starts at depth==2 because the direct
 		// children of the root error area already printed on separate
@@ -220,8 +269,12 @@ func (s *state) formatEntries(err error) {
 			}
 		}
 		fmt.Fprintf(&s.finalBuf, "Wraps: (%d)", j)
-		entry := s.entries[i]
-		s.printEntry(entry)
+		last = s.collapsedRun(i)
+		if last < i {
+			fmt.Fprintf(&s.finalBuf, "-(%d)", j+i-last)
+		}
+		s.printEntries(i, last)
+		j += i - last + 1
 	}
 
 	// At the end, we link all the (N) references to the Go type of the
@@ -232,6 +285,84 @@ func (s *state) formatEntries(err error) {
 	}
 }
 
+// collapsedRun returns the index of the innermost entry that can be
+// printed together with the entry at index i, when the
+// FormatCollapsePlainWrappers() option is enabled. This is i itself
+// if the option is not enabled or no collapsing is possible.
+//
+// Entries are collapsed when they form a chain of single causes where
+// every layer contributes no detail nor stack trace, and no layer
+// elides the message of its cause (unless the entire chain is elided
+// by an enclosing multi-cause error).
+func (s *state) collapsedRun(i int) int {
+	if !s.opts.collapsePlainWrappers {
+		return i
+	}
+	last := i
+	for last > 0 {
+		outer, inner := &s.entries[last], &s.entries[last-1]
+		if !outer.isPlain() || !inner.isPlain() || inner.elideShort != outer.elideShort ||
+			UnwrapOnce(outer.err) == nil {
+			break
+		}
+		last--
+	}
+	return last
+}
+
+// printEntries renders the entries from index first down to index
+// last into s.finalBuf. If there is more than one, they are printed
+// on one line like formatSingleLineOutput() does.
+func (s *state) printEntries(first, last int) {
+	if first == last {
+		s.printEntry(s.entries[first])
+		return
+	}
+	sep := ": "
+	if s.opts.causeSeparator != nil {
+		sep = *s.opts.causeSeparator
+	}
+	needSep := false
+	for i := first; i >= last; i-- {
+		entry := s.entries[i]
+		if len(entry.head) == 0 {
+			continue
+		}
+		if needSep {
+			s.finalBuf.WriteString(sep)
+		} else if entry.head[0] != '\n' {
+			s.finalBuf.WriteByte(' ')
+		}
+		s.writeHead(entry)
+		needSep = true
+	}
+	s.printRepeat(s.entries[first])
+}
+
+// printRepeat renders the inline annotations of the entry and the
+// number of identical causes grouped into it, if any, into
+// s.finalBuf.
+func (s *state) printRepeat(entry formatEntry) {
+	if len(entry.inline) > 0 {
+		s.finalBuf.WriteString(" [")
+		s.finalBuf.Write(bytes.Join(entry.inline, []byte(", ")))
+		s.finalBuf.WriteByte(']')
+	}
+	if entry.repeat > 0 {
+		fmt.Fprintf(&s.finalBuf, " (x%d)", entry.repeat+1)
+	}
+}
+
+// writeHead writes the head of the entry into s.finalBuf, escaping it
+// if necessary.
+func (s *state) writeHead(entry formatEntry) {
+	if !s.redactableOutput || entry.redactable {
+		s.finalBuf.Write(entry.head)
+	} else {
+		s.finalBuf.Write([]byte(redact.EscapeBytes(entry.head)))
+	}
+}
+
 // printEntry renders the entry given as argument
 // into s.finalBuf.
 //
@@ -264,6 +395,7 @@ func (s *state) printEntry(entry formatEntry) {
 			}
 		}
 	}
+	s.printRepeat(entry)
 	if len(entry.details) > 0 {
 		if len(entry.head) == 0 {
 			if entry.details[0] != '\n' {
@@ -285,10 +417,18 @@ func (s *state) printEntry(entry formatEntry) {
 		}
 	}
 	if entry.stackTrace != nil {
+		st := entry.stackTrace
+		hidden := 0
+		if max := s.opts.maxStackFrames; max > 0 && len(st) > max {
+			st, hidden = st[:max], len(st)-max
+		}
 		s.finalBuf.WriteString("\n  -- stack trace:")
 		s.finalBuf.WriteString(strings.ReplaceAll(
-			fmt.Sprintf("%+v", entry.stackTrace),
+			fmt.Sprintf("%+v", st),
 			"\n", string(detailSep)))
+		if hidden > 0 {
+			fmt.Fprintf(&s.finalBuf, "%s... (%d more frames)", detailSep, hidden)
+		}
 		if entry.elidedStackTrace {
 			fmt.Fprintf(&s.finalBuf, "%s[...repeated from below...]", detailSep)
 		}
@@ -298,7 +438,8 @@ func (s *state) printEntry(entry formatEntry) {
 // formatSingleLineOutput prints the details extracted via
 // formatRecursive() through the chain of errors as if .Error() has
 // been called: it only prints the non-detail parts and prints them on
-// one line with ": " separators.
+// one line with ": " separators, or the separator configured with
+// FormatCauseSeparator().
 //
 // This function is used both when FormatError() is called indirectly
 // from .Error(), e.g. in:
@@ -316,13 +457,17 @@ func (s *state) printEntry(entry formatEntry) {
 // from redact.SafePrinter to do this, so care should be taken below
 // to properly escape markers, etc.
 func (s *state) formatSingleLineOutput() {
+	sep := ": "
+	if s.opts.causeSeparator != nil {
+		sep = *s.opts.causeSeparator
+	}
 	for i := len(s.entries) - 1; i >= 0; i-- {
 		entry := &s.entries[i]
 		if entry.elideShort {
 			continue
 		}
 		if s.finalBuf.Len() > 0 && len(entry.head) > 0 {
-			s.finalBuf.WriteString(": ")
+			s.finalBuf.WriteString(sep)
 		}
 		if len(entry.head) == 0 {
 			// shortcut, to avoid the copy below.
@@ -362,6 +507,30 @@ func (s *state) formatSingleLineOutput() {
 // persisted into the generated entries and used later to display the
 // error with increased indentation based in the depth.
 func (s *state) formatRecursive(err error, isOutermost, withDetail, withDepth bool, depth int) int {
+	if max := s.maxDepth(); withDetail && max > 0 && depth >= max {
+		// Too deep. Summarize the rest of the tree in a single entry.
+		// This is only done in the verbose rendering: the message must
+		// remain complete, as it is used for error identity and
+		// transferred over the network.
+		s.entries = append(s.entries, s.omittedEntry(err, withDepth, depth))
+		return 1
+	}
+
+	if !s.redactableOutput && IsSecuritySensitiveLayer(err) {
+		// The layer and its causes must never be rendered unredacted.
+		// Render them with redaction markers, then redact the unsafe
+		// parts. This is consistent with the Error() method of the
+		// layer.
+		start := len(s.entries)
+		s.redactableOutput = true
+		n := s.formatRecursive(err, isOutermost, withDetail, withDepth, depth)
+		s.redactableOutput = false
+		for i := start; i < len(s.entries); i++ {
+			s.entries[i].redact()
+		}
+		return n
+	}
+
 	cause := UnwrapOnce(err)
 	numChildren := 0
 	if cause != nil {
@@ -374,17 +543,47 @@ func (s *state) formatRecursive(err error, isOutermost, withDetail, withDepth bo
 		numChildren += s.formatRecursive(cause, false, withDetail, withDepth, depth+1)
 	}
 
+	if s.opts.inlineAnnotations && cause != nil && len(s.entries) > 0 {
+		if a, ok := err.(InlineAnnotator); ok {
+			// Attach the annotation to the entry of the cause, which
+			// is the last one collected, instead of producing an
+			// entry for this layer.
+			annot := a.InlineAnnotation()
+			c := &s.entries[len(s.entries)-1]
+			if s.redactableOutput {
+				c.inline = append(c.inline, []byte(annot))
+			} else {
+				c.inline = append(c.inline, []byte(annot.StripMarkers()))
+			}
+			return numChildren
+		}
+	}
+
 	causes := UnwrapMulti(err)
+	var siblings [][2]int
 	for _, c := range causes {
+		start, lastStack := len(s.entries), s.lastStack
 		// Override `withDepth` to true for all child entries ensuring they have
 		// indentation applied during formatting to distinguish them from
 		// parents.
-		numChildren += s.formatRecursive(c, false, withDetail, true, depth+1)
+		n := s.formatRecursive(c, false, withDetail, true, depth+1)
+		if s.opts.groupIdenticalCauses {
+			// If the subtree of this cause renders like that of a
+			// previous sibling, drop it and count it on the latter.
+			if k := s.findIdenticalSubtree(siblings, start); k >= 0 {
+				s.entries = s.entries[:start]
+				s.lastStack = lastStack
+				s.entries[siblings[k][1]-1].repeat++
+				continue
+			}
+			siblings = append(siblings, [2]int{start, len(s.entries)})
+		}
+		numChildren += n
 	}
 	// inserted := len(s.entries) - 1 - startChildren
 
 	// Reinitialize the state for this stage of wrapping.
-	s.wantDetail = withDetail
+	s.wantDetail = withDetail && !(s.opts.hideMaskedErrors && IsMaskingLayer(err))
 	s.needSpace = false
 	s.needNewline = 0
 	s.multiLine = false
@@ -424,7 +623,8 @@ func (s *state) formatRecursive(err error, isOutermost, withDetail, withDepth bo
 		// - when it is not the outermost wrapper, because
 		//   the Format() method is likely to be calling FormatError()
 		//   to do its job and we want to avoid an infinite recursion.
-		if !isOutermost && cause == nil {
+		_, hasStack := err.(StackTraceProvider)
+		if !isOutermost && cause == nil && !(hasStack && s.opts.hideStacks) {
 			v.Format(s, 'v')
 			if st, ok := err.(StackTraceProvider); ok {
 				// This is likely a leaf error from github/pkg/errors.
@@ -487,7 +687,7 @@ func (s *state) formatRecursive(err error, isOutermost, withDetail, withDepth bo
 
 	// If there's an embedded stack trace, also collect it.
 	// This will get either a stack from pkg/errors, or ours.
-	if !seenTrace {
+	if !seenTrace && !s.opts.hideStacks {
 		if st, ok := err.(StackTraceProvider); ok {
 			entry.stackTrace, entry.elidedStackTrace = ElideSharedStackTraceSuffix(s.lastStack, st.StackTrace())
 			s.lastStack = entry.stackTrace
@@ -501,6 +701,62 @@ func (s *state) formatRecursive(err error, isOutermost, withDetail, withDepth bo
 	return numChildren + 1
 }
 
+// redact replaces the unsafe parts of the entry by redaction
+// markers, and strips the markers. The entry must have been collected
+// for redactable output.
+func (e *formatEntry) redact() {
+	e.head = redactEntryBytes(e.head, e.redactable)
+	e.details = redactEntryBytes(e.details, e.redactable)
+	for i, b := range e.inline {
+		e.inline[i] = redactEntryBytes(b, true /* redactable */)
+	}
+	e.redactable = false
+}
+
+// redactEntryBytes implements (*formatEntry).redact. If redactable
+// is false, b is entirely unsafe.
+func redactEntryBytes(b []byte, redactable bool) []byte {
+	if len(b) == 0 {
+		return b
+	}
+	r := redact.RedactableBytes(b)
+	if !redactable {
+		r = redact.EscapeBytes(b)
+	}
+	return r.Redact().StripMarkers()
+}
+
+// findIdenticalSubtree returns the index in siblings of the range of
+// entries which is identical to the range starting at start and
+// extending to the end of s.entries, or -1 if there is none. The
+// entries are compared by type and rendered text; their stack traces
+// are not considered.
+func (s *state) findIdenticalSubtree(siblings [][2]int, start int) int {
+	sub := s.entries[start:]
+	for k, r := range siblings {
+		other := s.entries[r[0]:r[1]]
+		if len(other) != len(sub) {
+			continue
+		}
+		identical := true
+		for i := range sub {
+			a, b := &sub[i], &other[i]
+			if reflect.TypeOf(a.err) != reflect.TypeOf(b.err) ||
+				a.depth != b.depth || a.elideShort != b.elideShort ||
+				a.redactable != b.redactable ||
+				!bytes.Equal(a.head, b.head) || !bytes.Equal(a.details, b.details) ||
+				!bytes.Equal(bytes.Join(a.inline, nil), bytes.Join(b.inline, nil)) {
+				identical = false
+				break
+			}
+		}
+		if identical {
+			return k
+		}
+	}
+	return -1
+}
+
 // elideShortChildren takes a number of entries to set `elideShort` to
 // false. The reason a number of entries is needed is that we may be
 // eliding a subtree of causes in the case of a multi-cause error. In
@@ -556,6 +812,72 @@ func (s *state) collectEntry(err error, bufIsRedactable bool, withDepth bool, de
 	return entry
 }
 
+// maxFormatDepth can be configured using SetMaxFormatDepth() below.
+var maxFormatDepth atomic.Int64
+
+// SetMaxFormatDepth configures the maximum number of nested layers
+// that are rendered when formatting an error with %+v. The layers
+// beyond that depth are summarized by a single "... (N more layers
+// omitted)" entry. This protects logging against pathological error
+// chains. A value of zero or less, the default, means no limit.
+//
+// The message of the error, as returned by Error() and printed with
+// %v, is never truncated. The FormatMaxDepth() option overrides this
+// setting for a single rendering.
+func SetMaxFormatDepth(n int) {
+	maxFormatDepth.Store(int64(n))
+}
+
+// maxDepth returns the maximum depth of the verbose rendering, or
+// zero if there is no limit.
+func (s *state) maxDepth() int {
+	if s.opts.maxDepth > 0 {
+		return s.opts.maxDepth
+	}
+	return int(maxFormatDepth.Load())
+}
+
+// maxWalkedLayers is the maximum number of layers visited by the
+// walks over an error tree which must terminate on cyclic chains:
+// when counting the layers beyond the maximum format depth, and in
+// IsSecuritySensitive().
+const maxWalkedLayers = 10000
+
+// omittedEntry produces the entry that summarizes the error tree
+// rooted at err when it is too deep to be formatted.
+func (s *state) omittedEntry(err error, withDepth bool, depth int) formatEntry {
+	entry := formatEntry{err: err, redactable: s.redactableOutput}
+	if n := countLayers(err, maxWalkedLayers); n < maxWalkedLayers {
+		entry.head = []byte(fmt.Sprintf("... (%d more layers omitted)", n))
+	} else {
+		entry.head = []byte(fmt.Sprintf("... (%d+ more layers omitted)", n))
+	}
+	if withDepth {
+		entry.depth = depth
+	}
+	return entry
+}
+
+// countLayers returns the number of layers in the error tree rooted
+// at err, including the causes of multi-errors, up to limit.
+func countLayers(err error, limit int) int {
+	n := 0
+	for todo := []error{err}; len(todo) > 0 && n < limit; {
+		e := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+		if e == nil {
+			continue
+		}
+		n++
+		if cause := UnwrapOnce(e); cause != nil {
+			todo = append(todo, cause)
+		} else {
+			todo = append(todo, UnwrapMulti(e)...)
+		}
+	}
+	return n
+}
+
 // safeErrorPrinterFn is the type of a function that can take
 // over the safe printing of an error. This is used to inject special
 // cases into the formatting in errutil. We need this machinery to
@@ -570,6 +892,19 @@ func RegisterSpecialCasePrinter(fn safeErrorPrinterFn) {
 	specialCases = append(specialCases, fn)
 }
 
+// redactableOutputFilters is a list of functions applied to the
+// final rendering of errors formatted with redaction markers.
+var redactableOutputFilters []func(redact.RedactableBytes) redact.RedactableBytes
+
+// RegisterRedactableOutputFilter registers a function to apply to
+// the rendering of errors formatted with redaction markers, e.g. via
+// redact.Sprint(). This is used to inject additional redaction logic
+// from other packages. We need this machinery to prevent import
+// cycles.
+func RegisterRedactableOutputFilter(fn func(redact.RedactableBytes) redact.RedactableBytes) {
+	redactableOutputFilters = append(redactableOutputFilters, fn)
+}
+
 // formatSimple performs a best effort at extracting the details at a
 // given level of wrapping when the error object does not implement
 // the Formatter interface.
@@ -598,7 +933,11 @@ func (p *state) finishDisplay(verb rune) {
 		// If we're rendering in redactable form, then s.finalBuf contains
 		// a RedactableBytes. We can emit that directly.
 		sp := p.State.(redact.SafePrinter)
-		sp.Print(redact.RedactableBytes(p.finalBuf.Bytes()))
+		res := redact.RedactableBytes(p.finalBuf.Bytes())
+		for _, fn := range redactableOutputFilters {
+			res = fn(res)
+		}
+		sp.Print(res)
 		return
 	}
 	// Not redactable: render depending on flags and verb.
@@ -638,6 +977,9 @@ type state struct {
 	// the fmt.State above is actually a redact.SafePrinter.
 	redactableOutput bool
 
+	// opts carries the customizations requested via Formattable().
+	opts formatOptions
+
 	// finalBuf contains the final rendered string, prior to being
 	// copied to the fmt.State above.
 	//
@@ -775,6 +1117,24 @@ type formatEntry struct {
 	// a causer of others. This is used with verbose printing to
 	// illustrate the nesting depth for multi-cause error wrappers.
 	depth int
+
+	// repeat, if positive, is the number of identical causes of a
+	// multi-cause error that have been grouped into the subtree rooted
+	// at this entry, beyond the first. See
+	// FormatGroupIdenticalCauses().
+	repeat int
+
+	// inline contains the annotations of the InlineAnnotator wrappers
+	// around this entry, innermost first. See
+	// FormatInlineAnnotations(). Like head and details, they are
+	// RedactableBytes iff the output is redactable.
+	inline [][]byte
+}
+
+// isPlain returns true if the entry contributes neither details nor a
+// stack trace.
+func (e *formatEntry) isPlain() bool {
+	return len(e.details) == 0 && e.stackTrace == nil && len(e.inline) == 0
 }
 
 // String is used for debugging only.
@@ -867,10 +1227,24 @@ func (p *state) switchOver() {
 	// p.needNewline -= 1
 }
 
+func (p *state) detailLevel() int {
+	if !p.wantDetail {
+		return 0
+	}
+	if p.opts.detailLevel > 0 {
+		return p.opts.detailLevel
+	}
+	return 1
+}
+
 func (s *printer) Detail() bool {
 	return ((*state)(s)).detail()
 }
 
+func (s *printer) DetailLevel() int {
+	return ((*state)(s)).detailLevel()
+}
+
 func (s *printer) Print(args ...interface{}) {
 	s.enhanceArgs(args)
 	fmt.Fprint((*state)(s), args...)
@@ -890,7 +1264,7 @@ func (s *printer) enhanceArgs(args []interface{}) {
 			lastSeen = st
 		}
 		if err, ok := args[i].(error); ok {
-			args[i] = &errorFormatter{err}
+			args[i] = &errorFormatter{err: err}
 		}
 	}
 	s.lastStack = lastSeen
@@ -908,6 +1282,10 @@ func (s *safePrinter) Detail() bool {
 	return ((*state)(s)).detail()
 }
 
+func (s *safePrinter) DetailLevel() int {
+	return ((*state)(s)).detailLevel()
+}
+
 func (s *safePrinter) Print(args ...interface{}) {
 	s.enhanceArgs(args)
 	redact.Fprint((*state)(s), args...)
@@ -936,10 +1314,15 @@ func (s *safePrinter) enhanceArgs(args []interface{}) {
 	s.lastStack = lastSeen
 }
 
-type errorFormatter struct{ err error }
+type errorFormatter struct {
+	err  error
+	opts formatOptions
+}
 
 // Format implements the fmt.Formatter interface.
-func (ef *errorFormatter) Format(s fmt.State, verb rune) { FormatError(ef.err, s, verb) }
+func (ef *errorFormatter) Format(s fmt.State, verb rune) {
+	formatErrorInternal(ef.err, s, verb, false /* redactableOutput */, ef.opts)
+}
 
 // Error implements error, so that `redact` knows what to do with it.
 func (ef *errorFormatter) Error() string { return ef.err.Error() }
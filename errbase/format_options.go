@@ -0,0 +1,238 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase
+
+import "github.com/cockroachdb/redact"
+
+// FormatOption customizes the rendering of an error by the
+// fmt.Formatter returned by Formattable().
+type FormatOption func(*formatOptions)
+
+// formatOptions collects the customizations requested via
+// FormatOption values. The zero value is the default rendering.
+type formatOptions struct {
+	// detailLevel is the value reported by Printer.DetailLevel()
+	// when details are requested. Zero means the default (1).
+	detailLevel int
+	// maxStackFrames, if positive, limits the number of stack
+	// frames printed for each stack trace.
+	maxStackFrames int
+	// causeSeparator, if non-nil, replaces ": " between the messages
+	// of successive layers in the single-line rendering.
+	causeSeparator *string
+	// collapsePlainWrappers, if true, causes the verbose rendering to
+	// print chains of layers without details on a single line.
+	collapsePlainWrappers bool
+	// groupIdenticalCauses, if true, causes the verbose rendering to
+	// print identical causes of multi-cause errors only once.
+	groupIdenticalCauses bool
+	// inlineAnnotations, if true, causes the verbose rendering to
+	// print the annotations of InlineAnnotator wrappers next to the
+	// layer of their cause.
+	inlineAnnotations bool
+	// hideStacks, if true, causes the verbose rendering to omit
+	// all the stack traces.
+	hideStacks bool
+	// maxDepth, if positive, limits the number of nested layers
+	// printed by the verbose rendering, overriding
+	// SetMaxFormatDepth().
+	maxDepth int
+	// hideMaskedErrors, if true, causes the verbose rendering to
+	// omit the details of the layers registered with
+	// RegisterMaskingLayer().
+	hideMaskedErrors bool
+}
+
+// FormatDetailLevel sets the level of detail reported to errors via
+// Printer.DetailLevel() when formatting with %+v. The default level
+// is 1. Errors that implement tiered details can emit additional
+// information at levels 2 and above.
+func FormatDetailLevel(level int) FormatOption {
+	return func(o *formatOptions) { o.detailLevel = level }
+}
+
+// FormatMaxStackFramesPerLayer limits the number of stack frames
+// printed for each stack trace in the error chain when formatting
+// with %+v. The remaining frames are summarized as "... (N more
+// frames)". This only affects display: the stack traces stored in the
+// error, and those included in Sentry reports, are not truncated. The
+// default, zero, prints all the frames.
+func FormatMaxStackFramesPerLayer(n int) FormatOption {
+	return func(o *formatOptions) { o.maxStackFrames = n }
+}
+
+// FormatMaxDepth limits the number of nested layers printed when
+// formatting with %+v, like SetMaxFormatDepth() but for a single
+// rendering. The default, zero, uses the limit configured with
+// SetMaxFormatDepth().
+func FormatMaxDepth(n int) FormatOption {
+	return func(o *formatOptions) { o.maxDepth = n }
+}
+
+// FormatCauseSeparator sets the separator printed between the
+// messages of successive layers in the single-line rendering of the
+// error, i.e. with %v and on the first line of %+v. The default is
+// ": ".
+func FormatCauseSeparator(sep string) FormatOption {
+	return func(o *formatOptions) { o.causeSeparator = &sep }
+}
+
+// FormatCollapsePlainWrappers shortens the output of %+v by printing
+// chains of single-cause layers that contribute neither details nor
+// stack traces in a single block, with their messages joined as in
+// the first line of the output. For example:
+//
+//	Wraps: (2)-(4) b: c: d
+//
+// The error types of the collapsed layers are still listed
+// individually at the end of the output.
+func FormatCollapsePlainWrappers() FormatOption {
+	return func(o *formatOptions) { o.collapsePlainWrappers = true }
+}
+
+// FormatGroupIdenticalCauses shortens the output of %+v for
+// multi-cause errors, e.g. those constructed with Join(), that contain
+// many identical causes. Causes that render identically to a previous
+// sibling, including their own causes, are omitted and counted on the
+// first occurrence instead. For example:
+//
+//	└─ Wraps: (2) connection refused (x50)
+//
+// Only the stack trace of the first occurrence is printed. The first
+// line of the output, which is the error's message, is unaffected.
+func FormatGroupIdenticalCauses() FormatOption {
+	return func(o *formatOptions) { o.groupIdenticalCauses = true }
+}
+
+// FormatInlineAnnotations shortens the output of %+v by rendering
+// the wrappers that implement InlineAnnotator, e.g. those attached
+// with WithDuration(), next to the layer they annotate instead of as
+// layers of their own. For example:
+//
+//	Wraps: (2) prefix [+1.2s]
+//
+// The annotation wrappers are then omitted from the list of error
+// types at the end of the output. When the error contains no such
+// wrapper, the output is unchanged.
+func FormatInlineAnnotations() FormatOption {
+	return func(o *formatOptions) { o.inlineAnnotations = true }
+}
+
+// FormatHideStacks omits all the "-- stack trace:" blocks from the
+// output of %+v, while keeping the rest of the details. Leaf errors
+// which print their own stack trace, e.g. those from
+// github.com/pkg/errors, are rendered with their message only.
+//
+// Unlike the StripStacks option of Normalize(), this does not modify
+// the error: it only affects the rendering.
+func FormatHideStacks() FormatOption {
+	return func(o *formatOptions) { o.hideStacks = true }
+}
+
+// FormatHideMaskedErrors omits the details of the layers that
+// conceal another error, as declared via RegisterMaskingLayer(),
+// from the output of %+v. For example, the barriers of the barriers
+// package are then rendered with their message only, without the
+// "-- cause hidden behind barrier" block.
+//
+// Like FormatHideStacks(), this does not modify the error: it only
+// affects the rendering.
+func FormatHideMaskedErrors() FormatOption {
+	return func(o *formatOptions) { o.hideMaskedErrors = true }
+}
+
+// RegisterMaskingLayer declares that the layers of the given type
+// conceal another error, whose rendering they include in their
+// details, e.g. the barriers of the barriers package. The details of
+// these layers are omitted when formatting with the
+// FormatHideMaskedErrors() option.
+//
+// This is meant to be called from an init() function.
+func RegisterMaskingLayer(theType TypeKey) {
+	maskingLayers[theType] = struct{}{}
+}
+
+// IsMaskingLayer returns true iff the given error layer was declared
+// via RegisterMaskingLayer() to conceal another error.
+func IsMaskingLayer(err error) bool {
+	_, ok := maskingLayers[GetTypeKey(err)]
+	return ok
+}
+
+// registry for RegisterMaskingLayer.
+var maskingLayers = map[TypeKey]struct{}{}
+
+// RegisterSecuritySensitiveLayer declares that the layers of the
+// given type flag their cause as security sensitive: the unsafe parts
+// of such a layer and its causes are redacted by FormatError(), i.e.
+// when formatting with fmt, as if they were printed via
+// redact.Sprint() and then redacted. The layers wrapped around it are
+// rendered as usual.
+//
+// This is meant to be called from an init() function.
+func RegisterSecuritySensitiveLayer(theType TypeKey) {
+	securitySensitiveLayers[theType] = struct{}{}
+}
+
+// IsSecuritySensitiveLayer returns true iff the given error layer was
+// declared via RegisterSecuritySensitiveLayer().
+func IsSecuritySensitiveLayer(err error) bool {
+	if len(securitySensitiveLayers) == 0 {
+		return false
+	}
+	_, ok := securitySensitiveLayers[GetTypeKey(err)]
+	return ok
+}
+
+// IsSecuritySensitive returns true iff the given error contains,
+// in its causal chain or the causes of its multi-errors, a layer
+// declared via RegisterSecuritySensitiveLayer(). At most
+// maxWalkedLayers layers are inspected, so that this terminates on
+// cyclic chains.
+func IsSecuritySensitive(err error) bool {
+	if len(securitySensitiveLayers) == 0 {
+		return false
+	}
+	n := 0
+	for todo := []error{err}; len(todo) > 0 && n < maxWalkedLayers; n++ {
+		e := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+		if e == nil {
+			continue
+		}
+		if IsSecuritySensitiveLayer(e) {
+			return true
+		}
+		if cause := UnwrapOnce(e); cause != nil {
+			todo = append(todo, cause)
+		} else {
+			todo = append(todo, UnwrapMulti(e)...)
+		}
+	}
+	return false
+}
+
+// registry for RegisterSecuritySensitiveLayer.
+var securitySensitiveLayers = map[TypeKey]struct{}{}
+
+// InlineAnnotator is implemented by wrapper types that carry a short
+// annotation about their cause, e.g. a duration. When formatting
+// with FormatInlineAnnotations(), the annotation is rendered next to
+// the layer of the cause in the output of %+v, instead of as a layer
+// of its own.
+type InlineAnnotator interface {
+	// InlineAnnotation returns the annotation, e.g. "+1.2s".
+	InlineAnnotation() redact.RedactableString
+}
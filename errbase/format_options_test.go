@@ -0,0 +1,344 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase_test
+
+import (
+	goErr "errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors/barriers"
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/hintdetail"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+	pkgErr "github.com/pkg/errors"
+)
+
+// tieredErr is a wrapper which prints more details
+// at higher detail levels.
+type tieredErr struct {
+	cause error
+}
+
+func (e *tieredErr) Error() string                 { return e.cause.Error() }
+func (e *tieredErr) Unwrap() error                 { return e.cause }
+func (e *tieredErr) Format(s fmt.State, verb rune) { errbase.FormatError(e, s, verb) }
+func (e *tieredErr) SafeFormatError(p errbase.Printer) error {
+	p.Printf("level %d", redact.Safe(p.DetailLevel()))
+	if p.Detail() {
+		p.Printf("basic detail")
+		if p.DetailLevel() >= 2 {
+			p.Printf("\ndeep detail")
+		}
+	}
+	return e.cause
+}
+
+func TestFormatDetailLevel(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := &tieredErr{cause: goErr.New("woo")}
+
+	// Without detail, the level is zero.
+	tt.CheckStringEqual(fmt.Sprintf("%v", errbase.Formattable(err)), `level 0: woo`)
+	tt.CheckStringEqual(fmt.Sprintf("%v", errbase.Formattable(err, errbase.FormatDetailLevel(2))),
+		`level 0: woo`)
+
+	// The default detail level is 1.
+	tt.CheckStringEqual(fmt.Sprintf("%+v", errbase.Formattable(err)), `level 1: woo
+(1) level 1
+  | basic detail
+Wraps: (2) woo
+Error types: (1) *errbase_test.tieredErr (2) *errors.errorString`)
+
+	// Higher levels enable more details.
+	tt.CheckStringEqual(fmt.Sprintf("%+v", errbase.Formattable(err, errbase.FormatDetailLevel(2))), `level 2: woo
+(1) level 2
+  | basic detail
+  | deep detail
+Wraps: (2) woo
+Error types: (1) *errbase_test.tieredErr (2) *errors.errorString`)
+}
+
+func deepStackErr(depth int) error {
+	if depth == 0 {
+		return pkgErr.New("deep")
+	}
+	return deepStackErr(depth - 1)
+}
+
+func TestFormatMaxStackFramesPerLayer(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := deepStackErr(10)
+	numFrames := len(err.(errbase.StackTraceProvider).StackTrace())
+	tt.Assert(numFrames > 10)
+
+	// By default, all frames are printed.
+	full := fmt.Sprintf("%+v", errbase.Formattable(err))
+	tt.Check(!strings.Contains(full, "more frames"))
+	tt.CheckEqual(strings.Count(full, "deepStackErr"), 11)
+
+	// With a cap, only the top frames are printed.
+	capped := fmt.Sprintf("%+v", errbase.Formattable(err, errbase.FormatMaxStackFramesPerLayer(3)))
+	tt.CheckEqual(strings.Count(capped, "deepStackErr"), 3)
+	tt.Check(strings.Contains(capped,
+		fmt.Sprintf("\n  | ... (%d more frames)\n", numFrames-3)))
+
+	// A cap larger than the stack has no effect.
+	tt.CheckStringEqual(
+		fmt.Sprintf("%+v", errbase.Formattable(err, errbase.FormatMaxStackFramesPerLayer(numFrames))),
+		full)
+
+	// The stored stack trace is not truncated.
+	tt.CheckEqual(len(err.(errbase.StackTraceProvider).StackTrace()), numFrames)
+}
+
+func TestFormatSingleLine(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := goErr.New("hello")
+	tt.CheckStringEqual(errbase.FormatSingleLine(err, " -> "), "hello")
+
+	// Layers without a message of their own do not
+	// produce a separator.
+	err = pkgErr.WithStack(err)
+	err = pkgErr.WithMessage(err, "woo")
+	err = fmt.Errorf("waa: %w", err)
+	tt.CheckStringEqual(err.Error(), "waa: woo: hello")
+	tt.CheckStringEqual(errbase.FormatSingleLine(err, " -> "), "waa -> woo -> hello")
+	tt.CheckStringEqual(errbase.FormatSingleLine(err, "\n"), "waa\nwoo\nhello")
+
+	// The separator also applies to the first line of the
+	// verbose output.
+	verbose := fmt.Sprintf("%+v", errbase.Formattable(err, errbase.FormatCauseSeparator(" -> ")))
+	tt.Check(strings.HasPrefix(verbose, "waa -> woo -> hello\n(1) waa\n"))
+
+	tt.CheckStringEqual(errbase.FormatSingleLine(nil, " -> "), "<nil>")
+}
+
+func TestFormatCollapsePlainWrappers(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := goErr.New("d")
+	err = fmt.Errorf("c: %w", err)
+	err = fmt.Errorf("b: %w", err)
+	err = &tieredErr{cause: err}
+	err = pkgErr.WithMessage(err, "a1")
+	err = pkgErr.WithMessage(err, "a2")
+
+	tt.CheckStringEqual(fmt.Sprintf("%+v", errbase.Formattable(err)), `a2: a1: level 1: b: c: d
+(1) a2
+Wraps: (2) a1
+Wraps: (3) level 1
+  | basic detail
+Wraps: (4) b
+Wraps: (5) c
+Wraps: (6) d
+Error types: (1) *errors.withMessage (2) *errors.withMessage (3) *errbase_test.tieredErr (4) *fmt.wrapError (5) *fmt.wrapError (6) *errors.errorString`)
+
+	tt.CheckStringEqual(fmt.Sprintf("%+v", errbase.Formattable(err, errbase.FormatCollapsePlainWrappers())), `a2: a1: level 1: b: c: d
+(1)-(2) a2: a1
+Wraps: (3) level 1
+  | basic detail
+Wraps: (4)-(6) b: c: d
+Error types: (1) *errors.withMessage (2) *errors.withMessage (3) *errbase_test.tieredErr (4) *fmt.wrapError (5) *fmt.wrapError (6) *errors.errorString`)
+
+	// Layers with stack traces are not collapsed, and multi-error
+	// siblings are not collapsed together.
+	err = fmt.Errorf("a: %w", pkgErr.WithStack(goErr.Join(
+		fmt.Errorf("b: %w", goErr.New("c")), goErr.New("d"))))
+	v := fmt.Sprintf("%+v", errbase.Formattable(err, errbase.FormatCollapsePlainWrappers()))
+	tt.Check(strings.HasPrefix(v, "a: b: c\n(1) a\nWraps: (2)\n"))
+	tt.CheckContains(v, "\nWraps: (3) b: cd\n")
+	tt.CheckContains(v, "\n  └─ Wraps: (4) d\n")
+	tt.CheckContains(v, "\n  └─ Wraps: (5)-(6) b: c\n")
+}
+
+func TestFormatGroupIdenticalCauses(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	var errs []error
+	for i := 0; i < 10; i++ {
+		errs = append(errs, fmt.Errorf("dial: %w", goErr.New("connection refused")))
+	}
+	errs = append(errs, goErr.New("timeout"))
+	err := goErr.Join(errs...)
+
+	// By default, all the causes are listed.
+	v := fmt.Sprintf("%+v", errbase.Formattable(err))
+	tt.Check(strings.Contains(v, "(22) connection refused\n"))
+
+	// With grouping, the identical causes are counted instead.
+	v = fmt.Sprintf("%+v", errbase.Formattable(err, errbase.FormatGroupIdenticalCauses()))
+	tt.Check(strings.HasSuffix(v, `
+  | timeout
+Wraps: (2) timeout
+Wraps: (3) dial (x10)
+└─ Wraps: (4) connection refused
+Error types: (1) *errors.joinError (2) *errors.errorString (3) *fmt.wrapError (4) *errors.errorString`))
+
+	// The message of the error is unaffected.
+	tt.CheckStringEqual(fmt.Sprintf("%v", errbase.Formattable(err, errbase.FormatGroupIdenticalCauses())),
+		err.Error())
+}
+
+func TestFormatInlineAnnotations(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	// Without annotations, the output is unchanged.
+	err := pkgErr.WithMessage(goErr.New("woo"), "prefix")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", errbase.Formattable(err, errbase.FormatInlineAnnotations())),
+		fmt.Sprintf("%+v", errbase.Formattable(err)))
+
+	err = errutil.WithDuration(pkgErr.WithMessage(
+		errutil.WithDuration(goErr.New("woo"), 300*time.Millisecond), "prefix"), 1200*time.Millisecond)
+
+	tt.CheckStringEqual(fmt.Sprintf("%+v", errbase.Formattable(err)), `prefix: woo
+(1) after: 1.2s
+Wraps: (2) prefix
+Wraps: (3) after: 300ms
+Wraps: (4) woo
+Error types: (1) *errutil.withDuration (2) *errors.withMessage (3) *errutil.withDuration (4) *errors.errorString`)
+
+	tt.CheckStringEqual(fmt.Sprintf("%+v", errbase.Formattable(err, errbase.FormatInlineAnnotations())), `prefix: woo
+(1) prefix [+1.2s]
+Wraps: (2) woo [+300ms]
+Error types: (1) *errors.withMessage (2) *errors.errorString`)
+
+	// The message of the error is unaffected.
+	tt.CheckStringEqual(fmt.Sprintf("%v", errbase.Formattable(err, errbase.FormatInlineAnnotations())),
+		err.Error())
+}
+
+func TestFormatHideStacks(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := hintdetail.WithHint(errutil.Wrap(errutil.New("woo"), "prefix"), "try again")
+
+	full := fmt.Sprintf("%+v", errbase.Formattable(err))
+	tt.CheckEqual(strings.Count(full, "-- stack trace:"), 2)
+
+	tt.CheckStringEqual(fmt.Sprintf("%+v", errbase.Formattable(err, errbase.FormatHideStacks())), `prefix: woo
+(1) try again
+Wraps: (2) attached stack trace
+Wraps: (3) prefix
+Wraps: (4) attached stack trace
+Wraps: (5) woo
+Error types: (1) *hintdetail.withHint (2) *withstack.withStack (3) *errutil.withPrefix (4) *withstack.withStack (5) *errutil.leafError`)
+
+	// A leaf error which prints its own stack trace is rendered
+	// with its message only.
+	err = pkgErr.New("woo")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", errbase.Formattable(pkgErr.WithMessage(err, "prefix"), errbase.FormatHideStacks())), `prefix: woo
+(1) prefix
+Wraps: (2) woo
+Error types: (1) *errors.withMessage (2) *errors.fundamental`)
+
+	// The error itself is unchanged.
+	tt.Check(strings.Contains(fmt.Sprintf("%+v", errbase.Formattable(err)), "-- stack trace:"))
+}
+
+func TestFormatHideMaskedErrors(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := errutil.WithMessage(barriers.HandledWithMessage(goErr.New("secret"), "handled"), "prefix")
+
+	tt.Check(strings.Contains(fmt.Sprintf("%+v", err), "secret"))
+	tt.Check(errbase.IsMaskingLayer(errbase.UnwrapAll(err)))
+	tt.Check(!errbase.IsMaskingLayer(err))
+
+	const expected = `prefix: handled
+(1) prefix
+Wraps: (2) handled
+Error types: (1) *errutil.withPrefix (2) *barriers.barrierErr`
+	tt.CheckStringEqual(fmt.Sprintf("%+v", errbase.Formattable(err, errbase.FormatHideMaskedErrors())), expected)
+
+	// The option also applies to redactable output.
+	var buf redact.StringBuilder
+	buf.Printf("%+v", redactableFormatter{err, []errbase.FormatOption{errbase.FormatHideMaskedErrors()}})
+	tt.CheckStringEqual(buf.RedactableString().StripMarkers(), expected)
+}
+
+// redactableFormatter formats an error with options in redactable
+// output.
+type redactableFormatter struct {
+	err  error
+	opts []errbase.FormatOption
+}
+
+func (f redactableFormatter) SafeFormat(s redact.SafePrinter, verb rune) {
+	errbase.FormatRedactableErrorWithOptions(f.err, s, verb, f.opts...)
+}
+
+func TestSetMaxFormatDepth(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	var err error = goErr.New("leaf")
+	for i := 0; i < 1000; i++ {
+		err = pkgErr.WithMessagef(err, "m%d", i)
+	}
+	msg := err.Error()
+
+	errbase.SetMaxFormatDepth(3)
+	defer errbase.SetMaxFormatDepth(0)
+
+	tt.CheckStringEqual(fmt.Sprintf("%+v", errbase.Formattable(err)), `m999: m998: m997: ... (998 more layers omitted)
+(1) m999
+Wraps: (2) m998
+Wraps: (3) m997
+Wraps: (4) ... (998 more layers omitted)
+Error types: (1) *errors.withMessage (2) *errors.withMessage (3) *errors.withMessage (4) *errors.withMessage`)
+
+	// The truncation marker is considered safe for reporting.
+	tt.CheckStringEqual(string(redact.Sprintf("%+v", err).Redact()), `‹×›: ‹×›: ‹×›: ... (998 more layers omitted)
+(1) ‹×›
+Wraps: (2) ‹×›
+Wraps: (3) ‹×›
+Wraps: (4) ... (998 more layers omitted)
+Error types: (1) *errors.withMessage (2) *errors.withMessage (3) *errors.withMessage (4) *errors.withMessage`)
+
+	// The message is never truncated, including when a wrapper
+	// computes it by formatting its cause, so the identity of the
+	// error is preserved.
+	tt.CheckStringEqual(err.Error(), msg)
+	tt.CheckStringEqual(fmt.Sprintf("%v", errbase.Formattable(err)), msg)
+	errbase.SetMaxFormatDepth(2)
+	a := errutil.WithMessage(errutil.WithMessage(errutil.WithMessage(goErr.New("a"), "x"), "b"), "c")
+	tt.CheckStringEqual(a.Error(), "c: b: x: a")
+	tt.CheckStringEqual(fmt.Sprintf("%v", a), a.Error())
+	b := errutil.WithMessage(errutil.WithMessage(errutil.WithMessage(goErr.New("a"), "y"), "b"), "c")
+	tt.Check(!markers.Is(a, b))
+	tt.Check(markers.Is(a, errutil.WithMessage(errutil.WithMessage(errutil.WithMessage(goErr.New("a"), "x"), "b"), "c")))
+
+	// The limit can also be set for a single rendering.
+	errbase.SetMaxFormatDepth(0)
+	tt.CheckContains(fmt.Sprintf("%+v", errbase.Formattable(err, errbase.FormatMaxDepth(2))),
+		"Wraps: (3) ... (999 more layers omitted)")
+	tt.Check(!strings.Contains(fmt.Sprintf("%+v", errbase.Formattable(err)), "omitted"))
+
+	// The count of omitted layers is bounded.
+	errbase.SetMaxFormatDepth(3)
+	for i := 0; i < 10000; i++ {
+		err = pkgErr.WithMessage(err, "m")
+	}
+	tt.CheckContains(fmt.Sprintf("%+v", errbase.Formattable(err)),
+		"m: m: m: ... (10000+ more layers omitted)")
+}
@@ -96,4 +96,16 @@ type Printer interface {
 	// detail has not been requested.
 	// If Detail returns false, the caller can avoid printing the detail at all.
 	Detail() bool
+
+	// DetailLevel reports the amount of detail requested. It is 0
+	// when no detail is requested, 1 for the details printed with %+v
+	// (this is the level at which Detail() returns true) and higher
+	// when more detail was requested via the FormatDetailLevel option to
+	// Formattable(). Errors can use this to print additional
+	// information only at high verbosity levels.
+	//
+	// Unlike Detail(), DetailLevel does not affect the output
+	// produced by the Printer. Call Detail() first to start the
+	// detail section.
+	DetailLevel() int
 }
@@ -101,6 +101,11 @@ func RegisterTypeMigration(previousPkgPath, previousTypeName string, newType err
 		panic(fmt.Errorf("migration to type %q already registered (from %q)", newKey, f))
 	}
 	backwardRegistry[newKey] = prevKey
+	migrations = append(migrations, TypeMigration{
+		OldPath:   previousPkgPath,
+		OldType:   previousTypeName,
+		NewFamily: string(newKey),
+	})
 	// If any other key was registered as a migration from newKey,
 	// we'll forward those as well.
 	// This changes X -> newKey to X -> prevKey for every X.
@@ -111,13 +116,50 @@ func RegisterTypeMigration(previousPkgPath, previousTypeName string, newType err
 	}
 }
 
+// migratedFamily returns the family name to use for the given type
+// key: the oldest known name if the type was migrated, or the key
+// itself otherwise. This ensures that errors created before and after
+// a migration have the same family, and thus compare equal.
+func migratedFamily(typeKey string) string {
+	if prevKey, ok := backwardRegistry[TypeKey(typeKey)]; ok {
+		return string(prevKey)
+	}
+	return typeKey
+}
+
 // registry used when encoding an error, so that the receiver observes
 // the original key. This maps new keys to old keys.
 var backwardRegistry = map[TypeKey]TypeKey{}
 
-// TestingWithEmptyMigrationRegistry is intended for use by tests.
+// TypeMigration describes a call to RegisterTypeMigration(). It is
+// returned by RegisteredMigrations().
+type TypeMigration struct {
+	// OldPath and OldType are the previous package path and type name
+	// of the error type.
+	OldPath, OldType string
+	// NewFamily is the type key of the current error type.
+	NewFamily string
+}
+
+// RegisteredMigrations returns the type migrations registered with
+// RegisterTypeMigration(), in the order they were registered. This
+// is meant for diagnostics, for example to investigate why an error
+// received from a previous version is not decoded to the new type.
+// The result is a copy which the caller is free to modify.
+func RegisteredMigrations() []TypeMigration {
+	return append([]TypeMigration(nil), migrations...)
+}
+
+// migrations is the list returned by RegisteredMigrations().
+var migrations []TypeMigration
+
+// TestingWithEmptyMigrationRegistry is intended for use by tests. It
+// removes all the registered type migrations, so that a test can
+// register its own without interference from other packages, and
+// returns a function which restores the previous registrations.
 func TestingWithEmptyMigrationRegistry() (restore func()) {
-	save := backwardRegistry
+	save, saveList := backwardRegistry, migrations
 	backwardRegistry = map[TypeKey]TypeKey{}
-	return func() { backwardRegistry = save }
+	migrations = nil
+	return func() { backwardRegistry, migrations = save, saveList }
 }
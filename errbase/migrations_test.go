@@ -16,11 +16,14 @@ package errbase_test
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 
 	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/join"
 	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/testutils"
 	"github.com/gogo/protobuf/proto"
 )
 
@@ -299,6 +302,83 @@ func TestUnknownErrorComparisonAfterHeterogeneousMigration(t *testing.T) {
 	}
 }
 
+// Scenario 6: comparison between a migrated wrapper received from
+// the network and a local instance of the new type.
+// - v2 renames foo -> bar
+// - v1 sends error foo to v2
+// - v2' (which has bar but does not know about the migration) sends
+//   error bar to v2
+// - v2 compares the two errors with a local bar error.
+func TestMigratedWrapperEquivalence(t *testing.T) {
+	defer errbase.TestingWithEmptyMigrationRegistry()()
+
+	// == Scenario on v1 ==
+	encPre := errbase.EncodeError(context.Background(), &fooWrap{cause: errors.New("hello")})
+
+	// == Scenario on v2' ==
+	encNew := errbase.EncodeError(context.Background(), &barWrap{cause: errors.New("hello")})
+
+	// == Scenario on v2 ==
+	decPre := errbase.DecodeError(context.Background(), encPre)
+	decNew := errbase.DecodeError(context.Background(), encNew)
+	local := &barWrap{cause: errors.New("hello")}
+
+	// Without the migration, the errors are different.
+	if markers.Is(decPre, local) {
+		t.Error("unexpected equivalence without migration")
+	}
+
+	// Register the fact that foo was migrated to bar.
+	errbase.RegisterTypeMigration(myPkgPath, "*errbase_test.fooWrap", &barWrap{})
+
+	// Main test: check that v2 recognizes all the errors as equivalent,
+	// regardless of which side of the migration they were created on.
+	for _, pair := range [][2]error{
+		{decPre, local},
+		{decNew, local},
+		{decPre, decNew},
+	} {
+		if !markers.Is(pair[0], pair[1]) || !markers.Is(pair[1], pair[0]) {
+			t.Errorf("equivalence after migration failed: %T vs %T", pair[0], pair[1])
+		}
+	}
+
+	// The equivalence extends to the branches of multi-errors.
+	other := errors.New("world")
+	if !markers.Is(join.Join(decPre, other), join.Join(local, other)) {
+		t.Error("multi-error equivalence after migration failed")
+	}
+	if !markers.Is(join.Join(local, other), join.Join(decNew, other)) {
+		t.Error("multi-error equivalence after migration failed")
+	}
+}
+
+func TestRegisteredMigrations(t *testing.T) {
+	tt := testutils.T{T: t}
+	defer errbase.TestingWithEmptyMigrationRegistry()()
+
+	tt.Check(len(errbase.RegisteredMigrations()) == 0)
+
+	errbase.RegisterTypeMigration(myPkgPath, "errbase_test.fooErr", barErr{})
+	errbase.RegisterTypeMigration("some/old/path", "*oldpkg.qux", quxErr{})
+
+	migrations := errbase.RegisteredMigrations()
+	tt.CheckDeepEqual(migrations, []errbase.TypeMigration{
+		{OldPath: myPkgPath, OldType: "errbase_test.fooErr", NewFamily: myPkgPath + "/errbase_test.barErr"},
+		{OldPath: "some/old/path", OldType: "*oldpkg.qux", NewFamily: myPkgPath + "/errbase_test.quxErr"},
+	})
+
+	// The result is a copy.
+	migrations[0].OldType = "woo"
+	tt.CheckStringEqual(errbase.RegisteredMigrations()[0].OldType, "errbase_test.fooErr")
+
+	// The registrations are removed by the testing helper.
+	restore := errbase.TestingWithEmptyMigrationRegistry()
+	tt.Check(len(errbase.RegisteredMigrations()) == 0)
+	restore()
+	tt.CheckEqual(len(errbase.RegisteredMigrations()), 2)
+}
+
 type fooErr struct{}
 
 func (fooErr) Error() string { return "" }
@@ -319,6 +399,16 @@ type barErrP struct{}
 
 func (*barErrP) Error() string { return "" }
 
+type fooWrap struct{ cause error }
+
+func (w *fooWrap) Error() string { return "wrap: " + w.cause.Error() }
+func (w *fooWrap) Unwrap() error { return w.cause }
+
+type barWrap struct{ cause error }
+
+func (w *barWrap) Error() string { return "wrap: " + w.cause.Error() }
+func (w *barWrap) Unwrap() error { return w.cause }
+
 var myPkgPath = func() string {
 	t := fooErr{}
 	return reflect.TypeOf(t).PkgPath()
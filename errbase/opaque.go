@@ -135,3 +135,29 @@ func (e *opaqueWrapper) SafeFormatError(p Printer) (next error) {
 	}
 	return e.cause
 }
+
+// IsRemote returns true iff the error or one of its causes, including
+// those of multi-cause errors, was received over the network with a
+// type that could not be decoded locally. Such layers are preserved
+// opaquely: their message and safe details are available, and they are
+// re-encoded exactly, but their Go type and any behavior attached to
+// it are lost.
+//
+// Errors decoded from the network whose types all have a decoder
+// registered locally are indistinguishable from local errors, and
+// IsRemote returns false for them.
+func IsRemote(err error) bool {
+	switch err.(type) {
+	case *opaqueLeaf, *opaqueLeafCauses, *opaqueWrapper:
+		return true
+	}
+	if cause := UnwrapOnce(err); cause != nil {
+		return IsRemote(cause)
+	}
+	for _, c := range UnwrapMulti(err) {
+		if IsRemote(c) {
+			return true
+		}
+	}
+	return false
+}
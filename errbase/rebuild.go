@@ -0,0 +1,53 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase
+
+import "context"
+
+// replaceCause returns a copy of the wrapper err, whose cause is
+// cause, with newCause in place of cause. err itself is not modified.
+//
+// Wrappers which implement Cloner are copied with CloneWithCause().
+// Other wrappers are rebuilt using the encoder and decoder registered
+// for their type, as if they had been received from the network.
+// The wrapper types which have not been registered with the library
+// are thus rebuilt as opaque wrappers, which preserve their message,
+// their safe details and their error mark.
+func replaceCause(ctx context.Context, err, cause, newCause error) error {
+	if c, ok := err.(Cloner); ok {
+		return c.CloneWithCause(newCause)
+	}
+	ctx = context.WithValue(ctx, rebuildingKey{}, true)
+	enc := encodeWrapperLayer(ctx, err, cause)
+	return decodeWrapperLayer(ctx, enc, newCause, false /* lite */)
+}
+
+// replaceCauses is like replaceCause, for a multi-cause error err.
+// It is always rebuilt using its registered encoder and decoder.
+func replaceCauses(ctx context.Context, err error, newCauses []error) error {
+	ctx = context.WithValue(ctx, rebuildingKey{}, true)
+	enc := encodeLeafLayer(ctx, err)
+	return decodeLeafLayer(ctx, enc, newCauses, false /* lite */)
+}
+
+// rebuildingKey is the context key which indicates that an error
+// layer is encoded to be rebuilt locally, and not to be sent over the
+// network. The strict encoding mode does not apply in that case.
+type rebuildingKey struct{}
+
+func isRebuilding(ctx context.Context) bool {
+	v, _ := ctx.Value(rebuildingKey{}).(bool)
+	return v
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 
 	"github.com/cockroachdb/errors/errorspb"
+	"github.com/cockroachdb/redact"
 	pkgErr "github.com/pkg/errors"
 )
 
@@ -53,6 +54,42 @@ func GetSafeDetails(err error) (payload SafeDetailPayload) {
 	return
 }
 
+// HasSafeDetails returns true if any layer of the error, including
+// the causes of multi-errors, provides non-empty safe details or a
+// stack trace. This is meant as a cheap check before building a
+// report, to skip errors that carry nothing beyond their message.
+func HasSafeDetails(err error) bool {
+	for ; err != nil; err = UnwrapOnce(err) {
+		if sd, ok := err.(SafeDetailer); ok && len(sd.SafeDetails()) > 0 {
+			return true
+		}
+		if _, ok := err.(StackTraceProvider); ok {
+			return true
+		}
+		for _, c := range UnwrapMulti(err) {
+			if HasSafeDetails(c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsFullySafe returns true if the message of the error contains no
+// unsafe part, i.e. every layer of the error produces its message
+// using safe content only, e.g. via SafeFormatError() with the
+// arguments marked as safe. In that case, the message can be shown
+// to end users or included in reports verbatim; otherwise, a generic
+// message should be used instead. IsFullySafe returns true for a nil
+// error.
+func IsFullySafe(err error) bool {
+	if err == nil {
+		return true
+	}
+	msg := redact.Sprint(err)
+	return msg.Redact() == msg
+}
+
 func getDetails(err error) []string {
 	if sd, ok := err.(SafeDetailer); ok {
 		return sd.SafeDetails()
@@ -0,0 +1,85 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/safedetails"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/withstack"
+	"github.com/cockroachdb/redact"
+	pkgErr "github.com/pkg/errors"
+)
+
+func TestHasSafeDetails(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.Check(!errbase.HasSafeDetails(nil))
+
+	// Bare errors carry nothing beyond their message.
+	err := goErr.New("hello")
+	tt.Check(!errbase.HasSafeDetails(err))
+	tt.Check(!errbase.HasSafeDetails(fmt.Errorf("woo: %w", err)))
+
+	// Stack traces are recognized, also in the pkg/errors style.
+	tt.Check(errbase.HasSafeDetails(withstack.WithStack(err)))
+	tt.Check(errbase.HasSafeDetails(pkgErr.New("hello")))
+
+	// Safe details are recognized at any depth.
+	sdErr := safedetails.WithSafeDetails(err, "safe %s", "detail")
+	tt.Check(errbase.HasSafeDetails(sdErr))
+	tt.Check(errbase.HasSafeDetails(fmt.Errorf("woo: %w", sdErr)))
+
+	// An empty set of safe details is not sufficient.
+	tt.Check(!errbase.HasSafeDetails(safedetails.WithSafeDetails(err, "")))
+
+	// The causes of multi-errors are inspected.
+	tt.Check(!errbase.HasSafeDetails(goErr.Join(err, goErr.New("world"))))
+	tt.Check(errbase.HasSafeDetails(goErr.Join(err, sdErr)))
+
+	// Details are preserved across the network.
+	enc := errbase.EncodeError(context.Background(), withstack.WithStack(err))
+	tt.Check(errbase.HasSafeDetails(errbase.DecodeError(context.Background(), enc)))
+}
+
+func TestIsFullySafe(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.Check(errbase.IsFullySafe(nil))
+
+	// Errors constructed from safe content only.
+	err := errutil.Newf("cannot open %s", redact.Safe("config.yaml"))
+	err = errutil.Wrap(err, "while starting")
+	err = withstack.WithStack(err)
+	tt.Check(errbase.IsFullySafe(err))
+
+	enc := errbase.EncodeError(context.Background(), err)
+	tt.Check(errbase.IsFullySafe(errbase.DecodeError(context.Background(), enc)))
+
+	// An unsafe argument anywhere makes the error unsafe.
+	tt.Check(!errbase.IsFullySafe(errutil.Newf("cannot open %s", "secret.txt")))
+	tt.Check(!errbase.IsFullySafe(errutil.Wrapf(err, "for user %s", "alice")))
+
+	// Errors that do not implement SafeFormatError are unsafe.
+	tt.Check(!errbase.IsFullySafe(goErr.New("hello")))
+	tt.Check(!errbase.IsFullySafe(errutil.Wrap(goErr.New("hello"), "while starting")))
+	tt.Check(!errbase.IsFullySafe(fmt.Errorf("while starting: %w", err)))
+}
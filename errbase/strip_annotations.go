@@ -0,0 +1,91 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase
+
+import "context"
+
+// StripAnnotations returns a copy of err from which the annotation
+// layers have been removed, keeping only the wrappers that contribute
+// to the message, the leaves and the stack traces. This is meant to
+// store or compare errors in a minimal form. The message of the
+// result is the same as that of err, and markers.Is() is preserved
+// for the remaining causes.
+//
+// The annotation layers are those whose type has been registered
+// with RegisterStrippableAnnotation(). This library registers:
+//   - the hints and user-facing details of the hintdetail package.
+//   - the domains of the domains package.
+//   - the codes of the extgrpc, exthttp, errclass and errreason
+//     packages, and the exit codes of the errutil package.
+//   - the context tags of the contexttags package.
+//   - the telemetry keys of the telemetrykeys package.
+//   - the issue and remediation links of the issuelink package.
+//   - the secondary errors of the secondary package.
+//
+// Other wrappers, in particular the stack traces, the safe details,
+// the error marks and the assertion failures, are kept. The layers
+// above a removed annotation are copied: the wrappers which implement
+// Cloner are copied with CloneWithCause(), and the other wrappers are
+// rebuilt using their registered encoder and decoder, as opaque
+// wrappers if they have not been registered with the library.
+func StripAnnotations(err error) error {
+	s, _ := stripAnnotations(context.Background(), err)
+	return s
+}
+
+// stripAnnotations implements StripAnnotations. It returns true iff
+// the result is different from err.
+func stripAnnotations(ctx context.Context, err error) (error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	if cause := UnwrapOnce(err); cause != nil {
+		newCause, changed := stripAnnotations(ctx, cause)
+		if _, ok := strippableAnnotations[GetTypeKey(err)]; ok {
+			return newCause, true
+		}
+		if !changed {
+			return err, false
+		}
+		return replaceCause(ctx, err, cause, newCause), true
+	}
+	causes := UnwrapMulti(err)
+	var newCauses []error
+	for i, cause := range causes {
+		newCause, changed := stripAnnotations(ctx, cause)
+		if changed && newCauses == nil {
+			newCauses = append([]error(nil), causes...)
+		}
+		if newCauses != nil {
+			newCauses[i] = newCause
+		}
+	}
+	if newCauses == nil {
+		return err, false
+	}
+	return replaceCauses(ctx, err, newCauses), true
+}
+
+// RegisterStrippableAnnotation declares that the given wrapper type
+// is an annotation, which does not contribute to the message of the
+// error, and is removed by StripAnnotations().
+//
+// This is meant to be called from an init() function.
+func RegisterStrippableAnnotation(theType TypeKey) {
+	strippableAnnotations[theType] = struct{}{}
+}
+
+// registry for RegisterStrippableAnnotation.
+var strippableAnnotations = map[TypeKey]struct{}{}
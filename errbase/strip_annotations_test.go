@@ -0,0 +1,118 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errbase_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/contexttags"
+	"github.com/cockroachdb/errors/domains"
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/hintdetail"
+	"github.com/cockroachdb/errors/issuelink"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/safedetails"
+	"github.com/cockroachdb/errors/secondary"
+	"github.com/cockroachdb/errors/telemetrykeys"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/logtags"
+)
+
+func TestStripAnnotations(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.Check(errbase.StripAnnotations(nil) == nil)
+
+	// Errors without annotations are returned as-is.
+	leaf := goErr.New("hello")
+	err := fmt.Errorf("woo: %w", errutil.WithMessage(leaf, "waa"))
+	tt.Check(errbase.StripAnnotations(err) == err)
+
+	ctx := logtags.AddTag(context.Background(), "tag", 123)
+	err = hintdetail.WithHint(leaf, "some hint")
+	err = hintdetail.WithDetail(err, "some detail")
+	err = errutil.Wrap(err, "woo")
+	err = domains.WithDomain(err, "mydomain")
+	err = contexttags.WithContextTags(err, ctx)
+	err = telemetrykeys.WithTelemetry(err, "somekey")
+	err = issuelink.WithIssueLink(err, issuelink.IssueLink{IssueURL: "https://example.com"})
+	err = secondary.WithSecondaryError(err, goErr.New("other"))
+	err = safedetails.WithSafeDetails(err, "safe")
+	err = errutil.WithMessage(err, "waa")
+
+	theTest := func(tt testutils.T, err error) {
+		s := errbase.StripAnnotations(err)
+
+		// The message and the causes are preserved.
+		tt.CheckStringEqual(s.Error(), "waa: woo: hello")
+		tt.Check(markers.Is(s, leaf))
+
+		// The annotations are gone.
+		tt.CheckDeepEqual(hintdetail.GetAllHints(s), []string(nil))
+		tt.CheckDeepEqual(hintdetail.GetAllDetails(s), []string(nil))
+		tt.CheckEqual(domains.GetDomain(s), domains.NoDomain)
+		tt.CheckDeepEqual(contexttags.GetContextTags(s), []*logtags.Buffer(nil))
+		tt.CheckEqual(len(telemetrykeys.GetTelemetryKeys(s)), 0)
+		tt.Check(!issuelink.HasIssueLink(s))
+
+		// The other layers are kept: the two prefixes, the safe
+		// details, the stack trace and the leaf.
+		n := 0
+		for c := s; c != nil; c = errbase.UnwrapOnce(c) {
+			n++
+		}
+		tt.CheckEqual(n, 5)
+		tt.CheckDeepEqual(errbase.GetSafeDetails(errbase.UnwrapOnce(s)).SafeDetails, []string{"safe"})
+		tt.CheckContains(fmt.Sprintf("%+v", s), "strip_annotations_test.go")
+
+		// The original error is unchanged.
+		tt.CheckEqual(len(hintdetail.GetAllHints(err)), 2)
+	}
+
+	tt.Run("local", func(tt testutils.T) { theTest(tt, err) })
+
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+
+	tt.Run("remote", func(tt testutils.T) { theTest(tt, newErr) })
+}
+
+func TestStripAnnotationsRebuildsWrappersAbove(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	// The wrappers of this library which are not encoded on the
+	// network keep their state. The wrappers which have not been
+	// registered with the library are rebuilt as opaque wrappers.
+	leaf := goErr.New("hello")
+	err := hintdetail.WithHint(leaf, "some hint")
+	err = errutil.WithLocalMetadata(err, map[string]string{"k": "v"})
+	err = &localWrapper{cause: err}
+
+	s := errbase.StripAnnotations(err)
+	tt.CheckStringEqual(s.Error(), "local: hello")
+	tt.Check(markers.Is(s, leaf))
+	tt.CheckDeepEqual(hintdetail.GetAllHints(s), []string(nil))
+
+	var lw *localWrapper
+	tt.Check(!goErr.As(s, &lw))
+	tt.CheckDeepEqual(errutil.GetLocalMetadata(s), map[string]string{"k": "v"})
+
+	// The original error is unchanged.
+	tt.CheckEqual(len(hintdetail.GetAllHints(err)), 1)
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/cockroachdb/errors/errbase"
 	"github.com/cockroachdb/errors/errbase/internal"
+	"github.com/cockroachdb/errors/join"
 	"github.com/cockroachdb/errors/testutils"
 	"github.com/gogo/protobuf/proto"
 	"github.com/kr/pretty"
@@ -209,3 +210,31 @@ func TestUnknownWrapperTraversal(t *testing.T) {
 	// The original object has been restored!
 	tt.CheckDeepEqual(newErr2, origErr)
 }
+
+// remoteWrap is another wrapper type that the rest of the library
+// has no idea about.
+type remoteWrap struct{ cause error }
+
+func (m *remoteWrap) Unwrap() error { return m.cause }
+func (m *remoteWrap) Error() string { return "hi: " + m.cause.Error() }
+
+func TestIsRemote(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	// Locally built errors are not remote.
+	origErr := &remoteWrap{cause: &myError{val: 123}}
+	tt.Check(!errbase.IsRemote(origErr))
+	tt.Check(!errbase.IsRemote(nil))
+
+	// Errors of known types are not remote after a round trip.
+	tt.Check(!errbase.IsRemote(network(t, errors.New("hello"))))
+
+	// Unknown leaf and wrapper types are remote.
+	tt.Check(errbase.IsRemote(network(t, &myError{val: 123})))
+	tt.Check(errbase.IsRemote(network(t, &remoteWrap{cause: errors.New("hello")})))
+	tt.Check(errbase.IsRemote(network(t, origErr)))
+
+	// This includes unknown types inside multi-cause errors.
+	tt.Check(!errbase.IsRemote(network(t, join.Join(errors.New("hello"), errors.New("world")))))
+	tt.Check(errbase.IsRemote(network(t, join.Join(errors.New("hello"), &myError{val: 123}))))
+}
@@ -14,6 +14,8 @@
 
 package errbase
 
+import "github.com/cockroachdb/errors/errorspb"
+
 // Sadly the go 2/1.13 design for errors has promoted the name
 // `Unwrap()` for the method that accesses the cause, whilst the
 // ecosystem has already chosen `Cause()`. In order to unwrap
@@ -61,6 +63,25 @@ func UnwrapAll(err error) error {
 	return err
 }
 
+// CausesInnermostFirst returns the error and each of its causes,
+// starting from the root cause and ending with the error itself.
+// This is the reverse of the order obtained by iterating with
+// UnwrapOnce().
+//
+// Like UnwrapAll, CausesInnermostFirst treats multi-errors as leaf
+// nodes: if the chain contains a multi-error, it is returned as the
+// first element and its causes are not included.
+func CausesInnermostFirst(err error) []error {
+	var res []error
+	for c := err; c != nil; c = UnwrapOnce(c) {
+		res = append(res, c)
+	}
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res
+}
+
 // UnwrapMulti access the slice of causes that an error contains, if it is a
 // multi-error.
 func UnwrapMulti(err error) []error {
@@ -69,3 +90,43 @@ func UnwrapMulti(err error) []error {
 	}
 	return nil
 }
+
+// WalkWithMarks calls fn for the error and each of its causes, in
+// depth-first order starting from the outermost layer. Each layer is
+// passed along with its ErrorTypeMark, as would be returned by
+// GetTypeMark(), and whether it is a leaf, i.e. it has neither a
+// cause nor multiple causes.
+//
+// Unlike UnwrapOnce and UnwrapAll, WalkWithMarks descends into the
+// causes of multi-errors, in the order they are returned by
+// UnwrapMulti.
+func WalkWithMarks(err error, fn func(layer error, mark errorspb.ErrorTypeMark, isLeaf bool)) {
+	if err == nil {
+		return
+	}
+	cause := UnwrapOnce(err)
+	causes := UnwrapMulti(err)
+	fn(err, GetTypeMark(err), cause == nil && len(causes) == 0)
+	if cause != nil {
+		WalkWithMarks(cause, fn)
+		return
+	}
+	for _, c := range causes {
+		WalkWithMarks(c, fn)
+	}
+}
+
+// WalkAnnotations calls fn for each annotation layer in the error
+// tree, in the same order as WalkWithMarks. An annotation layer is a
+// wrapper whose message is the same as that of its cause, i.e. a
+// wrapper that attaches information (hints, details, codes, domains,
+// etc.) without changing the visible message. Wrappers that add a
+// message prefix, leaves and multi-errors are not visited, although
+// their causes are.
+func WalkAnnotations(err error, fn func(layer error, mark errorspb.ErrorTypeMark)) {
+	WalkWithMarks(err, func(layer error, mark errorspb.ErrorTypeMark, _ bool) {
+		if cause := UnwrapOnce(layer); cause != nil && layer.Error() == cause.Error() {
+			fn(layer, mark)
+		}
+	})
+}
@@ -19,9 +19,15 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/cockroachdb/errors/domains"
 	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errorspb"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/extgrpc"
+	"github.com/cockroachdb/errors/hintdetail"
 	"github.com/cockroachdb/errors/testutils"
 	pkgErr "github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
 )
 
 // This test demonstrates how to use errbase.UnwrapOnce and errbase.UnwrapAll to
@@ -71,7 +77,77 @@ func TestMultiErrorUnwrap(t *testing.T) {
 	tt.CheckDeepEqual(errbase.UnwrapMulti(err3), []error{err, err2})
 }
 
+func TestCausesInnermostFirst(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.Check(errbase.CausesInnermostFirst(nil) == nil)
+
+	err := errors.New("hello")
+	tt.CheckDeepEqual(errbase.CausesInnermostFirst(err), []error{err})
+
+	err2 := pkgErr.WithMessage(err, "woo")
+	err3 := &myWrapper{cause: err2}
+	tt.CheckDeepEqual(errbase.CausesInnermostFirst(err3), []error{err, err2, err3})
+
+	// Multi-errors are treated as leaf nodes.
+	err4 := fmt.Errorf("%w %w", err, err2)
+	err5 := pkgErr.WithMessage(err4, "waa")
+	tt.CheckDeepEqual(errbase.CausesInnermostFirst(err5), []error{err4, err5})
+}
+
 type myWrapper struct{ cause error }
 
 func (w *myWrapper) Error() string { return w.cause.Error() }
 func (w *myWrapper) Unwrap() error { return w.cause }
+
+func TestWalkWithMarks(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	leaf1 := errors.New("hello")
+	leaf2 := pkgErr.New("world")
+	multi := errors.Join(pkgErr.WithMessage(leaf1, "woo"), leaf2)
+	err := pkgErr.WithStack(multi)
+
+	var layers []error
+	var leaves []bool
+	errbase.WalkWithMarks(err, func(layer error, mark errorspb.ErrorTypeMark, isLeaf bool) {
+		// The marks are those computed by GetTypeMark.
+		tt.CheckDeepEqual(mark, errbase.GetTypeMark(layer))
+		layers = append(layers, layer)
+		leaves = append(leaves, isLeaf)
+	})
+
+	tt.CheckDeepEqual(layers, []error{err, multi, errbase.UnwrapMulti(multi)[0], leaf1, leaf2})
+	tt.CheckDeepEqual(leaves, []bool{false, false, false, true, true})
+
+	// Nothing is visited for a nil error.
+	errbase.WalkWithMarks(nil, func(error, errorspb.ErrorTypeMark, bool) {
+		t.Error("unexpected call")
+	})
+}
+
+func TestWalkAnnotations(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	leaf := errors.New("hello")
+	hint := hintdetail.WithHint(leaf, "try again")
+	msg := errutil.Wrapf(hint, "woo %d", 1)
+	dom := domains.WithDomain(msg, domains.NamedDomain("mydomain"))
+	err := extgrpc.WrapWithGrpcCode(dom, codes.Unavailable)
+
+	var layers []error
+	errbase.WalkAnnotations(err, func(layer error, mark errorspb.ErrorTypeMark) {
+		tt.CheckDeepEqual(mark, errbase.GetTypeMark(layer))
+		layers = append(layers, layer)
+	})
+
+	// The message wrapper and the leaf are not visited. Note that
+	// Wrapf also adds a stack trace annotation on top of the message
+	// wrapper, which is visited.
+	tt.CheckDeepEqual(layers, []error{err, dom, msg, hint})
+
+	// Nothing is visited for a nil error.
+	errbase.WalkAnnotations(nil, func(error, errorspb.ErrorTypeMark) {
+		t.Error("unexpected call")
+	})
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errorspb"
 )
 
 // UnwrapOnce accesses the direct cause of the error if any, otherwise
@@ -33,15 +34,149 @@ func UnwrapOnce(err error) error { return errbase.UnwrapOnce(err) }
 // If the error has no cause (leaf error), it is returned directly.
 func UnwrapAll(err error) error { return errbase.UnwrapAll(err) }
 
+// CausesInnermostFirst returns the error and each of its causes,
+// starting from the root cause and ending with the error itself.
+// This is the reverse of the order obtained by iterating with
+// UnwrapOnce().
+//
+// Like UnwrapAll, CausesInnermostFirst treats multi-errors as leaf
+// nodes: if the chain contains a multi-error, it is returned as the
+// first element and its causes are not included.
+func CausesInnermostFirst(err error) []error { return errbase.CausesInnermostFirst(err) }
+
+// WalkWithMarks calls fn for the error and each of its causes, in
+// depth-first order starting from the outermost layer. Each layer is
+// passed along with its ErrorTypeMark, as would be returned by
+// GetTypeMark(), and whether it is a leaf, i.e. it has neither a
+// cause nor multiple causes.
+//
+// Unlike UnwrapOnce and UnwrapAll, WalkWithMarks descends into the
+// causes of multi-errors, in the order they are returned by
+// UnwrapMulti.
+func WalkWithMarks(err error, fn func(layer error, mark errorspb.ErrorTypeMark, isLeaf bool)) {
+	errbase.WalkWithMarks(err, fn)
+}
+
+// WalkAnnotations calls fn for each annotation layer in the error
+// tree, in the same order as WalkWithMarks. An annotation layer is a
+// wrapper whose message is the same as that of its cause, i.e. a
+// wrapper that attaches information (hints, details, codes, domains,
+// etc.) without changing the visible message. Wrappers that add a
+// message prefix, leaves and multi-errors are not visited, although
+// their causes are.
+func WalkAnnotations(err error, fn func(layer error, mark errorspb.ErrorTypeMark)) {
+	errbase.WalkAnnotations(err, fn)
+}
+
 // EncodedError is the type of an encoded (and protobuf-encodable) error.
 type EncodedError = errbase.EncodedError
 
+// EncodedFormatVersion is the version of the encoding format
+// produced by EncodeError(). It is stamped on the outermost
+// EncodedError only, since a whole error is always encoded with the
+// same version, so that decoders can detect version skew, for
+// example in mixed-version clusters or when decoding long-lived
+// persisted errors. The EncodedError of the causes report version
+// zero.
+//
+// Errors encoded before the version was introduced report version
+// zero. DecodeError() accepts errors encoded with any version:
+// fields unknown to the decoder are ignored, and fields missing
+// from older encodings take their default value.
+const EncodedFormatVersion uint32 = errbase.EncodedFormatVersion
+
 // EncodeError encodes an error.
 func EncodeError(ctx context.Context, err error) EncodedError { return errbase.EncodeError(ctx, err) }
 
 // DecodeError decodes an error.
 func DecodeError(ctx context.Context, enc EncodedError) error { return errbase.DecodeError(ctx, enc) }
 
+// DecodeErrorLite is like DecodeError, but skips the stack traces
+// embedded in the encoded error. This reduces the cost of decoding
+// for callers that only care about the messages, error marks and
+// other details of the error. In particular, Is() works the same on
+// the result.
+//
+// The layers of the result that carried a stack trace, as declared
+// via RegisterStackTraceCarrier(), do not report it anywhere: not in
+// their safe details, not when formatting with %+v, and not when
+// the error is encoded again.
+func DecodeErrorLite(ctx context.Context, enc EncodedError) error {
+	return errbase.DecodeErrorLite(ctx, enc)
+}
+
+// IsRemote returns true iff the error or one of its causes, including
+// those of multi-cause errors, was received over the network with a
+// type that could not be decoded locally. Such layers are preserved
+// opaquely: their message and safe details are available, and they are
+// re-encoded exactly, but their Go type and any behavior attached to
+// it are lost.
+//
+// Errors decoded from the network whose types all have a decoder
+// registered locally are indistinguishable from local errors, and
+// IsRemote returns false for them.
+func IsRemote(err error) bool { return errbase.IsRemote(err) }
+
+// EncodedErrors is the type of an encoded (and protobuf-encodable)
+// list of errors.
+type EncodedErrors = errbase.EncodedErrors
+
+// EncodeErrors encodes a list of errors, for example the per-item
+// results of a batch operation. The positions of the errors are
+// preserved: nil errors are encoded as an unset EncodedError.
+func EncodeErrors(ctx context.Context, errs []error) EncodedErrors {
+	return errbase.EncodeErrors(ctx, errs)
+}
+
+// DecodeErrors decodes a list of errors encoded with EncodeErrors.
+// The unset entries are decoded as nil errors.
+func DecodeErrors(ctx context.Context, enc EncodedErrors) []error {
+	return errbase.DecodeErrors(ctx, enc)
+}
+
+// DecodeErrorAs is like DecodeError, but for use by callers that
+// expect the outermost layer of the encoded error to be of the same
+// type as sample. It returns true iff the encoded error is of the
+// sample's type, as per GetTypeKey(), and the error was decoded into
+// that Go type using the decoder registered for it.
+//
+// If the types differ, or the error cannot be decoded into the
+// sample's type, for example because no decoder is registered, the
+// error is decoded normally and false is returned.
+func DecodeErrorAs(ctx context.Context, enc EncodedError, sample error) (error, bool) {
+	return errbase.DecodeErrorAs(ctx, enc, sample)
+}
+
+// MarshalJSON produces a compact JSON representation of an encoded
+// error, suitable for transporting errors through logs or other
+// systems that do not carry protobufs. The representation is
+// lossless: the result of UnmarshalJSON() can be passed to
+// DecodeError() to reconstruct the error.
+//
+// The JSON field names mirror those of the protobuf definition and
+// are stable across versions. Full details payloads are included as
+// their type URL and base64-encoded protobuf bytes.
+func MarshalJSON(enc EncodedError) ([]byte, error) { return errbase.MarshalJSON(enc) }
+
+// UnmarshalJSON is the inverse of MarshalJSON.
+func UnmarshalJSON(data []byte) (EncodedError, error) { return errbase.UnmarshalJSON(data) }
+
+// EncodeCompact encodes an error like EncodeError, and serializes the
+// result into a byte slice. For the common case of a chain of
+// wrappers around a single leaf error, this uses a compact encoding
+// which is smaller than the protobuf encoding of EncodedError. Other
+// errors, e.g. multi-errors, are serialized as the protobuf encoding
+// of EncodedError. In both cases, the representation is lossless: the
+// error can be reconstructed with DecodeCompact(). A nil error is
+// encoded as an unset EncodedError, and decoded as nil.
+func EncodeCompact(ctx context.Context, err error) []byte { return errbase.EncodeCompact(ctx, err) }
+
+// DecodeCompact decodes an error serialized with EncodeCompact. The
+// second return value is non-nil if the payload is malformed.
+func DecodeCompact(ctx context.Context, data []byte) (error, error) {
+	return errbase.DecodeCompact(ctx, data)
+}
+
 // SafeDetailer is an interface that can be implemented by errors that
 // can provide PII-free additional strings suitable for reporting or
 // telemetry.
@@ -57,6 +192,21 @@ func GetAllSafeDetails(err error) []SafeDetailPayload { return errbase.GetAllSaf
 // returned.
 func GetSafeDetails(err error) (payload SafeDetailPayload) { return errbase.GetSafeDetails(err) }
 
+// HasSafeDetails returns true if any layer of the error, including
+// the causes of multi-errors, provides non-empty safe details or a
+// stack trace. This is meant as a cheap check before building a
+// report, to skip errors that carry nothing beyond their message.
+func HasSafeDetails(err error) bool { return errbase.HasSafeDetails(err) }
+
+// IsFullySafe returns true if the message of the error contains no
+// unsafe part, i.e. every layer of the error produces its message
+// using safe content only, e.g. via SafeFormatError() with the
+// arguments marked as safe. In that case, the message can be shown
+// to end users or included in reports verbatim; otherwise, a generic
+// message should be used instead. IsFullySafe returns true for a nil
+// error.
+func IsFullySafe(err error) bool { return errbase.IsFullySafe(err) }
+
 // SafeDetailPayload captures the safe strings for one
 // level of wrapping.
 type SafeDetailPayload = errbase.SafeDetailPayload
@@ -81,6 +231,13 @@ type TypeKey = errbase.TypeKey
 // is meant for use in combination with the Register functions.
 func GetTypeKey(err error) TypeKey { return errbase.GetTypeKey(err) }
 
+// GetOriginalTypeName retrieves the original Go type name of the
+// outermost layer of the given error, including its package path.
+// For errors decoded from the network whose type is not known
+// locally, this is the type name that was recorded when the error
+// was encoded. The boolean return is false if err is nil.
+func GetOriginalTypeName(err error) (string, bool) { return errbase.GetOriginalTypeName(err) }
+
 // LeafDecoder is to be provided (via RegisterLeafDecoder above)
 // by additional wrapper types not yet known to this library.
 // A nil return indicates that decoding was not successful.
@@ -187,6 +344,68 @@ type MultiCauseEncoder = errbase.MultiCauseEncoder
 // SetWarningFn enables configuration of the warning function.
 func SetWarningFn(fn func(context.Context, string, ...interface{})) { errbase.SetWarningFn(fn) }
 
+// SetStrictEncoding enables or disables the strict encoding mode,
+// which is off by default. In strict mode, EncodeError panics when
+// it encounters an error type which has neither a registered encoder
+// nor a registered decoder, and would thus be decoded as an opaque
+// error on the other side.
+//
+// This is intended for use in tests, to surface missing
+// registrations at the point of encoding. It should not be enabled
+// in production code.
+func SetStrictEncoding(strict bool) { errbase.SetStrictEncoding(strict) }
+
+// RegisterOpaqueDecoding declares that the given error type is
+// intentionally left without a decoder, because decoding it as an
+// opaque error is sufficient. Such types are not reported by the
+// strict encoding mode enabled with SetStrictEncoding().
+func RegisterOpaqueDecoding(typeName TypeKey) { errbase.RegisterOpaqueDecoding(typeName) }
+
+// RegisterLocalOnlyWrapper declares that the wrappers of the given
+// type carry local-only state, which is not meant to leave the
+// process. EncodeError() does not encode such a wrapper: its cause is
+// encoded in its place, so that the wrapper does not appear in the
+// encoded form of the error.
+//
+// This is meant to be called from an init() function.
+func RegisterLocalOnlyWrapper(typeName TypeKey) { errbase.RegisterLocalOnlyWrapper(typeName) }
+
+// RegisterStackTraceCarrier declares that the first safe detail of
+// the encoded form of the given error type is a printed stack trace,
+// as is the case for the error types that carry a stack trace in
+// github.com/pkg/errors and in this library. This is used by
+// DecodeErrorLite() to skip the stack trace.
+//
+// This is meant to be called from an init() function.
+func RegisterStackTraceCarrier(typeName TypeKey) { errbase.RegisterStackTraceCarrier(typeName) }
+
+// RegisterStrippableAnnotation declares that the given wrapper type
+// is an annotation, which does not contribute to the message of the
+// error, and is removed by StripAnnotations().
+//
+// This is meant to be called from an init() function.
+func RegisterStrippableAnnotation(typeName TypeKey) { errbase.RegisterStrippableAnnotation(typeName) }
+
+// RegisterMaskingLayer declares that the layers of the given type
+// conceal another error, whose rendering they include in their
+// details, e.g. the barriers created by Handled(). The details of
+// these layers are omitted when formatting with the
+// FormatHideMaskedErrors() option.
+//
+// This is meant to be called from an init() function.
+func RegisterMaskingLayer(typeName TypeKey) { errbase.RegisterMaskingLayer(typeName) }
+
+// RegisterSecuritySensitiveLayer declares that the layers of the
+// given type flag their cause as security sensitive, e.g. those
+// created by WithSecuritySensitive(). The unsafe parts of such a layer
+// and its causes are redacted when formatting the error with fmt. The
+// layers wrapped around it are rendered as usual.
+//
+// This is meant to be called from an init() function.
+func RegisterSecuritySensitiveLayer(typeName TypeKey) {
+	errbase.RegisterSecuritySensitiveLayer(typeName)
+}
+
 // A Formatter formats error messages.
 //
 // NB: Consider implementing SafeFormatter instead. This will ensure
@@ -225,7 +444,137 @@ func FormatError(err error, s fmt.State, verb rune) { errbase.FormatError(err, s
 // Formattable wraps an error into a fmt.Formatter which
 // will provide "smart" formatting even if the outer layer
 // of the error does not implement the Formatter interface.
-func Formattable(err error) fmt.Formatter { return errbase.Formattable(err) }
+//
+// The optional FormatOption arguments customize the rendering,
+// see the documentation of each option for details.
+func Formattable(err error, opts ...FormatOption) fmt.Formatter {
+	return errbase.Formattable(err, opts...)
+}
+
+// FormatOption customizes the rendering of an error by the
+// fmt.Formatter returned by Formattable().
+type FormatOption = errbase.FormatOption
+
+// FormatDetailLevel sets the level of detail reported to errors via
+// Printer.DetailLevel() when formatting with %+v. The default level
+// is 1. Errors that implement tiered details can emit additional
+// information at levels 2 and above.
+func FormatDetailLevel(level int) FormatOption { return errbase.FormatDetailLevel(level) }
+
+// FormatMaxStackFramesPerLayer limits the number of stack frames
+// printed for each stack trace in the error chain when formatting
+// with %+v. The remaining frames are summarized as "... (N more
+// frames)". This only affects display: the stack traces stored in the
+// error, and those included in Sentry reports, are not truncated. The
+// default, zero, prints all the frames.
+func FormatMaxStackFramesPerLayer(n int) FormatOption {
+	return errbase.FormatMaxStackFramesPerLayer(n)
+}
+
+// FormatMaxDepth limits the number of nested layers printed when
+// formatting with %+v, like SetMaxFormatDepth() but for a single
+// rendering. The default, zero, uses the limit configured with
+// SetMaxFormatDepth().
+func FormatMaxDepth(n int) FormatOption { return errbase.FormatMaxDepth(n) }
+
+// FormatCauseSeparator sets the separator printed between the
+// messages of successive layers in the single-line rendering of the
+// error, i.e. with %v and on the first line of %+v. The default is
+// ": ".
+func FormatCauseSeparator(sep string) FormatOption { return errbase.FormatCauseSeparator(sep) }
+
+// FormatCollapsePlainWrappers shortens the output of %+v by printing
+// chains of single-cause layers that contribute neither details nor
+// stack traces in a single block, with their messages joined as in
+// the first line of the output. For example:
+//
+//	Wraps: (2)-(4) b: c: d
+//
+// The error types of the collapsed layers are still listed
+// individually at the end of the output.
+func FormatCollapsePlainWrappers() FormatOption { return errbase.FormatCollapsePlainWrappers() }
+
+// FormatGroupIdenticalCauses shortens the output of %+v for
+// multi-cause errors, e.g. those constructed with Join(), that contain
+// many identical causes. Causes that render identically to a previous
+// sibling, including their own causes, are omitted and counted on the
+// first occurrence instead. For example:
+//
+//	└─ Wraps: (2) connection refused (x50)
+//
+// Only the stack trace of the first occurrence is printed. The first
+// line of the output, which is the error's message, is unaffected.
+func FormatGroupIdenticalCauses() FormatOption { return errbase.FormatGroupIdenticalCauses() }
+
+// FormatInlineAnnotations shortens the output of %+v by rendering
+// the wrappers that implement InlineAnnotator, e.g. those attached
+// with WithDuration(), next to the layer they annotate instead of as
+// layers of their own. For example:
+//
+//	Wraps: (2) prefix [+1.2s]
+//
+// The annotation wrappers are then omitted from the list of error
+// types at the end of the output. When the error contains no such
+// wrapper, the output is unchanged.
+func FormatInlineAnnotations() FormatOption { return errbase.FormatInlineAnnotations() }
+
+// FormatHideStacks omits all the "-- stack trace:" blocks from the
+// output of %+v, while keeping the rest of the details. Leaf errors
+// which print their own stack trace, e.g. those from
+// github.com/pkg/errors, are rendered with their message only.
+//
+// Unlike the StripStacks option of Normalize(), this does not modify
+// the error: it only affects the rendering.
+func FormatHideStacks() FormatOption { return errbase.FormatHideStacks() }
+
+// FormatHideMaskedErrors omits the details of the layers that
+// conceal another error, as declared via RegisterMaskingLayer(),
+// from the output of %+v. For example, the barriers created by
+// Handled() are then rendered with their message only, without the
+// "-- cause hidden behind barrier" block.
+//
+// Like FormatHideStacks(), this does not modify the error: it only
+// affects the rendering.
+func FormatHideMaskedErrors() FormatOption { return errbase.FormatHideMaskedErrors() }
+
+// InlineAnnotator is implemented by wrapper types that carry a short
+// annotation about their cause, e.g. a duration. When formatting
+// with FormatInlineAnnotations(), the annotation is rendered next to
+// the layer of the cause in the output of %+v, instead of as a layer
+// of its own.
+type InlineAnnotator = errbase.InlineAnnotator
+
+// FormatSingleLine produces the message of the error, like Error(),
+// but using the given separator between the messages of successive
+// layers instead of ": ". Layers that do not contribute a message of
+// their own are skipped and do not produce a separator.
+func FormatSingleLine(err error, sep string) string { return errbase.FormatSingleLine(err, sep) }
+
+// SetMaxFormatDepth configures the maximum number of nested layers
+// that are rendered when formatting an error with %+v. The layers
+// beyond that depth are summarized by a single "... (N more layers
+// omitted)" entry. This protects logging against pathological error
+// chains. A value of zero or less, the default, means no limit.
+//
+// The message of the error, as returned by Error() and printed with
+// %v, is never truncated. The FormatMaxDepth() option overrides this
+// setting for a single rendering.
+func SetMaxFormatDepth(n int) { errbase.SetMaxFormatDepth(n) }
+
+// SetConstructionHook registers a function to be called every time
+// the library constructs an error with a stack trace, for example via
+// New(), Newf(), Wrap(), Wrapf() or WithStack(). The function
+// receives the newly constructed error. This can be used e.g. to
+// count or sample errors by type at the point they are created.
+//
+// The hook is called synchronously on the goroutine constructing the
+// error, so it should be fast and must not block. It must also not
+// construct errors using the library itself, to avoid infinite
+// recursion.
+//
+// Passing nil removes the hook. It is safe to call
+// SetConstructionHook() concurrently with the construction of errors.
+func SetConstructionHook(fn func(err error)) { errbase.SetConstructionHook(fn) }
 
 // RegisterTypeMigration tells the library that the type of the error
 // given as 3rd argument was previously known with type
@@ -251,3 +600,91 @@ func Formattable(err error) fmt.Formatter { return errbase.Formattable(err) }
 func RegisterTypeMigration(previousPkgPath, previousTypeName string, newType error) {
 	errbase.RegisterTypeMigration(previousPkgPath, previousTypeName, newType)
 }
+
+// TypeMigration describes a call to RegisterTypeMigration(). It is
+// returned by RegisteredMigrations().
+type TypeMigration = errbase.TypeMigration
+
+// RegisteredMigrations returns the type migrations registered with
+// RegisterTypeMigration(), in the order they were registered. This
+// is meant for diagnostics, for example to investigate why an error
+// received from a previous version is not decoded to the new type.
+// The result is a copy which the caller is free to modify.
+func RegisteredMigrations() []TypeMigration { return errbase.RegisteredMigrations() }
+
+// StackCompactor can be implemented by error layers carrying a stack
+// trace, to let CompactStacks() release the memory held by the stack
+// trace.
+type StackCompactor = errbase.StackCompactor
+
+// CompactStacks reduces the memory retained by the stack traces in
+// the direct causal chain of err: the innermost stack trace is
+// preserved, and the stack traces of the layers above it are
+// released if they implement StackCompactor. The branches of
+// multi-errors are compacted in the same way, each independently. The
+// identity of the error, as per Is(), is not modified.
+//
+// This is meant to be used for long-lived errors, for example errors
+// stored in a cache. Like the other functions of this library,
+// CompactStacks does not modify err: it returns a new error where the
+// compacted layers, and the layers above them, are copies. The layers
+// above the compacted ones are rebuilt like in Clone(), so the wrapper
+// types which have not been registered with the library become opaque
+// wrappers. The other layers are shared with err. When there is
+// nothing to compact, err itself is returned.
+func CompactStacks(err error) error { return errbase.CompactStacks(err) }
+
+// Cloner is implemented by wrapper types which cannot be rebuilt from
+// their encoding, for example because they have mutable or local-only
+// state, to let Clone(), StripAnnotations() and CompactStacks() copy
+// them. The wrapper types which do not implement it are rebuilt using
+// their registered encoder and decoder.
+type Cloner = errbase.Cloner
+
+// Clone returns a copy of err that is independent from it, so that
+// modifying a layer of one in place does not affect the other. The
+// copy has the same message, the same error marks (as per
+// markers.Is()) and the same annotations as err.
+//
+// The layers which implement Cloner are copied with CloneWithCause().
+// In this library, these are the stack traces and the wrappers with
+// local-only state: WithLocalMetadata(), WithLogged(),
+// errmeta.WithValue() and the secondary error summaries. The other
+// error types of this library are immutable.
+//
+// The layers above a copied layer are rebuilt around the copy: using
+// CloneWithCause() if they implement Cloner, and otherwise using the
+// encoder and decoder registered for their type, as if they had been
+// received from the network. The wrapper types which have not been
+// registered with the library are thus rebuilt as opaque wrappers,
+// which preserve their message, safe details and error mark but not
+// their Go type. The layers below the copied layers are immutable and
+// are shared between err and the copy. When err contains no layer
+// which implements Cloner, Clone returns err itself.
+func Clone(err error) error { return errbase.Clone(err) }
+
+// StripAnnotations returns a copy of err from which the annotation
+// layers have been removed, keeping only the wrappers that contribute
+// to the message, the leaves and the stack traces. This is meant to
+// store or compare errors in a minimal form. The message of the
+// result is the same as that of err, and markers.Is() is preserved
+// for the remaining causes.
+//
+// The annotation layers are those whose type has been registered
+// with RegisterStrippableAnnotation(). This library registers:
+//   - the hints and user-facing details of the hintdetail package.
+//   - the domains of the domains package.
+//   - the codes of the extgrpc, exthttp, errclass and errreason
+//     packages, and the exit codes of the errutil package.
+//   - the context tags of the contexttags package.
+//   - the telemetry keys of the telemetrykeys package.
+//   - the issue and remediation links of the issuelink package.
+//   - the secondary errors of the secondary package.
+//
+// Other wrappers, in particular the stack traces, the safe details,
+// the error marks and the assertion failures, are kept. The layers
+// above a removed annotation are copied: the wrappers which implement
+// Cloner are copied with CloneWithCause(), and the other wrappers are
+// rebuilt using their registered encoder and decoder, as opaque
+// wrappers if they have not been registered with the library.
+func StripAnnotations(err error) error { return errbase.StripAnnotations(err) }
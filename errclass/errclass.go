@@ -0,0 +1,155 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package errclass provides error categories drawn from a closed,
+// centrally defined taxonomy.
+package errclass
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// DefineCategories adds the given categories to the set of
+// categories accepted by WithCategory().
+//
+// This is meant to be called from an init() function.
+func DefineCategories(valid ...string) {
+	for _, c := range valid {
+		categories[c] = struct{}{}
+	}
+}
+
+// SetStrictCategories configures the behavior of WithCategory() when
+// it is passed a category that was not defined with
+// DefineCategories(). When strict (the default), WithCategory()
+// returns an assertion failure. When lenient, the category is
+// attached anyway.
+func SetStrictCategories(strict bool) {
+	lenient = !strict
+}
+
+// TestingWithEmptyCategories clears the defined categories and
+// restores the default strict mode. The returned function restores
+// the previous configuration. This is meant for use in tests.
+func TestingWithEmptyCategories() (restore func()) {
+	oldCategories, oldLenient := categories, lenient
+	categories, lenient = map[string]struct{}{}, false
+	return func() { categories, lenient = oldCategories, oldLenient }
+}
+
+// categories is the registry for DefineCategories.
+var categories = map[string]struct{}{}
+
+// lenient is configured by SetStrictCategories.
+var lenient bool
+
+// WithCategory annotates an error with a category from the set
+// defined with DefineCategories(). The category must not contain
+// PII: it is considered safe for reporting.
+//
+// If the category was not defined and strict mode is enabled (the
+// default, see SetStrictCategories()), an assertion failure is
+// returned instead. It preserves the message and details of the
+// original error.
+//
+// If the annotation is applied multiple times, the outermost
+// category wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetCategory()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithCategory(err error, cat string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := categories[cat]; !ok && !lenient {
+		return errutil.NewAssertionErrorWithWrappedErrDepthf(1, err,
+			"undefined error category %q", redact.Safe(cat))
+	}
+	return &withCategory{cause: err, category: cat}
+}
+
+// GetCategory retrieves the outermost category annotation
+// in the error's causal chain, or false if there is none.
+func GetCategory(err error) (string, bool) {
+	v, ok := markers.If(err, func(err error) (interface{}, bool) {
+		if w, ok := err.(*withCategory); ok {
+			return w.category, true
+		}
+		return nil, false
+	})
+	if !ok {
+		return "", false
+	}
+	return v.(string), true
+}
+
+type withCategory struct {
+	cause    error
+	category string
+}
+
+var _ error = (*withCategory)(nil)
+var _ errbase.SafeDetailer = (*withCategory)(nil)
+var _ fmt.Formatter = (*withCategory)(nil)
+var _ errbase.SafeFormatter = (*withCategory)(nil)
+
+func (w *withCategory) Error() string { return w.cause.Error() }
+func (w *withCategory) Cause() error  { return w.cause }
+func (w *withCategory) Unwrap() error { return w.cause }
+
+func (w *withCategory) SafeDetails() []string { return []string{w.category} }
+
+func (w *withCategory) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withCategory) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("category: %s", redact.Safe(w.category))
+	}
+	return w.cause
+}
+
+func decodeWithCategory(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) == 0 {
+		// Some future version of the library is using a different
+		// encoding. Let DecodeError use the opaque type.
+		return nil
+	}
+	// The category is restored as-is, even if it is not defined in
+	// this process.
+	return &withCategory{cause: cause, category: details[0]}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withCategory)(nil)), decodeWithCategory)
+	errbase.RegisterStrippableAnnotation(errbase.GetTypeKey((*withCategory)(nil)))
+	errutil.RegisterCodeExtractor("category", GetCategory)
+	// Like in decodeWithCategory(), the category is copied as-is, even
+	// if it is not defined in this process.
+	errutil.RegisterCodeWrapper("category", func(err, from error) error {
+		cat, _ := GetCategory(from)
+		return &withCategory{cause: err, category: cat}
+	})
+}
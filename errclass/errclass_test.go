@@ -0,0 +1,95 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errclass_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/assert"
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errclass"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+func TestValidCategory(t *testing.T) {
+	tt := testutils.T{T: t}
+	defer errclass.TestingWithEmptyCategories()()
+	errclass.DefineCategories("storage", "network")
+
+	origErr := goErr.New("woo")
+	_, ok := errclass.GetCategory(origErr)
+	tt.Check(!ok)
+	tt.Check(errclass.WithCategory(nil, "storage") == nil)
+
+	// The outermost category wins.
+	err := errclass.WithCategory(errclass.WithCategory(origErr, "storage"), "network")
+
+	cat, ok := errclass.GetCategory(err)
+	tt.Check(ok)
+	tt.CheckStringEqual(cat, "network")
+	tt.Check(!assert.HasAssertionFailure(err))
+
+	tt.CheckStringEqual(err.Error(), "woo")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", err), `woo
+(1) category: network
+Wraps: (2) category: storage
+Wraps: (3) woo
+Error types: (1) *errclass.withCategory (2) *errclass.withCategory (3) *errors.errorString`)
+
+	// The category is a safe detail.
+	tt.CheckDeepEqual(errbase.GetSafeDetails(err).SafeDetails, []string{"network"})
+
+	// Simulate a network transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+
+	cat, ok = errclass.GetCategory(newErr)
+	tt.Check(ok)
+	tt.CheckStringEqual(cat, "network")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", newErr), fmt.Sprintf("%+v", err))
+}
+
+func TestInvalidCategoryStrict(t *testing.T) {
+	tt := testutils.T{T: t}
+	defer errclass.TestingWithEmptyCategories()()
+	errclass.DefineCategories("storage")
+
+	origErr := goErr.New("woo")
+	err := errclass.WithCategory(origErr, "stroage")
+
+	tt.Check(assert.HasAssertionFailure(err))
+	_, ok := errclass.GetCategory(err)
+	tt.Check(!ok)
+	tt.Check(strings.Contains(err.Error(), `undefined error category "stroage"`))
+	tt.Check(strings.Contains(err.Error(), "woo"))
+}
+
+func TestInvalidCategoryLenient(t *testing.T) {
+	tt := testutils.T{T: t}
+	defer errclass.TestingWithEmptyCategories()()
+	errclass.DefineCategories("storage")
+	errclass.SetStrictCategories(false)
+
+	err := errclass.WithCategory(goErr.New("woo"), "stroage")
+
+	tt.Check(!assert.HasAssertionFailure(err))
+	cat, ok := errclass.GetCategory(err)
+	tt.Check(ok)
+	tt.CheckStringEqual(cat, "stroage")
+}
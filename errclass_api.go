@@ -0,0 +1,53 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errors
+
+import "github.com/cockroachdb/errors/errclass"
+
+// DefineCategories adds the given categories to the set of
+// categories accepted by WithCategory().
+//
+// This is meant to be called from an init() function.
+func DefineCategories(valid ...string) { errclass.DefineCategories(valid...) }
+
+// SetStrictCategories configures the behavior of WithCategory() when
+// it is passed a category that was not defined with
+// DefineCategories(). When strict (the default), WithCategory()
+// returns an assertion failure. When lenient, the category is
+// attached anyway.
+func SetStrictCategories(strict bool) { errclass.SetStrictCategories(strict) }
+
+// WithCategory annotates an error with a category from the set
+// defined with DefineCategories(). The category must not contain
+// PII: it is considered safe for reporting.
+//
+// If the category was not defined and strict mode is enabled (the
+// default, see SetStrictCategories()), an assertion failure is
+// returned instead. It preserves the message and details of the
+// original error.
+//
+// If the annotation is applied multiple times, the outermost
+// category wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetCategory()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithCategory(err error, cat string) error { return errclass.WithCategory(err, cat) }
+
+// GetCategory retrieves the outermost category annotation
+// in the error's causal chain, or false if there is none.
+func GetCategory(err error) (string, bool) { return errclass.GetCategory(err) }
@@ -0,0 +1,107 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package errmeta attaches arbitrary typed values to errors, for use
+// in the current process only.
+//
+// This is the error counterpart to context.WithValue(): it avoids
+// the need for single-purpose wrapper types when data only needs to
+// be passed alongside an error locally. The values are not
+// preserved by EncodeError/DecodeError: the annotation is omitted
+// from the encoded error.
+package errmeta
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithValue annotates an error with a value under the given key.
+// The value is only available in the current process.
+//
+// The value is considered unsafe: it is never included in safe
+// details, and is redacted in redactable output.
+//
+// Detail is shown:
+// - via `Value()` below.
+// - when formatting with `%+v` (redacted in Sentry reports).
+func WithValue[T any](err error, key string, value T) error {
+	if err == nil {
+		return nil
+	}
+	return &withValue{cause: err, key: key, value: value}
+}
+
+// Value retrieves the value attached with WithValue() under the
+// given key in the error's direct causal chain. If the key was
+// attached multiple times, the outermost value of type T wins. The
+// boolean result is false if there is no such value.
+func Value[T any](err error, key string) (T, bool) {
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		w, ok := c.(*withValue)
+		if !ok || w.key != key {
+			continue
+		}
+		if v, ok := w.value.(T); ok {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+type withValue struct {
+	cause error
+	key   string
+	value interface{}
+}
+
+var _ error = (*withValue)(nil)
+var _ fmt.Formatter = (*withValue)(nil)
+var _ errbase.SafeFormatter = (*withValue)(nil)
+var _ errbase.Cloner = (*withValue)(nil)
+
+func (w *withValue) Error() string { return w.cause.Error() }
+func (w *withValue) Cause() error  { return w.cause }
+func (w *withValue) Unwrap() error { return w.cause }
+
+// CloneWithCause implements the errbase.Cloner interface. The value
+// is not encoded, so the layer cannot be rebuilt from its encoding.
+func (w *withValue) CloneWithCause(cause error) error {
+	return &withValue{cause: cause, key: w.key, value: w.value}
+}
+
+func (w *withValue) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withValue) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("value: %s=%v", w.key, w.value)
+	}
+	return w.cause
+}
+
+// decodeWithValue drops the annotation, for the errors encoded by
+// previous versions of the library which did not omit it. The value
+// is local-only and was not encoded.
+func decodeWithValue(_ context.Context, cause error, _ string, _ []string, _ proto.Message) error {
+	return cause
+}
+
+func init() {
+	errbase.RegisterLocalOnlyWrapper(errbase.GetTypeKey((*withValue)(nil)))
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withValue)(nil)), decodeWithValue)
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errmeta_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errmeta"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+)
+
+type request struct {
+	id   int
+	user string
+}
+
+func TestValue(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("woo")
+	_, ok := errmeta.Value[int](origErr, "a")
+	tt.Check(!ok)
+	_, ok = errmeta.Value[int](nil, "a")
+	tt.Check(!ok)
+	tt.Check(errmeta.WithValue(nil, "a", 1) == nil)
+
+	err := errmeta.WithValue(origErr, "count", 42)
+	err = errmeta.WithValue(err, "req", &request{id: 7, user: "alice"})
+	err = errmeta.WithValue(err, "elapsed", 3*time.Second)
+	err = errmeta.WithValue(err, "count", 43)
+
+	// Values of different types under different keys. The outermost
+	// value wins for a given key.
+	n, ok := errmeta.Value[int](err, "count")
+	tt.Check(ok)
+	tt.CheckEqual(n, 43)
+	r, ok := errmeta.Value[*request](err, "req")
+	tt.Check(ok)
+	tt.CheckEqual(r.id, 7)
+	tt.CheckStringEqual(r.user, "alice")
+	d, ok := errmeta.Value[time.Duration](err, "elapsed")
+	tt.Check(ok)
+	tt.CheckEqual(d, 3*time.Second)
+
+	// A key that is not present, or a value of another type.
+	_, ok = errmeta.Value[int](err, "other")
+	tt.Check(!ok)
+	s, ok := errmeta.Value[string](err, "count")
+	tt.Check(!ok)
+	tt.CheckStringEqual(s, "")
+
+	// The outermost value of the requested type wins.
+	err2 := errmeta.WithValue(err, "count", "many")
+	s, ok = errmeta.Value[string](err2, "count")
+	tt.Check(ok)
+	tt.CheckStringEqual(s, "many")
+	n, ok = errmeta.Value[int](err2, "count")
+	tt.Check(ok)
+	tt.CheckEqual(n, 43)
+
+	tt.CheckStringEqual(err.Error(), "woo")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", err), `woo
+(1) value: count=43
+Wraps: (2) value: elapsed=3s
+Wraps: (3) value: req=&{7 alice}
+Wraps: (4) value: count=42
+Wraps: (5) woo
+Error types: (1) *errmeta.withValue (2) *errmeta.withValue (3) *errmeta.withValue (4) *errmeta.withValue (5) *errors.errorString`)
+
+	// The values are redacted in redactable output.
+	redacted := redact.Sprintf("%+v", err).Redact()
+	tt.Check(!strings.Contains(string(redacted), "alice"))
+
+	// The values do not survive a network transfer: the annotations
+	// are not encoded at all.
+	enc := errbase.EncodeError(context.Background(), err)
+	tt.CheckDeepEqual(enc, errbase.EncodeError(context.Background(), origErr))
+	newErr := errbase.DecodeError(context.Background(), enc)
+
+	_, ok = errmeta.Value[int](newErr, "count")
+	tt.Check(!ok)
+	tt.CheckStringEqual(newErr.Error(), "woo")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", newErr), fmt.Sprintf("%+v", origErr))
+}
@@ -63,6 +63,11 @@ type EncodedError struct {
 	//	*EncodedError_Leaf
 	//	*EncodedError_Wrapper
 	Error isEncodedError_Error `protobuf_oneof:"error"`
+	// The version of the encoding format used to produce this
+	// error. Zero for errors encoded before the version was
+	// introduced. Only set on the outermost EncodedError: the
+	// causes are encoded with the same version.
+	FormatVersion uint32 `protobuf:"varint,3,opt,name=format_version,json=formatVersion,proto3" json:"format_version,omitempty"`
 }
 
 func (m *EncodedError) Reset()         { *m = EncodedError{} }
@@ -131,6 +136,13 @@ func (m *EncodedError) GetWrapper() *EncodedWrapper {
 	return nil
 }
 
+func (m *EncodedError) GetFormatVersion() uint32 {
+	if m != nil {
+		return m.FormatVersion
+	}
+	return 0
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*EncodedError) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -424,6 +436,43 @@ func (m *ErrnoPayload) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ErrnoPayload proto.InternalMessageInfo
 
+// EncodedErrors is the wire-encodable representation of a list of
+// errors, for example the per-item results of a batch operation.
+type EncodedErrors struct {
+	// Nil errors are represented by an EncodedError with neither
+	// a leaf nor a wrapper set.
+	Errors []EncodedError `protobuf:"bytes,1,rep,name=errors,proto3" json:"errors"`
+}
+
+func (m *EncodedErrors) Reset()         { *m = EncodedErrors{} }
+func (m *EncodedErrors) String() string { return proto.CompactTextString(m) }
+func (*EncodedErrors) ProtoMessage()    {}
+func (*EncodedErrors) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ddc818d0729874b8, []int{7}
+}
+func (m *EncodedErrors) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *EncodedErrors) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *EncodedErrors) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EncodedErrors.Merge(m, src)
+}
+func (m *EncodedErrors) XXX_Size() int {
+	return m.Size()
+}
+func (m *EncodedErrors) XXX_DiscardUnknown() {
+	xxx_messageInfo_EncodedErrors.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EncodedErrors proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterEnum("cockroach.errorspb.MessageType", MessageType_name, MessageType_value)
 	proto.RegisterType((*EncodedError)(nil), "cockroach.errorspb.EncodedError")
@@ -433,56 +482,59 @@ func init() {
 	proto.RegisterType((*ErrorTypeMark)(nil), "cockroach.errorspb.ErrorTypeMark")
 	proto.RegisterType((*StringsPayload)(nil), "cockroach.errorspb.StringsPayload")
 	proto.RegisterType((*ErrnoPayload)(nil), "cockroach.errorspb.ErrnoPayload")
+	proto.RegisterType((*EncodedErrors)(nil), "cockroach.errorspb.EncodedErrors")
 }
 
 func init() { proto.RegisterFile("errorspb/errors.proto", fileDescriptor_ddc818d0729874b8) }
 
 var fileDescriptor_ddc818d0729874b8 = []byte{
-	// 693 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x53, 0x41, 0x4f, 0x13, 0x41,
-	0x14, 0xde, 0xa5, 0x85, 0xb6, 0xaf, 0x05, 0xcb, 0x88, 0xc9, 0x42, 0x64, 0x29, 0xab, 0x89, 0x04,
-	0x75, 0x9b, 0xe0, 0xc1, 0xc4, 0x18, 0x13, 0xaa, 0x05, 0x4c, 0x28, 0x92, 0x05, 0xa3, 0xf1, 0xb2,
-	0x99, 0xb6, 0xd3, 0x65, 0xc2, 0xee, 0xce, 0x66, 0x66, 0x1b, 0xd9, 0x7f, 0x61, 0xe2, 0x5f, 0xf2,
-	0xc0, 0x91, 0x23, 0x27, 0xa3, 0x25, 0xfe, 0x04, 0x6f, 0x1e, 0xcc, 0xce, 0xee, 0xd8, 0xa2, 0x28,
-	0x07, 0x6f, 0x6f, 0xde, 0xfb, 0xbe, 0xf7, 0xde, 0xf7, 0xde, 0x1b, 0xb8, 0x45, 0x38, 0x67, 0x5c,
-	0x44, 0xdd, 0x66, 0x66, 0xd8, 0x11, 0x67, 0x31, 0x43, 0xa8, 0xc7, 0x7a, 0xc7, 0x9c, 0xe1, 0xde,
-	0x91, 0xad, 0x00, 0x4b, 0x8b, 0x1e, 0x63, 0x9e, 0x4f, 0x9a, 0x12, 0xd1, 0x1d, 0x0e, 0x9a, 0x38,
-	0x4c, 0x32, 0xf8, 0xd2, 0x82, 0xc7, 0x3c, 0x26, 0xcd, 0x66, 0x6a, 0x65, 0x5e, 0xeb, 0xa3, 0x0e,
-	0xb5, 0x76, 0xd8, 0x63, 0x7d, 0xd2, 0x6f, 0xa7, 0x49, 0xd0, 0x13, 0x28, 0xfa, 0x04, 0x0f, 0x0c,
-	0xbd, 0xa1, 0xaf, 0x55, 0x37, 0xee, 0xda, 0x7f, 0x16, 0xb1, 0x27, 0xf1, 0xbb, 0x04, 0x0f, 0x76,
-	0x34, 0x47, 0x72, 0xd0, 0x33, 0x28, 0xbd, 0xe7, 0x38, 0x8a, 0x08, 0x37, 0xa6, 0x24, 0xdd, 0xfa,
-	0x07, 0xfd, 0x4d, 0x86, 0xdc, 0xd1, 0x1c, 0x45, 0x6a, 0x95, 0x60, 0x5a, 0xa2, 0xac, 0x4f, 0x3a,
-	0xd4, 0x7f, 0xaf, 0x82, 0x0c, 0x28, 0x05, 0x44, 0x08, 0xec, 0x11, 0xd9, 0x5c, 0xc5, 0x51, 0x4f,
-	0xb4, 0x0d, 0xa5, 0x3e, 0x89, 0x31, 0xf5, 0x45, 0x5e, 0xf7, 0xde, 0x75, 0x6d, 0xbf, 0xc8, 0xe0,
-	0xad, 0xe2, 0xe9, 0xe7, 0x15, 0xcd, 0x51, 0x6c, 0xd4, 0x81, 0xf9, 0x60, 0xe8, 0xc7, 0x54, 0x72,
-	0xdc, 0x1e, 0x1e, 0x0a, 0x22, 0x8c, 0x42, 0xa3, 0xb0, 0x56, 0xdd, 0x68, 0x5c, 0x97, 0xd2, 0xa9,
-	0x8f, 0xa9, 0xcf, 0x25, 0xd3, 0xfa, 0xa1, 0xc3, 0xcd, 0x2b, 0xaa, 0xa2, 0x07, 0x80, 0x18, 0xa7,
-	0x1e, 0x0d, 0xb1, 0xef, 0xc6, 0x49, 0x44, 0xdc, 0x10, 0x07, 0x4a, 0x54, 0x5d, 0x45, 0x0e, 0x93,
-	0x88, 0xec, 0xe1, 0x80, 0xa0, 0x57, 0x70, 0x23, 0xeb, 0x47, 0x42, 0x03, 0xcc, 0x8f, 0x73, 0x95,
-	0xab, 0x57, 0xb6, 0x94, 0x1a, 0x29, 0xb7, 0x83, 0xf9, 0x71, 0xae, 0x6f, 0x96, 0x4c, 0x3a, 0xd1,
-	0x43, 0x40, 0x9c, 0x44, 0x8c, 0xc7, 0xb8, 0xeb, 0x13, 0x37, 0xc2, 0x89, 0xcf, 0x70, 0x5f, 0xca,
-	0xac, 0x38, 0xf3, 0xe3, 0xc8, 0x7e, 0x16, 0x40, 0x8f, 0xa1, 0x36, 0x18, 0xfa, 0xbe, 0xab, 0x46,
-	0x5c, 0x94, 0xc5, 0x17, 0xec, 0xec, 0xd4, 0x6c, 0x75, 0x6a, 0xf6, 0x66, 0x98, 0x38, 0xd5, 0x14,
-	0x99, 0xcb, 0xb4, 0xbe, 0xeb, 0x30, 0x77, 0x79, 0xd9, 0xe8, 0x29, 0x4c, 0xcb, 0xa9, 0xe6, 0xe7,
-	0x75, 0xed, 0x50, 0x73, 0x01, 0x19, 0x69, 0xf2, 0x02, 0xa6, 0xfe, 0x7a, 0x01, 0x85, 0xff, 0xba,
-	0x80, 0x16, 0xd4, 0xf2, 0x9c, 0x72, 0xdc, 0x52, 0xec, 0xdc, 0xc6, 0xca, 0x55, 0xd9, 0x3a, 0x19,
-	0x2e, 0x1d, 0xab, 0x53, 0x0d, 0xc6, 0x0f, 0x6b, 0x0f, 0x66, 0x2f, 0x6d, 0x01, 0xad, 0x40, 0x75,
-	0x80, 0x03, 0xea, 0x27, 0x93, 0x8b, 0x86, 0xcc, 0x25, 0x57, 0x7c, 0x1b, 0x2a, 0xe4, 0x24, 0x26,
-	0xa1, 0xa0, 0x2c, 0xcc, 0xa5, 0x8d, 0x1d, 0xd6, 0x3a, 0xcc, 0x1d, 0xc4, 0x9c, 0x86, 0x9e, 0x50,
-	0x2b, 0x31, 0xc6, 0x72, 0x75, 0xb9, 0x36, 0xf5, 0xb4, 0xbe, 0xa5, 0xff, 0x99, 0xf3, 0x90, 0x29,
-	0xe8, 0x32, 0x40, 0x7a, 0x51, 0x2e, 0x49, 0x9d, 0xb2, 0x74, 0xc1, 0xa9, 0xa4, 0x1e, 0x89, 0x42,
-	0x08, 0x8a, 0x98, 0xf7, 0x8e, 0xf2, 0xa2, 0xd2, 0x46, 0x77, 0x60, 0x96, 0x0a, 0x37, 0x22, 0x3c,
-	0xa0, 0x42, 0x76, 0x94, 0x8e, 0xb4, 0xec, 0xd4, 0xa8, 0xd8, 0xff, 0xe5, 0x43, 0x8b, 0x50, 0xa6,
-	0xc2, 0x25, 0x27, 0x54, 0xc4, 0x72, 0x48, 0x65, 0xa7, 0x44, 0x45, 0x3b, 0x7d, 0xa2, 0x06, 0xd4,
-	0xa8, 0x70, 0x43, 0x16, 0xe7, 0xe1, 0x69, 0x19, 0x06, 0x2a, 0xf6, 0x58, 0x9c, 0x21, 0x96, 0x01,
-	0xa8, 0x70, 0x63, 0x1a, 0x10, 0x36, 0x8c, 0x8d, 0x19, 0x19, 0xaf, 0x50, 0x71, 0x98, 0x39, 0xd0,
-	0xaa, 0x4c, 0x10, 0x93, 0x20, 0x62, 0x1c, 0xf3, 0xc4, 0x28, 0x49, 0x40, 0x95, 0x8a, 0x43, 0xe5,
-	0x5a, 0xbf, 0x0f, 0xd5, 0x89, 0xf9, 0x23, 0x80, 0x99, 0x7d, 0xa7, 0xbd, 0xf5, 0xf2, 0x6d, 0x5d,
-	0x43, 0x75, 0xa8, 0x6d, 0xbd, 0xde, 0xdd, 0x75, 0x3b, 0xed, 0x83, 0x83, 0xcd, 0xed, 0x76, 0x5d,
-	0x6f, 0xad, 0x9f, 0x7e, 0x35, 0xb5, 0xd3, 0x91, 0xa9, 0x9f, 0x8d, 0x4c, 0xfd, 0x7c, 0x64, 0xea,
-	0x5f, 0x46, 0xa6, 0xfe, 0xe1, 0xc2, 0xd4, 0xce, 0x2e, 0x4c, 0xed, 0xfc, 0xc2, 0xd4, 0xde, 0x95,
-	0xd5, 0x56, 0xbb, 0x33, 0xf2, 0x9e, 0x1f, 0xfd, 0x0c, 0x00, 0x00, 0xff, 0xff, 0xd7, 0x2c, 0x21,
-	0xe3, 0x75, 0x05, 0x00, 0x00,
+	// 733 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x53, 0x5d, 0x4f, 0x13, 0x4d,
+	0x14, 0xde, 0xa5, 0xa5, 0x1f, 0xa7, 0x1f, 0x6f, 0x99, 0x17, 0x93, 0x85, 0xc8, 0x52, 0x56, 0x8d,
+	0x04, 0xb5, 0x4d, 0xf0, 0xc2, 0xc4, 0x18, 0x12, 0xaa, 0x05, 0x4c, 0x28, 0x90, 0x05, 0x3f, 0xe2,
+	0xcd, 0x66, 0xda, 0x4e, 0xcb, 0x84, 0xdd, 0x9d, 0xcd, 0xcc, 0x56, 0xe9, 0xbf, 0xf0, 0xf7, 0x78,
+	0xed, 0x05, 0x97, 0x5c, 0x72, 0x65, 0xb4, 0xc4, 0x9f, 0xe0, 0x9d, 0x17, 0x66, 0x67, 0x76, 0x69,
+	0x51, 0x14, 0x13, 0xef, 0x66, 0x9e, 0xf3, 0x3c, 0xe7, 0xcc, 0x73, 0xce, 0x19, 0xb8, 0x41, 0x38,
+	0x67, 0x5c, 0x04, 0xed, 0xba, 0x3a, 0xd4, 0x02, 0xce, 0x42, 0x86, 0x50, 0x87, 0x75, 0x8e, 0x38,
+	0xc3, 0x9d, 0xc3, 0x5a, 0x42, 0x98, 0x9f, 0xeb, 0x33, 0xd6, 0x77, 0x49, 0x5d, 0x32, 0xda, 0x83,
+	0x5e, 0x1d, 0xfb, 0x43, 0x45, 0x9f, 0x9f, 0xed, 0xb3, 0x3e, 0x93, 0xc7, 0x7a, 0x74, 0x52, 0xa8,
+	0xf5, 0x41, 0x87, 0x62, 0xd3, 0xef, 0xb0, 0x2e, 0xe9, 0x36, 0xa3, 0x24, 0xe8, 0x31, 0xa4, 0x5d,
+	0x82, 0x7b, 0x86, 0x5e, 0xd5, 0x97, 0x0b, 0xab, 0xb7, 0x6b, 0xbf, 0x16, 0xa9, 0x4d, 0xf2, 0xb7,
+	0x09, 0xee, 0x6d, 0x69, 0xb6, 0xd4, 0xa0, 0x35, 0xc8, 0xbe, 0xe3, 0x38, 0x08, 0x08, 0x37, 0xa6,
+	0xa4, 0xdc, 0xfa, 0x83, 0xfc, 0x95, 0x62, 0x6e, 0x69, 0x76, 0x22, 0x42, 0x77, 0xa0, 0xdc, 0x63,
+	0xdc, 0xc3, 0xa1, 0xf3, 0x96, 0x70, 0x41, 0x99, 0x6f, 0xa4, 0xaa, 0xfa, 0x72, 0xc9, 0x2e, 0x29,
+	0xf4, 0xa5, 0x02, 0x1b, 0x59, 0x98, 0x96, 0xc9, 0xac, 0x8f, 0x3a, 0x54, 0x7e, 0x7e, 0x0c, 0x32,
+	0x20, 0xeb, 0x11, 0x21, 0x70, 0x9f, 0x48, 0x0f, 0x79, 0x3b, 0xb9, 0xa2, 0x4d, 0xc8, 0x76, 0x49,
+	0x88, 0xa9, 0x2b, 0xe2, 0xe7, 0xdd, 0xbd, 0xce, 0xdd, 0x33, 0x45, 0x6f, 0xa4, 0x4f, 0x3e, 0x2d,
+	0x6a, 0x76, 0xa2, 0x46, 0x2d, 0x98, 0xf1, 0x06, 0x6e, 0x48, 0xa5, 0xc6, 0xe9, 0xe0, 0x81, 0x20,
+	0xc2, 0x48, 0x55, 0x53, 0xcb, 0x85, 0xd5, 0xea, 0x75, 0x29, 0xed, 0xca, 0x58, 0xfa, 0x54, 0x2a,
+	0xad, 0xef, 0x3a, 0xfc, 0x7f, 0x45, 0x55, 0x74, 0x1f, 0x10, 0xe3, 0xb4, 0x4f, 0x7d, 0xec, 0x3a,
+	0xe1, 0x30, 0x20, 0x8e, 0x8f, 0xbd, 0xc4, 0x54, 0x25, 0x89, 0x1c, 0x0c, 0x03, 0xb2, 0x83, 0x3d,
+	0x82, 0x76, 0xe1, 0x3f, 0xf5, 0x1e, 0x49, 0xf5, 0x30, 0x3f, 0x8a, 0x5d, 0x2e, 0x5d, 0xf9, 0xa4,
+	0xe8, 0x10, 0x69, 0x5b, 0x98, 0x1f, 0xc5, 0xfe, 0x4a, 0x64, 0x12, 0x44, 0x0f, 0x00, 0x71, 0x12,
+	0x30, 0x1e, 0xe2, 0xb6, 0x4b, 0x9c, 0x00, 0x0f, 0x5d, 0x86, 0xbb, 0xd2, 0x66, 0xde, 0x9e, 0x19,
+	0x47, 0xf6, 0x54, 0x00, 0x3d, 0x82, 0x62, 0x6f, 0xe0, 0xba, 0x4e, 0xd2, 0xe2, 0xb4, 0x2c, 0x3e,
+	0x5b, 0x53, 0x1b, 0x59, 0x4b, 0x36, 0xb2, 0xb6, 0xee, 0x0f, 0xed, 0x42, 0xc4, 0x8c, 0x6d, 0x5a,
+	0xdf, 0x74, 0x28, 0x5f, 0xde, 0x09, 0xf4, 0x04, 0xa6, 0x65, 0x57, 0xe3, 0x2d, 0xbc, 0xb6, 0xa9,
+	0xb1, 0x01, 0x25, 0x9a, 0xdc, 0x80, 0xa9, 0xdf, 0x6e, 0x40, 0xea, 0x9f, 0x36, 0xa0, 0x01, 0xc5,
+	0x38, 0xa7, 0x6c, 0xb7, 0x34, 0x5b, 0x5e, 0x5d, 0xbc, 0x2a, 0x5b, 0x4b, 0xf1, 0xa2, 0xb6, 0xda,
+	0x05, 0x6f, 0x7c, 0xb1, 0x76, 0xa0, 0x74, 0x69, 0x0a, 0x68, 0x11, 0x0a, 0x3d, 0xec, 0x51, 0x77,
+	0x38, 0x39, 0x68, 0x50, 0x90, 0x1c, 0xf1, 0x4d, 0xc8, 0x93, 0xe3, 0x90, 0xf8, 0xf2, 0x6b, 0x28,
+	0x6b, 0x63, 0xc0, 0x5a, 0x81, 0xf2, 0x7e, 0xc8, 0xa9, 0xdf, 0x17, 0xc9, 0x48, 0x8c, 0xb1, 0x5d,
+	0x5d, 0x8e, 0x2d, 0xb9, 0x5a, 0x5f, 0xa3, 0x6f, 0xcf, 0xb9, 0xcf, 0x12, 0xea, 0x02, 0x40, 0xb4,
+	0x51, 0x0e, 0x89, 0x40, 0x59, 0x3a, 0x65, 0xe7, 0x23, 0x44, 0xb2, 0x10, 0x82, 0x34, 0xe6, 0x9d,
+	0xc3, 0xb8, 0xa8, 0x3c, 0xa3, 0x5b, 0x50, 0xa2, 0xc2, 0x09, 0x08, 0xf7, 0xa8, 0xb8, 0xf8, 0xac,
+	0x39, 0xbb, 0x48, 0xc5, 0xde, 0x05, 0x86, 0xe6, 0x20, 0x47, 0x85, 0x43, 0x8e, 0xa9, 0x08, 0x65,
+	0x93, 0x72, 0x76, 0x96, 0x8a, 0x66, 0x74, 0x45, 0x55, 0x28, 0x52, 0xe1, 0xf8, 0x2c, 0x8c, 0xc3,
+	0xd3, 0x32, 0x0c, 0x54, 0xec, 0xb0, 0x50, 0x31, 0x16, 0x00, 0xa8, 0x70, 0x42, 0xea, 0x11, 0x36,
+	0x08, 0x8d, 0x8c, 0x8c, 0xe7, 0xa9, 0x38, 0x50, 0x00, 0x5a, 0x92, 0x09, 0x42, 0xe2, 0x05, 0x8c,
+	0x63, 0x3e, 0x34, 0xb2, 0x92, 0x50, 0xa0, 0xe2, 0x20, 0x81, 0xac, 0x5d, 0x28, 0x4d, 0x4e, 0x53,
+	0xa0, 0x35, 0xc8, 0xa8, 0xc9, 0xc8, 0x8e, 0xfc, 0xfd, 0x6a, 0xc5, 0xaa, 0x95, 0x7b, 0x50, 0x98,
+	0x18, 0x28, 0x02, 0xc8, 0xec, 0xd9, 0xcd, 0x8d, 0xe7, 0xaf, 0x2b, 0x1a, 0xaa, 0x40, 0x71, 0xe3,
+	0xc5, 0xf6, 0xb6, 0xd3, 0x6a, 0xee, 0xef, 0xaf, 0x6f, 0x36, 0x2b, 0x7a, 0x63, 0xe5, 0xe4, 0x8b,
+	0xa9, 0x9d, 0x8c, 0x4c, 0xfd, 0x74, 0x64, 0xea, 0x67, 0x23, 0x53, 0xff, 0x3c, 0x32, 0xf5, 0xf7,
+	0xe7, 0xa6, 0x76, 0x7a, 0x6e, 0x6a, 0x67, 0xe7, 0xa6, 0xf6, 0x26, 0x97, 0xd4, 0x6c, 0x67, 0xe4,
+	0x07, 0x79, 0xf8, 0x63, 0x00, 0x3f, 0x40, 0xc3, 0x3f, 0xed, 0x05, 0x00, 0x00,
 }
 
 func (m *EncodedError) Marshal() (dAtA []byte, err error) {
@@ -505,6 +557,11 @@ func (m *EncodedError) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.FormatVersion != 0 {
+		i = encodeVarintErrors(dAtA, i, uint64(m.FormatVersion))
+		i--
+		dAtA[i] = 0x18
+	}
 	if m.Error != nil {
 		{
 			size := m.Error.Size()
@@ -883,6 +940,43 @@ func (m *ErrnoPayload) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *EncodedErrors) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EncodedErrors) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *EncodedErrors) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Errors) > 0 {
+		for iNdEx := len(m.Errors) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Errors[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintErrors(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintErrors(dAtA []byte, offset int, v uint64) int {
 	offset -= sovErrors(v)
 	base := offset
@@ -903,6 +997,9 @@ func (m *EncodedError) Size() (n int) {
 	if m.Error != nil {
 		n += m.Error.Size()
 	}
+	if m.FormatVersion != 0 {
+		n += 1 + sovErrors(uint64(m.FormatVersion))
+	}
 	return n
 }
 
@@ -1059,6 +1156,21 @@ func (m *ErrnoPayload) Size() (n int) {
 	return n
 }
 
+func (m *EncodedErrors) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Errors) > 0 {
+		for _, e := range m.Errors {
+			l = e.Size()
+			n += 1 + l + sovErrors(uint64(l))
+		}
+	}
+	return n
+}
+
 func sovErrors(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -1164,6 +1276,25 @@ func (m *EncodedError) Unmarshal(dAtA []byte) error {
 			}
 			m.Error = &EncodedError_Wrapper{v}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FormatVersion", wireType)
+			}
+			m.FormatVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowErrors
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FormatVersion |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipErrors(dAtA[iNdEx:])
@@ -2081,6 +2212,90 @@ func (m *ErrnoPayload) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *EncodedErrors) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowErrors
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: EncodedErrors: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: EncodedErrors: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Errors", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowErrors
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthErrors
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthErrors
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Errors = append(m.Errors, EncodedError{})
+			if err := m.Errors[len(m.Errors)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipErrors(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthErrors
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipErrors(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -0,0 +1,134 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package errreason provides application-level reason codes, for
+// example "INSUFFICIENT_FUNDS". Reason codes describe why an
+// operation failed in the terms of the application domain. They are
+// distinct from, and complementary to, the transport-level codes
+// attached with e.g. the extgrpc and exthttp packages.
+package errreason
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errorspb"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithReason annotates an error with an application-level reason
+// code and an optional free-form detail. The code must not contain
+// PII: it is considered safe for reporting. The detail is not.
+//
+// If the annotation is applied multiple times, the outermost
+// reason wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`, shows the code only.
+// - via `GetReason()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports, with the detail redacted.
+func WithReason(err error, code string, detail string) error {
+	if err == nil {
+		return nil
+	}
+	return &withReason{cause: err, code: code, detail: detail}
+}
+
+// GetReason retrieves the outermost reason annotation in the error's
+// causal chain, or false if there is none.
+func GetReason(err error) (code, detail string, ok bool) {
+	v, ok := markers.If(err, func(err error) (interface{}, bool) {
+		if w, ok := err.(*withReason); ok {
+			return w, true
+		}
+		return nil, false
+	})
+	if !ok {
+		return "", "", false
+	}
+	w := v.(*withReason)
+	return w.code, w.detail, true
+}
+
+type withReason struct {
+	cause  error
+	code   string
+	detail string
+}
+
+var _ error = (*withReason)(nil)
+var _ errbase.SafeDetailer = (*withReason)(nil)
+var _ fmt.Formatter = (*withReason)(nil)
+var _ errbase.SafeFormatter = (*withReason)(nil)
+
+func (w *withReason) Error() string { return w.cause.Error() }
+func (w *withReason) Cause() error  { return w.cause }
+func (w *withReason) Unwrap() error { return w.cause }
+
+func (w *withReason) SafeDetails() []string { return []string{w.code} }
+
+func (w *withReason) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withReason) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		if w.detail == "" {
+			p.Printf("reason: %s", redact.Safe(w.code))
+		} else {
+			p.Printf("reason: %s (%s)", redact.Safe(w.code), w.detail)
+		}
+	}
+	return w.cause
+}
+
+func encodeWithReason(_ context.Context, err error) (string, []string, proto.Message) {
+	w := err.(*withReason)
+	return "", w.SafeDetails(), &errorspb.StringPayload{Msg: w.detail}
+}
+
+func decodeWithReason(
+	_ context.Context, cause error, _ string, details []string, payload proto.Message,
+) error {
+	m, ok := payload.(*errorspb.StringPayload)
+	if !ok || len(details) == 0 {
+		// If this ever happens, this means some version of the library
+		// (presumably future) changed the payload type, and we're
+		// receiving this here. In this case, give up and let
+		// DecodeError use the opaque type.
+		return nil
+	}
+	return &withReason{cause: cause, code: details[0], detail: m.Msg}
+}
+
+func init() {
+	errbase.RegisterWrapperEncoder(errbase.GetTypeKey((*withReason)(nil)), encodeWithReason)
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withReason)(nil)), decodeWithReason)
+	errbase.RegisterStrippableAnnotation(errbase.GetTypeKey((*withReason)(nil)))
+	errutil.RegisterCodeExtractor("reason", getReasonCode)
+	errutil.RegisterCodeWrapper("reason", func(err, from error) error {
+		code, detail, _ := GetReason(from)
+		return WithReason(err, code, detail)
+	})
+}
+
+// getReasonCode is registered with errutil.RegisterCodeExtractor and
+// reports the outermost reason code.
+func getReasonCode(err error) (string, bool) {
+	code, _, ok := GetReason(err)
+	return code, ok
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errreason_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errreason"
+	"github.com/cockroachdb/errors/extgrpc"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+	"google.golang.org/grpc/codes"
+)
+
+func TestWithReason(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("woo")
+	_, _, ok := errreason.GetReason(origErr)
+	tt.Check(!ok)
+	tt.Check(errreason.WithReason(nil, "INSUFFICIENT_FUNDS", "") == nil)
+
+	// The outermost reason wins.
+	err := errreason.WithReason(origErr, "ACCOUNT_LOCKED", "")
+	err = errreason.WithReason(err, "INSUFFICIENT_FUNDS", "balance is 12")
+
+	code, detail, ok := errreason.GetReason(err)
+	tt.Check(ok)
+	tt.CheckStringEqual(code, "INSUFFICIENT_FUNDS")
+	tt.CheckStringEqual(detail, "balance is 12")
+
+	tt.CheckStringEqual(err.Error(), "woo")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", err), `woo
+(1) reason: INSUFFICIENT_FUNDS (balance is 12)
+Wraps: (2) reason: ACCOUNT_LOCKED
+Wraps: (3) woo
+Error types: (1) *errreason.withReason (2) *errreason.withReason (3) *errors.errorString`)
+
+	// The code is safe for reporting, the detail is not.
+	tt.CheckDeepEqual(errbase.GetSafeDetails(err).SafeDetails, []string{"INSUFFICIENT_FUNDS"})
+	tt.CheckStringEqual(string(redact.Sprintf("%+v", err).Redact()), `‹×›
+(1) reason: INSUFFICIENT_FUNDS (‹×›)
+Wraps: (2) reason: ACCOUNT_LOCKED
+Wraps: (3) ‹×›
+Error types: (1) *errreason.withReason (2) *errreason.withReason (3) *errors.errorString`)
+
+	// Simulate a network transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+
+	code, detail, ok = errreason.GetReason(newErr)
+	tt.Check(ok)
+	tt.CheckStringEqual(code, "INSUFFICIENT_FUNDS")
+	tt.CheckStringEqual(detail, "balance is 12")
+	code, detail, ok = errreason.GetReason(errbase.UnwrapOnce(newErr))
+	tt.Check(ok)
+	tt.CheckStringEqual(code, "ACCOUNT_LOCKED")
+	tt.CheckStringEqual(detail, "")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", newErr), fmt.Sprintf("%+v", err))
+}
+
+// This test demonstrates that reason codes are independent from
+// transport codes.
+func TestReasonAndTransportCode(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := errreason.WithReason(goErr.New("woo"), "INSUFFICIENT_FUNDS", "")
+	err = extgrpc.WrapWithGrpcCode(err, codes.FailedPrecondition)
+
+	code, _, ok := errreason.GetReason(err)
+	tt.Check(ok)
+	tt.CheckStringEqual(code, "INSUFFICIENT_FUNDS")
+	tt.CheckEqual(extgrpc.GetGrpcCode(err), codes.FailedPrecondition)
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errors
+
+import "github.com/cockroachdb/errors/errreason"
+
+// WithReason annotates an error with an application-level reason
+// code and an optional free-form detail. The code must not contain
+// PII: it is considered safe for reporting. The detail is not.
+//
+// Reason codes describe why an operation failed in the terms of the
+// application domain, for example "INSUFFICIENT_FUNDS". They are
+// distinct from transport-level codes, e.g. gRPC or HTTP codes.
+//
+// If the annotation is applied multiple times, the outermost
+// reason wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`, shows the code only.
+// - via `GetReason()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports, with the detail redacted.
+func WithReason(err error, code string, detail string) error {
+	return errreason.WithReason(err, code, detail)
+}
+
+// GetReason retrieves the outermost reason annotation in the error's
+// causal chain, or false if there is none.
+func GetReason(err error) (code, detail string, ok bool) { return errreason.GetReason(err) }
@@ -39,6 +39,37 @@ func AssertionFailedWithDepthf(depth int, format string, args ...interface{}) er
 	return err
 }
 
+// Assertf returns nil if cond is true, and otherwise an assertion
+// failure as per AssertionFailedf(). This is a shorthand for:
+//
+//	if !cond {
+//	   return AssertionFailedf(format, args...)
+//	}
+//
+// The format string should describe the condition that was asserted.
+// As with AssertionFailedf, it is considered safe for reporting.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`, shows redacted strings.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func Assertf(cond bool, format string, args ...interface{}) error {
+	if cond {
+		return nil
+	}
+	return AssertionFailedWithDepthf(1, format, args...)
+}
+
+// AssertWithDepthf is like Assertf except the depth to capture the
+// stack trace is configurable.
+// See the doc of `Assertf()` for more details.
+func AssertWithDepthf(depth int, cond bool, format string, args ...interface{}) error {
+	if cond {
+		return nil
+	}
+	return AssertionFailedWithDepthf(1+depth, format, args...)
+}
+
 // HandleAsAssertionFailure hides an error and turns it into
 // an assertion failure. Both details from the original error and the
 // context of the caller are preserved. The original error is not
@@ -0,0 +1,56 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/assert"
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/withstack"
+)
+
+func TestAssertf(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	// A true condition produces no error.
+	tt.CheckEqual(errutil.Assertf(true, "x == %d", 1), nil)
+
+	// A false condition produces an assertion failure.
+	err := errutil.Assertf(false, "x == %d, got %s", 1, "two")
+	tt.Check(err != nil)
+	tt.Check(assert.IsAssertionFailure(err))
+	tt.Check(withstack.HasStack(err))
+	tt.CheckStringEqual(err.Error(), "x == 1, got two")
+
+	// The stack trace starts at the caller.
+	file, _, fn, ok := withstack.GetOneLineSource(err)
+	tt.Check(ok)
+	tt.Check(strings.HasSuffix(file, "assertions_test.go"))
+	tt.CheckStringEqual(fn, "TestAssertf")
+
+	// The asserted condition is reportable, with the
+	// arguments redacted.
+	found := false
+	for _, d := range errbase.GetAllSafeDetails(err) {
+		for _, s := range d.SafeDetails {
+			found = found || s == "x == ×, got ×"
+		}
+	}
+	tt.Check(found)
+}
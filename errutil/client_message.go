@@ -0,0 +1,60 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+)
+
+// UserFacingMessager can be implemented by errors that carry an
+// explicit message intended for the end user of an API. It is
+// used by ClientMessage().
+type UserFacingMessager interface {
+	UserFacingMessage() string
+}
+
+// ClientMessage computes the message to present to the client of an
+// API for the given error. It uses, in order of precedence:
+//
+//   - the outermost explicit user-facing message in the causal chain,
+//     i.e. an error that implements UserFacingMessager;
+//   - the safe part of the message of the leaf error, with the
+//     unsafe parts redacted, if there is any safe part;
+//   - the generic message provided by the caller.
+//
+// The empty string is returned for a nil error.
+func ClientMessage(err error, generic string) string {
+	if err == nil {
+		return ""
+	}
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		if m, ok := c.(UserFacingMessager); ok {
+			if msg := m.UserFacingMessage(); msg != "" {
+				return msg
+			}
+		}
+	}
+	leaf := errbase.UnwrapAll(err)
+	msg := redact.Sprint(leaf).Redact().StripMarkers()
+	if msg == "" || msg == redactedLeafMessage {
+		return generic
+	}
+	return msg
+}
+
+// redactedLeafMessage is the safe message of a leaf error whose
+// message is entirely unsafe.
+var redactedLeafMessage = redact.RedactableString(redact.RedactedMarker()).StripMarkers()
@@ -0,0 +1,64 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	goErr "errors"
+	"testing"
+
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+type userFacingErr struct {
+	cause error
+	msg   string
+}
+
+func (e *userFacingErr) Error() string             { return e.cause.Error() }
+func (e *userFacingErr) Unwrap() error             { return e.cause }
+func (e *userFacingErr) UserFacingMessage() string { return e.msg }
+
+var _ errutil.UserFacingMessager = (*userFacingErr)(nil)
+
+func TestClientMessage(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	const generic = "internal error"
+
+	tt.CheckStringEqual(errutil.ClientMessage(nil, generic), "")
+
+	// An explicit user-facing message has precedence. The outermost
+	// one wins.
+	leaf := errutil.Newf("cannot open %s", "secret.txt")
+	var err error = &userFacingErr{
+		cause: errutil.Wrap(&userFacingErr{cause: leaf, msg: "inner"}, "wrap"),
+		msg:   "please try again",
+	}
+	tt.CheckStringEqual(errutil.ClientMessage(err, generic), "please try again")
+
+	// An empty user-facing message is ignored.
+	err = &userFacingErr{cause: &userFacingErr{cause: leaf, msg: "inner"}}
+	tt.CheckStringEqual(errutil.ClientMessage(err, generic), "inner")
+
+	// Otherwise, the safe part of the leaf message is used. Wrapper
+	// prefixes are not included.
+	err = errutil.Wrap(leaf, "wrap")
+	tt.CheckStringEqual(errutil.ClientMessage(err, generic), "cannot open ×")
+
+	// If the leaf message has no safe part, the generic message is used.
+	err = errutil.Wrap(goErr.New("secret.txt"), "wrap")
+	tt.CheckStringEqual(errutil.ClientMessage(err, generic), generic)
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"sort"
+	"strconv"
+)
+
+// GetAllCodes collects the code-style annotations attached to an
+// error, keyed by their kind, with the value that wins for each kind
+// as per the corresponding accessor. This is meant for structured
+// loggers, to emit all the classification fields in one call.
+//
+// The exit code attached with WithExitCode() is reported under the
+// kind "exit". Other kinds are provided by the packages that define
+// them using RegisterCodeExtractor(). For example, the extgrpc,
+// exthttp, errclass and errreason packages provide the kinds "grpc",
+// "http", "category" and "reason", respectively.
+//
+// A nil map is returned for a nil error.
+func GetAllCodes(err error) map[string]string {
+	if err == nil {
+		return nil
+	}
+	res := map[string]string{}
+	if code, ok := GetExitCode(err); ok {
+		res["exit"] = strconv.Itoa(code)
+	}
+	for kind, fn := range codeExtractors {
+		if code, ok := fn(err); ok {
+			res[kind] = code
+		}
+	}
+	return res
+}
+
+// RegisterCodeExtractor registers a function that is used by
+// GetAllCodes() to report the code of the given kind. The function
+// receives the error passed to GetAllCodes() and is responsible for
+// inspecting its causes. It returns false if the error carries no
+// code of that kind. A previous registration for the same kind is
+// replaced.
+//
+// This is meant to be called from an init() function.
+func RegisterCodeExtractor(kind string, fn func(err error) (string, bool)) {
+	codeExtractors[kind] = fn
+}
+
+// codeExtractors is the registry for RegisterCodeExtractor.
+var codeExtractors = map[string]func(err error) (string, bool){}
+
+// RegisterCodeWrapper registers a function that is used by Merge() to
+// copy the code of the given kind from one error to another. The
+// function receives the error to annotate and the error carrying the
+// code, as reported by the function registered for the same kind
+// with RegisterCodeExtractor(), and returns the annotated error. A
+// previous registration for the same kind is replaced.
+//
+// This is meant to be called from an init() function.
+func RegisterCodeWrapper(kind string, fn func(err, from error) error) {
+	codeWrappers[kind] = fn
+}
+
+// codeWrappers is the registry for RegisterCodeWrapper.
+var codeWrappers = map[string]func(err, from error) error{}
+
+// copyMissingCodes annotates err with the codes of from, for the
+// kinds that are not in codes. The kinds are processed in
+// alphabetical order, so that the result is deterministic.
+func copyMissingCodes(err error, codes map[string]string, from error) error {
+	fromCodes := GetAllCodes(from)
+	kinds := make([]string, 0, len(fromCodes))
+	for kind := range fromCodes {
+		if _, ok := codes[kind]; !ok {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		if kind == "exit" {
+			code, _ := GetExitCode(from)
+			err = WithExitCode(err, code)
+		} else if fn, ok := codeWrappers[kind]; ok {
+			err = fn(err, from)
+		}
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	goErr "errors"
+	"net/http"
+	"testing"
+
+	"github.com/cockroachdb/errors/errclass"
+	"github.com/cockroachdb/errors/errreason"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/extgrpc"
+	"github.com/cockroachdb/errors/exthttp"
+	"github.com/cockroachdb/errors/testutils"
+	"google.golang.org/grpc/codes"
+)
+
+func TestGetAllCodes(t *testing.T) {
+	tt := testutils.T{T: t}
+	defer errclass.TestingWithEmptyCategories()()
+	errclass.DefineCategories("storage", "network")
+
+	tt.Check(errutil.GetAllCodes(nil) == nil)
+	tt.CheckDeepEqual(errutil.GetAllCodes(goErr.New("woo")), map[string]string{})
+
+	err := goErr.New("woo")
+	err = errclass.WithCategory(err, "storage")
+	err = errreason.WithReason(err, "INSUFFICIENT_FUNDS", "balance is 12")
+	err = extgrpc.WrapWithGrpcCode(err, codes.FailedPrecondition)
+	err = exthttp.WrapWithHTTPCode(err, http.StatusConflict)
+	err = errutil.WithExitCode(err, 3)
+	// The winning value of each kind is reported.
+	err = errclass.WithCategory(err, "network")
+	err = extgrpc.WrapWithGrpcCode(err, codes.Aborted)
+
+	tt.CheckDeepEqual(errutil.GetAllCodes(err), map[string]string{
+		"category": "network",
+		"exit":     "3",
+		"grpc":     "Aborted",
+		"http":     "409",
+		"reason":   "INSUFFICIENT_FUNDS",
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextWithError supports the "capture and retrieve later" pattern,
+// where an error occurring deep in a handler is retrieved by an
+// outer layer, e.g. for logging in a deferred function, without
+// threading it through return values.
+//
+// The outer layer calls ContextWithError() first, possibly with a
+// nil error, to install a slot in the context that it passes down.
+// When ContextWithError() is subsequently called with a context
+// derived from it, the error is stored in that slot and the context
+// is returned unchanged, so that ErrorFromContext() on the outer
+// context returns the last error captured.
+func ContextWithError(ctx context.Context, err error) context.Context {
+	if s, ok := ctx.Value(errorSlotKey{}).(*errorSlot); ok {
+		s.set(err)
+		return ctx
+	}
+	return context.WithValue(ctx, errorSlotKey{}, &errorSlot{err: err})
+}
+
+// ErrorFromContext retrieves the last error captured with
+// ContextWithError() in the context, or nil if there is none.
+func ErrorFromContext(ctx context.Context) error {
+	if s, ok := ctx.Value(errorSlotKey{}).(*errorSlot); ok {
+		return s.get()
+	}
+	return nil
+}
+
+// errorSlotKey is the context key for errorSlot.
+type errorSlotKey struct{}
+
+// errorSlot holds the error captured by ContextWithError. It is
+// shared by all the contexts derived from the one where it was
+// installed, possibly across goroutines.
+type errorSlot struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (s *errorSlot) set(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *errorSlot) get() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
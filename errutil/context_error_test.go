@@ -0,0 +1,52 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"testing"
+
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/logtags"
+)
+
+func TestContextWithError(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	// Absent case.
+	ctx := context.Background()
+	tt.Check(errutil.ErrorFromContext(ctx) == nil)
+
+	// Simple set/get.
+	err := goErr.New("hello")
+	tt.Check(errutil.ErrorFromContext(errutil.ContextWithError(ctx, err)) == err)
+
+	// Capture and retrieve later: the outer layer installs a slot,
+	// and the error captured with a derived context is visible to it.
+	outer := errutil.ContextWithError(ctx, nil)
+	tt.Check(errutil.ErrorFromContext(outer) == nil)
+	func(ctx context.Context) {
+		ctx = logtags.AddTag(ctx, "inner", nil)
+		tt.Check(errutil.ContextWithError(ctx, err) == ctx)
+	}(outer)
+	tt.Check(errutil.ErrorFromContext(outer) == err)
+
+	// The last error captured wins.
+	err2 := goErr.New("world")
+	errutil.ContextWithError(outer, err2)
+	tt.Check(errutil.ErrorFromContext(outer) == err2)
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import "github.com/cockroachdb/errors/hintdetail"
+
+// debugMode can be configured using SetDebugMode() below.
+var debugMode bool
+
+// SetDebugMode configures the behavior of WithDebugDetail(). Debug
+// mode is disabled by default. This should be called during
+// initialization, e.g. in development builds.
+func SetDebugMode(enabled bool) {
+	debugMode = enabled
+}
+
+// WithDebugDetail decorates an error with a textual detail computed
+// by fn, like WithDetail(), but only in debug mode (see
+// SetDebugMode()). When debug mode is disabled, the error is returned
+// unchanged and fn is not called. This makes it possible to include
+// expensive diagnostic strings in development without paying for
+// them in production.
+//
+// Detail is shown:
+// - when formatting with `%+v`.
+// - with `GetAllDetails()` / `FlattenDetails()`.
+func WithDebugDetail(err error, fn func() string) error {
+	if err == nil || !debugMode {
+		return err
+	}
+	return hintdetail.WithDetail(err, fn())
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/hintdetail"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+func TestWithDebugDetail(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	calls := 0
+	fn := func() string {
+		calls++
+		return "expensive"
+	}
+	origErr := goErr.New("woo")
+
+	// Debug mode is disabled by default.
+	err := errutil.WithDebugDetail(origErr, fn)
+	tt.Check(err == origErr)
+	tt.CheckEqual(calls, 0)
+
+	errutil.SetDebugMode(true)
+	defer errutil.SetDebugMode(false)
+
+	err = errutil.WithDebugDetail(origErr, fn)
+	tt.CheckEqual(calls, 1)
+	tt.CheckStringEqual(err.Error(), "woo")
+	tt.CheckDeepEqual(hintdetail.GetAllDetails(err), []string{"expensive"})
+	tt.CheckContains(fmt.Sprintf("%+v", err), "expensive")
+
+	tt.Check(errutil.WithDebugDetail(nil, fn) == nil)
+	tt.CheckEqual(calls, 1)
+
+	errutil.SetDebugMode(false)
+	tt.Check(errutil.WithDebugDetail(origErr, fn) == origErr)
+	tt.CheckEqual(calls, 1)
+}
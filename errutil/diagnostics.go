@@ -0,0 +1,159 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors/domains"
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/hintdetail"
+	"github.com/cockroachdb/errors/issuelink"
+	"github.com/cockroachdb/errors/telemetrykeys"
+	"github.com/cockroachdb/errors/withstack"
+)
+
+// ErrorDiagnostics aggregates the annotations attached to an error.
+// It is populated by Diagnostics().
+type ErrorDiagnostics struct {
+	// Hints is the result of GetAllHints().
+	Hints []string
+	// Details is the result of GetAllDetails().
+	Details []string
+	// IssueLinks is the result of GetAllIssueLinks().
+	IssueLinks []issuelink.IssueLink
+	// TelemetryKeys is the result of GetTelemetryKeys(), sorted.
+	TelemetryKeys []string
+	// Domain is the result of GetDomain().
+	Domain domains.Domain
+	// Domains is the result of GetAllDomains().
+	Domains []domains.Domain
+	// ExitCode and HasExitCode are the result of GetExitCode().
+	ExitCode    int
+	HasExitCode bool
+	// Duration and HasDuration are the result of GetDuration().
+	Duration    time.Duration
+	HasDuration bool
+	// Codes is the result of GetAllCodes(), for example the gRPC
+	// and HTTP codes under the kinds "grpc" and "http".
+	Codes map[string]string
+	// Stacks contains the stack traces found in the error and its
+	// causes, outermost first.
+	Stacks []*withstack.ReportableStackTrace
+}
+
+// Diagnostics collects all the hints, details, issue links,
+// telemetry keys, domains, codes and stack traces attached to an
+// error into a single ErrorDiagnostics value. This is a convenience
+// for tools that need to dump everything known about an error,
+// and avoids calling each of the accessors separately.
+//
+// The error is traversed once. Unlike the individual accessors, the
+// branches of multi-cause errors are inspected too, after the layers
+// above them. The fields that hold a single value, like Domain and
+// ExitCode, keep the outermost value found.
+//
+// The zero value is returned for a nil error.
+func Diagnostics(err error) ErrorDiagnostics {
+	var d ErrorDiagnostics
+	if err == nil {
+		return d
+	}
+	d.Domain = domains.NoDomain
+	var w diagnosticsWalker
+	w.walk(&d, err)
+	for k := range w.keys {
+		d.TelemetryKeys = append(d.TelemetryKeys, k)
+	}
+	sort.Strings(d.TelemetryKeys)
+	d.Codes = map[string]string{}
+	if d.HasExitCode {
+		d.Codes["exit"] = strconv.Itoa(d.ExitCode)
+	}
+	for kind, fn := range codeExtractors {
+		if code, ok := fn(err); ok {
+			d.Codes[kind] = code
+		}
+	}
+	return d
+}
+
+// diagnosticsWalker holds the state of Diagnostics() during the
+// traversal.
+type diagnosticsWalker struct {
+	hasDomain bool
+	hints     map[string]struct{}
+	keys      map[string]struct{}
+}
+
+// walk populates d with the annotations of err and its causes. The
+// values reported outermost first are collected before visiting the
+// causes, and the hints and details, which are reported innermost
+// first, after.
+func (w *diagnosticsWalker) walk(d *ErrorDiagnostics, err error) {
+	if link, ok := issuelink.GetIssueLink(err); ok {
+		d.IssueLinks = append(d.IssueLinks, link)
+	}
+	for _, k := range telemetrykeys.GetLayerTelemetryKeys(err) {
+		if w.keys == nil {
+			w.keys = map[string]struct{}{}
+		}
+		w.keys[k] = struct{}{}
+	}
+	if dom, ok := domains.GetLayerDomain(err); ok {
+		if !w.hasDomain {
+			d.Domain, w.hasDomain = dom, true
+		}
+		if dom != domains.NoDomain {
+			d.Domains = append(d.Domains, dom)
+		}
+	}
+	if e, ok := err.(*withExitCode); ok && !d.HasExitCode {
+		d.ExitCode, d.HasExitCode = e.code, true
+	}
+	if e, ok := err.(*withDuration); ok && !d.HasDuration {
+		d.Duration, d.HasDuration = e.duration, true
+	}
+	if st := withstack.GetReportableStackTrace(err); st != nil {
+		d.Stacks = append(d.Stacks, st)
+	}
+
+	if c := errbase.UnwrapOnce(err); c != nil {
+		w.walk(d, c)
+	} else {
+		for _, c := range errbase.UnwrapMulti(err) {
+			w.walk(d, c)
+		}
+	}
+
+	if h, ok := err.(hintdetail.ErrorHinter); ok {
+		if hint := h.ErrorHint(); hint != "" {
+			if _, seen := w.hints[hint]; !seen {
+				if w.hints == nil {
+					w.hints = map[string]struct{}{}
+				}
+				w.hints[hint] = struct{}{}
+				d.Hints = append(d.Hints, hint)
+			}
+		}
+	}
+	if e, ok := err.(hintdetail.ErrorDetailer); ok {
+		if detail := e.ErrorDetail(); detail != "" {
+			d.Details = append(d.Details, detail)
+		}
+	}
+}
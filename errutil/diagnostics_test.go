@@ -0,0 +1,98 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	goErr "errors"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors/domains"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/exthttp"
+	"github.com/cockroachdb/errors/hintdetail"
+	"github.com/cockroachdb/errors/issuelink"
+	"github.com/cockroachdb/errors/join"
+	"github.com/cockroachdb/errors/telemetrykeys"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/withstack"
+)
+
+func TestDiagnostics(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.CheckDeepEqual(errutil.Diagnostics(nil), errutil.ErrorDiagnostics{})
+
+	myDomain := domains.NamedDomain("mydomain")
+	link := issuelink.IssueLink{IssueURL: "https://example.com/1", Detail: "sub"}
+
+	err := goErr.New("woo")
+	err = withstack.WithStack(err)
+	err = hintdetail.WithHint(err, "try again")
+	err = hintdetail.WithDetail(err, "some detail")
+	err = issuelink.WithIssueLink(err, link)
+	err = telemetrykeys.WithTelemetry(err, "b", "a")
+	err = domains.WithDomain(err, domains.NamedDomain("inner"))
+	err = domains.WithDomain(err, myDomain)
+	err = errutil.WithExitCode(err, 42)
+	err = exthttp.WrapWithHTTPCode(err, 404)
+	err = errutil.WithDuration(err, 3*time.Second)
+	err = withstack.WithStack(err)
+
+	d := errutil.Diagnostics(err)
+	tt.CheckDeepEqual(d.Hints, hintdetail.GetAllHints(err))
+	tt.Assert(len(d.Hints) == 2)
+	tt.CheckStringEqual(d.Hints[0], "try again")
+	tt.CheckDeepEqual(d.Details, []string{"some detail"})
+	tt.CheckDeepEqual(d.IssueLinks, []issuelink.IssueLink{link})
+	tt.CheckDeepEqual(d.TelemetryKeys, []string{"a", "b"})
+	tt.CheckEqual(d.Domain, myDomain)
+	tt.CheckDeepEqual(d.Domains, domains.GetAllDomains(err))
+	tt.CheckDeepEqual(d.Domains, []domains.Domain{myDomain, domains.NamedDomain("inner")})
+	tt.Check(d.HasExitCode)
+	tt.CheckEqual(d.ExitCode, 42)
+	tt.Check(d.HasDuration)
+	tt.CheckEqual(d.Duration, 3*time.Second)
+	tt.Assert(len(d.Stacks) == 2)
+	tt.Check(d.Stacks[0] != nil && d.Stacks[1] != nil)
+	tt.CheckDeepEqual(d.Codes, errutil.GetAllCodes(err))
+	tt.CheckStringEqual(d.Codes["exit"], "42")
+	tt.CheckStringEqual(d.Codes["http"], "404")
+
+	// The branches of multi-cause errors are inspected too.
+	err = join.Join(
+		hintdetail.WithHint(telemetrykeys.WithTelemetry(goErr.New("a"), "k1"), "hint a"),
+		hintdetail.WithDetail(errutil.WithExitCode(goErr.New("b"), 1), "detail b"),
+	)
+	d = errutil.Diagnostics(err)
+	tt.CheckDeepEqual(d.Hints, []string{"hint a"})
+	tt.CheckDeepEqual(d.Details, []string{"detail b"})
+	tt.CheckDeepEqual(d.TelemetryKeys, []string{"k1"})
+	tt.Check(d.HasExitCode)
+	tt.CheckEqual(d.ExitCode, 1)
+
+	// An error without annotations has empty diagnostics.
+	d = errutil.Diagnostics(goErr.New("plain"))
+	tt.Check(len(d.Hints) == 0)
+	tt.Check(len(d.Details) == 0)
+	tt.Check(len(d.IssueLinks) == 0)
+	tt.Check(len(d.TelemetryKeys) == 0)
+	tt.CheckEqual(d.Domain, domains.NoDomain)
+	tt.Check(len(d.Domains) == 0)
+	tt.Check(len(d.Codes) == 0)
+	tt.Check(!d.HasExitCode)
+	tt.Check(!d.HasDuration)
+	tt.Check(len(d.Stacks) == 0)
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/secondary"
+)
+
+// FindType finds the first error in err's tree whose type is
+// assignable to T, and returns it. The tree is searched depth-first
+// from the outermost layer, following the causes of wrappers and all
+// the branches of multi-errors, in the order they are returned by
+// UnwrapMulti.
+//
+// Secondary errors, e.g. those attached with WithSecondaryError(),
+// are not searched. Use FindTypeIncludingSecondaries() for this.
+func FindType[T any](err error) (T, bool) {
+	return findType[T](err, false /* includeSecondaries */)
+}
+
+// FindTypeIncludingSecondaries is like FindType, but also searches
+// the secondary errors attached to the layers of the tree, as
+// returned by GetSecondaryErrors(). The secondary errors are only
+// searched after the entire primary tree, in the order in which they
+// are encountered, and recursively including their own secondary
+// errors.
+func FindTypeIncludingSecondaries[T any](err error) (T, bool) {
+	return findType[T](err, true /* includeSecondaries */)
+}
+
+func findType[T any](err error, includeSecondaries bool) (res T, ok bool) {
+	var secondaries []error
+	var visit func(chain error) bool
+	visit = func(chain error) bool {
+		if includeSecondaries {
+			secondaries = append(secondaries, secondary.GetSecondaryErrors(chain)...)
+		}
+		for c := chain; c != nil; c = errbase.UnwrapOnce(c) {
+			if v, isT := c.(T); isT {
+				res = v
+				return true
+			}
+			for _, cause := range errbase.UnwrapMulti(c) {
+				if visit(cause) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if err == nil {
+		return res, false
+	}
+	if visit(err) {
+		return res, true
+	}
+	for _, sec := range secondaries {
+		if res, ok = findType[T](sec, includeSecondaries); ok {
+			return res, true
+		}
+	}
+	return res, false
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	goErr "errors"
+	"testing"
+
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/join"
+	"github.com/cockroachdb/errors/secondary"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+func TestFindType(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	_, ok := errutil.FindType[*myType](nil)
+	tt.Check(!ok)
+	_, ok = errutil.FindType[*myType](goErr.New("hello"))
+	tt.Check(!ok)
+
+	// The type is found in a branch of a multi-error, deep in the tree.
+	refErr := &myType{msg: "woo"}
+	err := errutil.Wrap(join.Join(
+		goErr.New("hello"),
+		join.Join(goErr.New("world"), errutil.Wrap(refErr, "waa")),
+	), "wuu")
+	v, ok := errutil.FindType[*myType](err)
+	tt.Check(ok)
+	tt.Check(v == refErr)
+
+	// The first match in depth-first order wins.
+	otherErr := &myType{msg: "other"}
+	v, ok = errutil.FindType[*myType](join.Join(errutil.Wrap(otherErr, "a"), refErr))
+	tt.Check(ok)
+	tt.Check(v == otherErr)
+
+	// Interface types can be searched too.
+	w, ok := errutil.FindType[interface{ Cause() error }](err)
+	tt.Check(ok)
+	tt.Check(w.(error) == err)
+}
+
+func TestFindTypeIncludingSecondaries(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	// The type is only present as a secondary error, inside a branch
+	// of a multi-error.
+	refErr := &myType{msg: "woo"}
+	err := join.Join(
+		goErr.New("hello"),
+		secondary.WithSecondaryError(goErr.New("world"), errutil.Wrap(refErr, "waa")),
+	)
+
+	_, ok := errutil.FindType[*myType](err)
+	tt.Check(!ok)
+
+	v, ok := errutil.FindTypeIncludingSecondaries[*myType](err)
+	tt.Check(ok)
+	tt.Check(v == refErr)
+
+	// The primary tree is searched before the secondary errors.
+	otherErr := &myType{msg: "other"}
+	err = secondary.WithSecondaryError(errutil.Wrap(otherErr, "a"), refErr)
+	v, ok = errutil.FindTypeIncludingSecondaries[*myType](err)
+	tt.Check(ok)
+	tt.Check(v == otherErr)
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/errors/contexttags"
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+)
+
+// LogField is a key/value pair produced by LogFields().
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// LogFields converts an error into a flat list of fields suitable
+// for structured loggers. The following fields are produced, in
+// this order:
+//
+//   - "error": the message of the error, with the unsafe parts
+//     redacted.
+//   - "error.type": the family name of the outermost layer, as per
+//     errbase.GetTypeMark().
+//   - "error.<kind>_code": one field per code reported by
+//     GetAllCodes(), e.g. "error.grpc_code", in alphabetical order
+//     of kind.
+//   - "error.stack": the innermost stack trace, i.e. that closest to
+//     the origin of the error, if there is one.
+//   - "error.tag.<key>": one field per context tag attached with
+//     contexttags.WithContextTags(), outermost first. The values are
+//     always redacted, because their safety is not preserved by
+//     contexttags.GetContextTags().
+//
+// All the values are strings. A nil slice is returned for a nil
+// error.
+func LogFields(err error) []LogField {
+	if err == nil {
+		return nil
+	}
+	fields := []LogField{
+		{Key: "error", Value: string(redact.Sprint(err).Redact())},
+		{Key: "error.type", Value: errbase.GetTypeMark(err).FamilyName},
+	}
+
+	codes := GetAllCodes(err)
+	kinds := make([]string, 0, len(codes))
+	for kind := range codes {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		fields = append(fields, LogField{Key: "error." + kind + "_code", Value: codes[kind]})
+	}
+
+	var st errbase.StackTrace
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		if sp, ok := c.(errbase.StackTraceProvider); ok {
+			if s := sp.StackTrace(); len(s) > 0 {
+				st = s
+			}
+		}
+	}
+	if st != nil {
+		fields = append(fields, LogField{Key: "error.stack", Value: fmt.Sprintf("%+v", st)})
+	}
+
+	for _, b := range contexttags.GetContextTags(err) {
+		for _, t := range b.Get() {
+			fields = append(fields, LogField{
+				Key:   "error.tag." + t.Key(),
+				Value: string(redact.Sprint(t.Value()).Redact()),
+			})
+		}
+	}
+	return fields
+}
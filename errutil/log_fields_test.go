@@ -0,0 +1,77 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/contexttags"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/extgrpc"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/logtags"
+	"google.golang.org/grpc/codes"
+)
+
+func TestLogFields(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.Check(errutil.LogFields(nil) == nil)
+
+	ctx := logtags.AddTag(context.Background(), "n", 1)
+	ctx = logtags.AddTag(ctx, "user", "alice")
+
+	err := errutil.Newf("cannot open %s", "secret.txt")
+	err = contexttags.WithContextTags(err, ctx)
+	err = errutil.Wrap(err, "load")
+	err = errutil.WithExitCode(err, 3)
+	err = extgrpc.WrapWithGrpcCode(err, codes.NotFound)
+
+	fields := errutil.LogFields(err)
+	keys := make([]string, len(fields))
+	values := map[string]interface{}{}
+	for i, f := range fields {
+		keys[i] = f.Key
+		values[f.Key] = f.Value
+	}
+	tt.CheckDeepEqual(keys, []string{
+		"error",
+		"error.type",
+		"error.exit_code",
+		"error.grpc_code",
+		"error.stack",
+		"error.tag.n",
+		"error.tag.user",
+	})
+
+	// Unsafe values are redacted.
+	tt.CheckEqual(values["error"], "load: cannot open ‹×›")
+	tt.CheckEqual(values["error.type"], "github.com/cockroachdb/errors/extgrpc/*extgrpc.withGrpcCode")
+	tt.CheckEqual(values["error.exit_code"], "3")
+	tt.CheckEqual(values["error.grpc_code"], "NotFound")
+	tt.CheckEqual(values["error.tag.user"], "‹×›")
+
+	// The stack trace is that of the innermost layer.
+	stack := values["error.stack"].(string)
+	tt.Check(strings.HasPrefix(stack, "\ngithub.com/cockroachdb/errors/errutil_test.TestLogFields\n"))
+	tt.CheckContains(stack, "log_fields_test.go:")
+
+	// An error without annotations only has the core fields.
+	fields = errutil.LogFields(goErr.New("woo"))
+	tt.CheckEqual(len(fields), 2)
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"github.com/cockroachdb/errors/hintdetail"
+	"github.com/cockroachdb/errors/secondary"
+)
+
+// Merge combines two errors that represent the same failure observed
+// in two places into a single error. The primary error determines the
+// message and the identity of the result for Is() and the other
+// cause analysis functions. The secondary error is attached like with
+// WithSecondaryError(): its stack traces and safe details are
+// included when formatting with %+v and in Sentry reports, but its
+// causes are hidden from cause analysis.
+//
+// In addition, the hints and details of the secondary error that are
+// not already present in the primary error are folded into the
+// result, so that GetAllHints() and GetAllDetails() report those of
+// the primary error first, followed by those of the secondary error.
+//
+// The codes of the secondary error, as reported by GetAllCodes(), are
+// also folded into the result for each kind of code that the primary
+// error does not carry: the primary error wins on conflicts. This
+// applies to the exit codes and to the kinds registered with
+// RegisterCodeWrapper(), e.g. the gRPC and HTTP codes of the extgrpc
+// and exthttp packages.
+//
+// For all other annotations retrieved via the causal chain, e.g. the
+// domain or the telemetry keys, only those of the primary error are
+// visible.
+//
+// If either error is nil, the other is returned as-is.
+func Merge(primary, secondaryErr error) error {
+	if primary == nil {
+		return secondaryErr
+	}
+	if secondaryErr == nil {
+		return primary
+	}
+	hints := hintdetail.GetAllHints(primary)
+	details := hintdetail.GetAllDetails(primary)
+	codes := GetAllCodes(primary)
+	err := secondary.WithSecondaryError(primary, secondaryErr)
+	err = copyMissingCodes(err, codes, secondaryErr)
+	for _, h := range hintdetail.GetAllHints(secondaryErr) {
+		if !containsString(hints, h) {
+			err = hintdetail.WithHint(err, h)
+		}
+	}
+	for _, d := range hintdetail.GetAllDetails(secondaryErr) {
+		if !containsString(details, d) {
+			err = hintdetail.WithDetail(err, d)
+		}
+	}
+	return err
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errclass"
+	"github.com/cockroachdb/errors/errreason"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/extgrpc"
+	"github.com/cockroachdb/errors/exthttp"
+	"github.com/cockroachdb/errors/hintdetail"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/testutils"
+	"google.golang.org/grpc/codes"
+)
+
+func observedInClient(cause error) error { return errutil.Wrap(cause, "client") }
+func observedInServer(cause error) error { return errutil.Wrap(cause, "server") }
+
+func TestMerge(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	primaryCause := goErr.New("connection reset")
+	secondaryCause := goErr.New("broken pipe")
+	primary := hintdetail.WithHint(observedInClient(primaryCause), "retry")
+	secondary := hintdetail.WithDetail(
+		hintdetail.WithHint(hintdetail.WithHint(observedInServer(secondaryCause), "retry"), "check the network"),
+		"peer 1")
+
+	err := errutil.Merge(primary, secondary)
+
+	// The primary error determines the message.
+	tt.CheckStringEqual(err.Error(), "client: connection reset")
+
+	// Only the causes of the primary error are visible.
+	tt.Check(markers.Is(err, primaryCause))
+	tt.Check(markers.Is(err, primary))
+	tt.Check(!markers.Is(err, secondaryCause))
+	tt.Check(!markers.Is(err, secondary))
+
+	// Both stack traces are printed.
+	v := fmt.Sprintf("%+v", err)
+	tt.CheckContains(v, "observedInClient")
+	tt.CheckContains(v, "observedInServer")
+	tt.CheckContains(v, "broken pipe")
+
+	// The hints and details are merged, those of the primary first.
+	tt.CheckDeepEqual(hintdetail.GetAllHints(err), []string{"retry", "check the network"})
+	tt.CheckDeepEqual(hintdetail.GetAllDetails(err), []string{"peer 1"})
+
+	// The codes of the secondary error are folded into the result
+	// when the primary error has no code of the same kind: the
+	// primary error wins on conflicts.
+	primary = extgrpc.WrapWithGrpcCode(primary, codes.Unavailable)
+	secondary = extgrpc.WrapWithGrpcCode(secondary, codes.Aborted)
+	secondary = exthttp.WrapWithHTTPCode(secondary, 503)
+	secondary = errutil.WithExitCode(secondary, 2)
+	err = errutil.Merge(primary, secondary)
+	tt.CheckDeepEqual(errutil.GetAllCodes(err), map[string]string{
+		"exit": "2",
+		"grpc": "Unavailable",
+		"http": "503",
+	})
+	tt.CheckEqual(extgrpc.GetGrpcCode(err), codes.Unavailable)
+	tt.CheckEqual(exthttp.GetHTTPCode(err, 0), 503)
+	tt.Check(markers.Is(err, primaryCause))
+	tt.Check(!markers.Is(err, secondaryCause))
+
+	// The categories and reasons are folded in the same way.
+	defer errclass.TestingWithEmptyCategories()()
+	errclass.DefineCategories("network")
+	secondary = errclass.WithCategory(secondary, "network")
+	secondary = errreason.WithReason(secondary, "PEER_GONE", "peer 1 left")
+	err = errutil.Merge(primary, secondary)
+	cat, ok := errclass.GetCategory(err)
+	tt.Check(ok)
+	tt.CheckStringEqual(cat, "network")
+	code, detail, ok := errreason.GetReason(err)
+	tt.Check(ok)
+	tt.CheckStringEqual(code, "PEER_GONE")
+	tt.CheckStringEqual(detail, "peer 1 left")
+
+	// A nil error is ignored.
+	tt.Check(errutil.Merge(primary, nil) == primary)
+	tt.Check(errutil.Merge(nil, secondary) == secondary)
+}
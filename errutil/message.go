@@ -43,3 +43,22 @@ func WithMessagef(err error, format string, args ...interface{}) error {
 		prefix: redact.Sprintf(format, args...),
 	}
 }
+
+// WithOnlyPrefixMessage annotates err with a message which replaces
+// the error's message entirely: the Error() string of the result is
+// just the given message, without the message of the cause appended.
+// However, the cause remains visible to Is() and the full chain is
+// still displayed when formatting with %+v.
+//
+// If err is nil, WithOnlyPrefixMessage returns nil.
+// The message is considered safe for reporting
+// and is included in Sentry reports.
+func WithOnlyPrefixMessage(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return &withNewMessage{
+		cause:   err,
+		message: redact.Sprint(redact.Safe(message)),
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// TemplatedArg is an argument of an error constructed with
+// NewfStructured(), as returned by GetMessageTemplate().
+type TemplatedArg struct {
+	// Value is the argument rendered as a redactable string.
+	//
+	// When the error has been transferred over the network, the
+	// unsafe parts of the value are replaced by redaction markers.
+	Value redact.RedactableString
+	// Safe is true iff the value contains no unsafe part.
+	Safe bool
+}
+
+// NewfStructured is like Newf(), but also remembers the format string
+// and the arguments of the message. These can be retrieved separately
+// with GetMessageTemplate(), e.g. to aggregate log entries by message
+// template instead of rendered message. Error() renders the message
+// like Newf().
+//
+// Note: the format string is assumed to not contain
+// PII and is included in Sentry reports.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`, with the unsafe arguments redacted.
+// - via `GetMessageTemplate()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func NewfStructured(format string, args ...interface{}) error {
+	return NewStructuredWithDepthf(1, format, args...)
+}
+
+// NewStructuredWithDepthf is like NewfStructured() except the depth
+// to capture the stack trace is configurable.
+// See the doc of `NewfStructured()` for more details.
+func NewStructuredWithDepthf(depth int, format string, args ...interface{}) error {
+	err := NewWithDepthf(1+depth, format, args...)
+	targs := make([]TemplatedArg, len(args))
+	for i, a := range args {
+		targs[i] = makeTemplatedArg(redact.Sprint(a))
+	}
+	return &withMessageTemplate{cause: err, format: format, args: targs}
+}
+
+// GetMessageTemplate retrieves the format string and the arguments of
+// the outermost error constructed with NewfStructured() in the
+// error's causal chain, or false if there is none.
+func GetMessageTemplate(err error) (format string, args []TemplatedArg, ok bool) {
+	v, ok := markers.If(err, func(err error) (interface{}, bool) {
+		if w, ok := err.(*withMessageTemplate); ok {
+			return w, true
+		}
+		return nil, false
+	})
+	if !ok {
+		return "", nil, false
+	}
+	w := v.(*withMessageTemplate)
+	return w.format, append([]TemplatedArg(nil), w.args...), true
+}
+
+func makeTemplatedArg(v redact.RedactableString) TemplatedArg {
+	return TemplatedArg{Value: v, Safe: v.StripMarkers() == string(v)}
+}
+
+type withMessageTemplate struct {
+	cause  error
+	format string
+	args   []TemplatedArg
+}
+
+var _ error = (*withMessageTemplate)(nil)
+var _ errbase.SafeDetailer = (*withMessageTemplate)(nil)
+var _ fmt.Formatter = (*withMessageTemplate)(nil)
+var _ errbase.SafeFormatter = (*withMessageTemplate)(nil)
+
+func (w *withMessageTemplate) Error() string { return w.cause.Error() }
+func (w *withMessageTemplate) Cause() error  { return w.cause }
+func (w *withMessageTemplate) Unwrap() error { return w.cause }
+
+// SafeDetails encodes the format string followed by the arguments,
+// with their unsafe parts redacted.
+func (w *withMessageTemplate) SafeDetails() []string {
+	details := make([]string, 1+len(w.args))
+	details[0] = w.format
+	for i, a := range w.args {
+		details[1+i] = string(a.Value.Redact())
+	}
+	return details
+}
+
+func (w *withMessageTemplate) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withMessageTemplate) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("message template: %q", redact.Safe(w.format))
+	}
+	return w.cause
+}
+
+func decodeWithMessageTemplate(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) == 0 {
+		return nil
+	}
+	args := make([]TemplatedArg, len(details)-1)
+	for i, d := range details[1:] {
+		args[i] = makeTemplatedArg(redact.RedactableString(d))
+	}
+	return &withMessageTemplate{cause: cause, format: details[0], args: args}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withMessageTemplate)(nil)), decodeWithMessageTemplate)
+}
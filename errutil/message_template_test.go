@@ -0,0 +1,68 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+)
+
+func TestNewfStructured(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	_, _, ok := errutil.GetMessageTemplate(errutil.New("woo"))
+	tt.Check(!ok)
+
+	err := errutil.NewfStructured("user %s has %d items", "alice", redact.Safe(3))
+
+	// The message is rendered as usual.
+	tt.CheckStringEqual(err.Error(), "user alice has 3 items")
+	tt.CheckStringEqual(string(redact.Sprint(err).Redact()), "user ‹×› has 3 items")
+
+	format, args, ok := errutil.GetMessageTemplate(errutil.WithMessage(err, "waa"))
+	tt.Check(ok)
+	tt.CheckStringEqual(format, "user %s has %d items")
+	tt.CheckDeepEqual(args, []errutil.TemplatedArg{
+		{Value: "‹alice›", Safe: false},
+		{Value: "3", Safe: true},
+	})
+
+	v := fmt.Sprintf("%+v", err)
+	tt.Check(strings.HasPrefix(v, "user alice has 3 items\n(1) message template: \"user %s has %d items\"\nWraps: (2) attached stack trace\n"))
+
+	// The template is reportable, with the unsafe arguments redacted.
+	tt.CheckDeepEqual(errbase.GetSafeDetails(err).SafeDetails,
+		[]string{"user %s has %d items", "‹×›", "3"})
+
+	// Simulate a network transfer. The safe arguments are preserved.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+
+	tt.CheckStringEqual(newErr.Error(), err.Error())
+	format, args, ok = errutil.GetMessageTemplate(newErr)
+	tt.Check(ok)
+	tt.CheckStringEqual(format, "user %s has %d items")
+	tt.CheckDeepEqual(args, []errutil.TemplatedArg{
+		{Value: "‹×›", Safe: false},
+		{Value: "3", Safe: true},
+	})
+}
@@ -15,6 +15,7 @@
 package errutil_test
 
 import (
+	"context"
 	goErr "errors"
 	"fmt"
 	"regexp"
@@ -22,7 +23,9 @@ import (
 	"testing"
 
 	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errorspb"
 	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/markers"
 	"github.com/cockroachdb/errors/testutils"
 )
 
@@ -222,3 +225,39 @@ func (e *werrFmt) FormatError(p errbase.Printer) error {
 }
 
 var emptyString = ""
+
+func TestWithOnlyPrefixMessage(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("woo")
+	err := errutil.WithOnlyPrefixMessage(origErr, "waa")
+
+	// The message of the cause is elided.
+	tt.CheckStringEqual(err.Error(), "waa")
+	tt.CheckStringEqual(fmt.Sprintf("%v", err), "waa")
+	// However the cause remains visible.
+	tt.Check(markers.Is(err, origErr))
+	tt.CheckStringEqual(fmt.Sprintf("%+v", err), `waa
+(1) waa
+Wraps: (2) woo
+Error types: (1) *errutil.withNewMessage (2) *errors.errorString`)
+
+	// The override survives a network transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+	tt.CheckStringEqual(newErr.Error(), "waa")
+	tt.Check(markers.Is(newErr, origErr))
+	tt.CheckStringEqual(fmt.Sprintf("%+v", newErr), fmt.Sprintf("%+v", err))
+
+	// The override also survives when the wrapper type is not known
+	// on the other side.
+	w := enc.GetWrapper()
+	tt.Assert(w != nil)
+	tt.CheckEqual(w.MessageType, errorspb.MessageType_FULL_MESSAGE)
+	w.Details.ErrorTypeMark.FamilyName = "unknown/*unknown.wrapper"
+	newErr = errbase.DecodeError(context.Background(), enc)
+	tt.CheckStringEqual(newErr.Error(), "waa")
+	tt.Check(markers.Is(newErr, origErr))
+
+	tt.Check(errutil.WithOnlyPrefixMessage(nil, "waa") == nil)
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors/domains"
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errorspb"
+)
+
+// NormalizeOptions configures Normalize(). The zero value leaves
+// errors unchanged.
+type NormalizeOptions struct {
+	// Domain, if non-empty, is attached to errors that do not have a
+	// domain yet. See GetDomain().
+	Domain domains.Domain
+	// StripLocalOnly, if true, removes the annotations that are only
+	// meaningful in the current process, e.g. those attached with
+	// WithLocalMetadata() or WithLogged().
+	StripLocalOnly bool
+	// StripStacks, if true, removes the wrappers that only carry a
+	// stack trace, e.g. those attached with WithStack(). This is meant
+	// for errors presented to untrusted clients. Stack traces
+	// captured by leaf errors themselves, for example by
+	// github.com/pkg/errors.New(), are preserved.
+	StripStacks bool
+}
+
+// Normalize prepares an error to cross a service boundary, as
+// configured by opts. Each step is a no-op if the error already
+// satisfies it; in particular, Normalize returns err itself if there
+// is nothing to do.
+//
+// Removing annotations requires transforming the error through
+// EncodeError/DecodeError. As a result, when StripStacks is set,
+// the local-only annotations are also removed, and error types that
+// were not registered with the library become opaque, as if the
+// error had been received from the network. The message and the
+// details of the remaining layers are preserved.
+func Normalize(err error, opts NormalizeOptions) error {
+	if err == nil {
+		return nil
+	}
+	if opts.Domain != "" && opts.Domain != domains.NoDomain &&
+		domains.GetDomain(err) == domains.NoDomain {
+		err = domains.WithDomain(err, opts.Domain)
+	}
+	// The stack wrappers must be recognized before the error is
+	// decoded, as they are opaque afterwards.
+	var stackTypes map[errorspb.ErrorTypeMark]bool
+	if opts.StripStacks {
+		stackTypes = getStackTypes(err)
+	}
+	if len(stackTypes) > 0 || (opts.StripLocalOnly && hasLayer(err, isLocalOnly)) {
+		// The local-only annotations are dropped by DecodeError.
+		ctx := context.Background()
+		enc := errbase.EncodeError(ctx, err)
+		if len(stackTypes) > 0 {
+			enc = stripEncodedStacks(enc, stackTypes)
+		}
+		err = errbase.DecodeError(ctx, enc)
+	}
+	return err
+}
+
+// isLocalOnly returns true for the annotations that are dropped by
+// DecodeError.
+func isLocalOnly(err error) bool {
+	switch e := err.(type) {
+	case *withLocalMetadata, *withLogged:
+		return true
+	case *panicValueError:
+		return e.local
+	}
+	return false
+}
+
+// isStackWrapper returns true for wrappers that carry a stack trace.
+func isStackWrapper(err error) bool {
+	_, ok := err.(errbase.StackTraceProvider)
+	return ok && errbase.UnwrapOnce(err) != nil
+}
+
+// hasLayer returns true if any layer in the error tree satisfies pred.
+func hasLayer(err error, pred func(error) bool) (found bool) {
+	errbase.WalkWithMarks(err, func(layer error, _ errorspb.ErrorTypeMark, _ bool) {
+		found = found || pred(layer)
+	})
+	return found
+}
+
+// getStackTypes returns the type marks of the wrappers that carry a
+// stack trace in the error tree.
+func getStackTypes(err error) map[errorspb.ErrorTypeMark]bool {
+	var stackTypes map[errorspb.ErrorTypeMark]bool
+	errbase.WalkWithMarks(err, func(layer error, mark errorspb.ErrorTypeMark, _ bool) {
+		if isStackWrapper(layer) {
+			if stackTypes == nil {
+				stackTypes = make(map[errorspb.ErrorTypeMark]bool)
+			}
+			stackTypes[mark] = true
+		}
+	})
+	return stackTypes
+}
+
+// stripEncodedStacks removes the wrappers with the given types and
+// no message of their own from the encoded error tree.
+func stripEncodedStacks(
+	enc errbase.EncodedError, stackTypes map[errorspb.ErrorTypeMark]bool,
+) errbase.EncodedError {
+	switch t := enc.Error.(type) {
+	case *errorspb.EncodedError_Wrapper:
+		w := t.Wrapper
+		if w.Message == "" && stackTypes[w.Details.ErrorTypeMark] {
+			return stripEncodedStacks(w.Cause, stackTypes)
+		}
+		newW := *w
+		newW.Cause = stripEncodedStacks(w.Cause, stackTypes)
+		return errbase.EncodedError{Error: &errorspb.EncodedError_Wrapper{Wrapper: &newW}}
+	case *errorspb.EncodedError_Leaf:
+		l := t.Leaf
+		if len(l.MultierrorCauses) == 0 {
+			return enc
+		}
+		newL := *l
+		newL.MultierrorCauses = make([]*errorspb.EncodedError, len(l.MultierrorCauses))
+		for i, c := range l.MultierrorCauses {
+			nc := stripEncodedStacks(*c, stackTypes)
+			newL.MultierrorCauses[i] = &nc
+		}
+		return errbase.EncodedError{Error: &errorspb.EncodedError_Leaf{Leaf: &newL}}
+	}
+	return enc
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/domains"
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errorspb"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/join"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/withstack"
+)
+
+// hasStackWrapper returns true if the error contains a wrapper
+// attached with WithStack(), possibly decoded as an opaque wrapper.
+func hasStackWrapper(err error) (found bool) {
+	stackMark := errbase.GetTypeMark(withstack.WithStack(goErr.New("")))
+	errbase.WalkWithMarks(err, func(_ error, mark errorspb.ErrorTypeMark, _ bool) {
+		found = found || mark == stackMark
+	})
+	return found
+}
+
+func TestNormalize(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.Check(errutil.Normalize(nil, errutil.NormalizeOptions{StripStacks: true}) == nil)
+
+	// The zero options leave the error unchanged.
+	origErr := errutil.WithLogged(errutil.New("woo"))
+	tt.Check(errutil.Normalize(origErr, errutil.NormalizeOptions{}) == origErr)
+
+	tt.Run("domain", func(tt testutils.T) {
+		opts := errutil.NormalizeOptions{Domain: domains.Domain("mysvc")}
+
+		err := errutil.Normalize(goErr.New("woo"), opts)
+		tt.CheckEqual(domains.GetDomain(err), domains.Domain("mysvc"))
+		tt.CheckStringEqual(err.Error(), "woo")
+
+		// An existing domain is preserved.
+		origErr := domains.WithDomain(goErr.New("woo"), "othersvc")
+		tt.Check(errutil.Normalize(origErr, opts) == origErr)
+	})
+
+	tt.Run("local only", func(tt testutils.T) {
+		opts := errutil.NormalizeOptions{StripLocalOnly: true}
+
+		origErr := errutil.WithLocalMetadata(errutil.WithLogged(errutil.New("woo")),
+			map[string]string{"user": "alice"})
+		err := errutil.Normalize(origErr, opts)
+		tt.Check(!errutil.IsLogged(err))
+		tt.Check(errutil.GetLocalMetadata(err) == nil)
+		tt.CheckStringEqual(err.Error(), "woo")
+		// The other steps are not applied.
+		tt.Check(hasStackWrapper(err))
+		tt.CheckEqual(domains.GetDomain(err), domains.NoDomain)
+
+		// Without local-only annotations, the error is unchanged.
+		origErr = errutil.New("woo")
+		tt.Check(errutil.Normalize(origErr, opts) == origErr)
+	})
+
+	tt.Run("stacks", func(tt testutils.T) {
+		opts := errutil.NormalizeOptions{StripStacks: true}
+
+		origErr := errutil.Wrap(join.Join(errutil.New("a"), goErr.New("b")), "woo")
+		tt.Check(hasStackWrapper(origErr))
+		err := errutil.Normalize(origErr, opts)
+		tt.Check(!hasStackWrapper(err))
+		tt.CheckStringEqual(err.Error(), origErr.Error())
+		tt.CheckContains(fmt.Sprintf("%+v", err), "(1) woo\nWraps: (2) a\n")
+
+		// Without stack wrappers, the error is unchanged.
+		origErr = goErr.New("woo")
+		tt.Check(errutil.Normalize(origErr, opts) == origErr)
+	})
+
+	tt.Run("all", func(tt testutils.T) {
+		opts := errutil.NormalizeOptions{
+			Domain:         domains.Domain("mysvc"),
+			StripLocalOnly: true,
+			StripStacks:    true,
+		}
+
+		origErr := errutil.WithLogged(errutil.Wrap(errutil.New("a"), "woo"))
+		err := errutil.Normalize(origErr, opts)
+		tt.Check(!errutil.IsLogged(err))
+		tt.Check(!hasStackWrapper(err))
+		tt.CheckEqual(domains.GetDomain(err), domains.Domain("mysvc"))
+		tt.CheckStringEqual(err.Error(), "woo: a")
+	})
+}
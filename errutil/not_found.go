@@ -0,0 +1,58 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"io/fs"
+
+	"github.com/cockroachdb/errors/markers"
+)
+
+// IsNotFound returns true iff the error indicates that some
+// requested entity does not exist. This recognizes:
+//
+//   - errors equivalent to fs.ErrNotExist (and thus os.ErrNotExist),
+//     including after transfer through the network;
+//   - errors recognized by a predicate registered with
+//     RegisterNotFoundPredicate(). For example, the extgrpc and exthttp
+//     packages register predicates for the gRPC NotFound code and the
+//     HTTP 404 code, respectively.
+func IsNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	if markers.Is(err, fs.ErrNotExist) {
+		return true
+	}
+	for _, pred := range notFoundPredicates {
+		if pred(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterNotFoundPredicate registers a function that is used by
+// IsNotFound() to recognize additional "not found" errors. The
+// predicate receives the error passed to IsNotFound() and is
+// responsible for inspecting its causes.
+//
+// This is meant to be called from an init() function.
+func RegisterNotFoundPredicate(pred func(err error) bool) {
+	notFoundPredicates = append(notFoundPredicates, pred)
+}
+
+// notFoundPredicates is the registry for RegisterNotFoundPredicate.
+var notFoundPredicates []func(err error) bool
@@ -0,0 +1,61 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"os"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+var errMissingThing = goErr.New("missing thing")
+
+func init() {
+	errutil.RegisterNotFoundPredicate(func(err error) bool {
+		return markers.Is(err, errMissingThing)
+	})
+}
+
+func TestIsNotFound(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	network := func(err error) error {
+		return errbase.DecodeError(context.Background(), errbase.EncodeError(context.Background(), err))
+	}
+
+	tt.Check(!errutil.IsNotFound(nil))
+	tt.Check(!errutil.IsNotFound(goErr.New("hello")))
+	tt.Check(!errutil.IsNotFound(os.ErrExist))
+
+	// os.ErrNotExist is recognized, including when wrapped
+	// or transferred through the network.
+	_, err := os.Open("/this/file/does/not/exist")
+	tt.Check(errutil.IsNotFound(err))
+	tt.Check(errutil.IsNotFound(os.ErrNotExist))
+	err = errutil.Wrap(os.ErrNotExist, "woo")
+	tt.Check(errutil.IsNotFound(err))
+	tt.Check(errutil.IsNotFound(network(err)))
+
+	// Registered predicates are consulted.
+	err = errutil.Wrap(errMissingThing, "woo")
+	tt.Check(errutil.IsNotFound(err))
+	tt.Check(errutil.IsNotFound(network(err)))
+}
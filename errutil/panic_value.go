@@ -0,0 +1,136 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errorspb"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/withstack"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// FromPanicValue converts a value recovered from a panic into an
+// error. If the value is already an error, it is returned with a
+// stack trace attached. Otherwise, a new error is constructed with
+// message "panic: <value>", and the original value can be
+// retrieved with GetPanicValue(). If the value is nil, as returned
+// by recover() when there was no panic, the result is nil.
+//
+// The value itself is only available in the current process. Its Go
+// type is considered safe for reporting; its representation is
+// considered unsafe unless the value implements redact.SafeValue.
+//
+// Detail is shown:
+// - via `GetPanicValue()` below, in the current process only.
+// - via `errors.GetSafeDetails()`, with the value redacted.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func FromPanicValue(v interface{}) error {
+	return FromPanicValueWithDepth(1, v)
+}
+
+// FromPanicValueWithDepth is like FromPanicValue() except the depth
+// to capture the stack trace is configurable.
+// See the doc of `FromPanicValue()` for more details.
+func FromPanicValueWithDepth(depth int, v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	if err, ok := v.(error); ok {
+		return withstack.WithStackDepth(err, 1+depth)
+	}
+	err := &panicValueError{
+		msg:      redact.Sprintf("panic: %v", v),
+		typeName: fmt.Sprintf("%T", v),
+		value:    v,
+		local:    true,
+	}
+	return withstack.WithStackDepth(err, 1+depth)
+}
+
+// GetPanicValue retrieves the value passed to FromPanicValue() in
+// the error's causal chain. The result is false if there is no such
+// value, including when the error was decoded from the network: the
+// value is not preserved by EncodeError/DecodeError.
+func GetPanicValue(err error) (interface{}, bool) {
+	v, ok := markers.If(err, func(err error) (interface{}, bool) {
+		if w, ok := err.(*panicValueError); ok && w.local {
+			return w.value, true
+		}
+		return nil, false
+	})
+	return v, ok
+}
+
+// panicValueError is the leaf error produced by FromPanicValue().
+type panicValueError struct {
+	msg      redact.RedactableString
+	typeName string
+	// value is the panic value. It is only populated, and local is
+	// only true, in the process where the error was constructed.
+	value interface{}
+	local bool
+}
+
+var _ error = (*panicValueError)(nil)
+var _ fmt.Formatter = (*panicValueError)(nil)
+var _ errbase.SafeFormatter = (*panicValueError)(nil)
+var _ errbase.SafeDetailer = (*panicValueError)(nil)
+
+func (e *panicValueError) Error() string                 { return e.msg.StripMarkers() }
+func (e *panicValueError) Format(s fmt.State, verb rune) { errbase.FormatError(e, s, verb) }
+
+func (e *panicValueError) SafeFormatError(p errbase.Printer) (next error) {
+	p.Print(e.msg)
+	if p.Detail() {
+		p.Printf("panic value type: %s", redact.Safe(e.typeName))
+	}
+	return nil
+}
+
+// SafeDetails reports the redacted message and the type of the value.
+func (e *panicValueError) SafeDetails() []string {
+	return []string{e.msg.Redact().StripMarkers(), e.typeName}
+}
+
+func encodePanicValueError(_ context.Context, err error) (string, []string, proto.Message) {
+	e := err.(*panicValueError)
+	return e.Error(), e.SafeDetails(), &errorspb.StringPayload{Msg: string(e.msg)}
+}
+
+func decodePanicValueError(
+	_ context.Context, _ string, details []string, payload proto.Message,
+) error {
+	m, ok := payload.(*errorspb.StringPayload)
+	if !ok || len(details) < 2 {
+		// If this ever happens, this means some version of the library
+		// (presumably future) changed the payload type, and we're
+		// receiving this here. In this case, give up and let
+		// DecodeError use the opaque type.
+		return nil
+	}
+	return &panicValueError{msg: redact.RedactableString(m.Msg), typeName: details[1]}
+}
+
+func init() {
+	tn := errbase.GetTypeKey((*panicValueError)(nil))
+	errbase.RegisterLeafEncoder(tn, encodePanicValueError)
+	errbase.RegisterLeafDecoder(tn, decodePanicValueError)
+}
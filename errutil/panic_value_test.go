@@ -0,0 +1,104 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/withstack"
+)
+
+type panicStruct struct {
+	A int
+	B string
+}
+
+func recoverPanic(v interface{}) (err error) {
+	defer func() {
+		err = errutil.FromPanicValue(recover())
+	}()
+	panic(v)
+}
+
+func TestFromPanicValue(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	testData := []struct {
+		value       interface{}
+		expMsg      string
+		expSafeMsg  string
+		expTypeName string
+	}{
+		{"boom", "panic: boom", "panic: ×", "string"},
+		{42, "panic: 42", "panic: ×", "int"},
+		{panicStruct{A: 1, B: "x"}, "panic: {1 x}", "panic: {× ×}", "errutil_test.panicStruct"},
+	}
+
+	for _, test := range testData {
+		tt.Run(test.expTypeName, func(tt testutils.T) {
+			err := recoverPanic(test.value)
+			tt.CheckStringEqual(err.Error(), test.expMsg)
+
+			// The stack trace is captured at the point of recovery.
+			file, _, _, ok := withstack.GetOneLineSource(err)
+			tt.Check(ok)
+			tt.CheckStringEqual(file, "panic_value_test.go")
+
+			v, ok := errutil.GetPanicValue(err)
+			tt.Check(ok)
+			tt.CheckDeepEqual(v, test.value)
+
+			// The value is also found through wrappers.
+			v, ok = errutil.GetPanicValue(errutil.Wrap(err, "woo"))
+			tt.Check(ok)
+			tt.CheckDeepEqual(v, test.value)
+
+			// Only the redacted representation and the type are safe.
+			details := errbase.GetSafeDetails(errbase.UnwrapOnce(err)).SafeDetails
+			tt.CheckDeepEqual(details, []string{test.expSafeMsg, test.expTypeName})
+			tt.CheckContains(fmt.Sprintf("%+v", err), "panic value type: "+test.expTypeName)
+
+			// The value does not survive a network transfer, but the
+			// message and the type do.
+			enc := errbase.EncodeError(context.Background(), err)
+			newErr := errbase.DecodeError(context.Background(), enc)
+			tt.CheckStringEqual(newErr.Error(), test.expMsg)
+			tt.Check(markers.Is(newErr, err))
+			_, ok = errutil.GetPanicValue(newErr)
+			tt.Check(!ok)
+			tt.CheckContains(fmt.Sprintf("%+v", newErr), "panic value type: "+test.expTypeName)
+		})
+	}
+
+	// Errors are passed through.
+	origErr := goErr.New("woo")
+	err := recoverPanic(origErr)
+	tt.Check(err != origErr)
+	tt.Check(markers.Is(err, origErr))
+	_, ok := errutil.GetPanicValue(err)
+	tt.Check(!ok)
+
+	// Without a panic, recover() returns nil and so does
+	// FromPanicValue().
+	tt.Check(errutil.FromPanicValue(nil) == nil)
+	tt.Check(errutil.FromPanicValue(recover()) == nil)
+}
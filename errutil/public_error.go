@@ -0,0 +1,85 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/barriers"
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// PublicError constructs an error suitable for the boundary of an
+// API: its message is publicMsg, and it is a barrier, so that the
+// internal error is hidden from Is() and the message of the internal
+// error is not visible to the client. The internal error remains
+// available in the details, for troubleshooting.
+//
+// The public message is also marked as intended for the end user,
+// so that ClientMessage() returns it. It is considered safe for
+// reporting.
+//
+// Detail is shown:
+// - via `ClientMessage()`.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func PublicError(internal error, publicMsg string) error {
+	if internal == nil {
+		return nil
+	}
+	return &withPublicMessage{
+		cause: barriers.HandledWithSafeMessage(internal, redact.Sprint(redact.Safe(publicMsg))),
+	}
+}
+
+// withPublicMessage marks the message of its cause as intended for
+// the end user.
+type withPublicMessage struct {
+	cause error
+}
+
+var _ error = (*withPublicMessage)(nil)
+var _ fmt.Formatter = (*withPublicMessage)(nil)
+var _ errbase.SafeFormatter = (*withPublicMessage)(nil)
+var _ UserFacingMessager = (*withPublicMessage)(nil)
+
+func (w *withPublicMessage) Error() string { return w.cause.Error() }
+func (w *withPublicMessage) Cause() error  { return w.cause }
+func (w *withPublicMessage) Unwrap() error { return w.cause }
+
+// UserFacingMessage implements the UserFacingMessager interface.
+func (w *withPublicMessage) UserFacingMessage() string { return w.cause.Error() }
+
+func (w *withPublicMessage) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withPublicMessage) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("public message")
+	}
+	return w.cause
+}
+
+func decodeWithPublicMessage(
+	_ context.Context, cause error, _ string, _ []string, _ proto.Message,
+) error {
+	return &withPublicMessage{cause: cause}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withPublicMessage)(nil)), decodeWithPublicMessage)
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/report"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+)
+
+func TestPublicError(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.Check(errutil.PublicError(nil, "oops") == nil)
+
+	sentinel := goErr.New("sentinel")
+	internal := errutil.Wrapf(sentinel, "cannot open %s", "secret.txt")
+	err := errutil.PublicError(internal, "the service is unavailable")
+
+	// The internal error is hidden.
+	tt.CheckStringEqual(err.Error(), "the service is unavailable")
+	tt.Check(!markers.Is(err, sentinel))
+	tt.Check(!goErr.Is(err, sentinel))
+
+	// The public message is presented to the client, even when
+	// the error is further wrapped.
+	tt.CheckStringEqual(errutil.ClientMessage(err, "internal error"), "the service is unavailable")
+	wrapped := errutil.Wrap(err, "while serving")
+	tt.CheckStringEqual(errutil.ClientMessage(wrapped, "internal error"), "the service is unavailable")
+
+	// The internal details are retained for troubleshooting, with
+	// the public message considered safe.
+	tt.CheckContains(fmt.Sprintf("%+v", err), "cannot open secret.txt: sentinel")
+	redacted := string(redact.Sprintf("%+v", err).Redact())
+	tt.CheckContains(redacted, "the service is unavailable\n(1) public message\nWraps: (2) the service is unavailable")
+	tt.CheckContains(redacted, "cannot open ‹×›: ‹×›")
+	event, _ := report.BuildSentryReport(err)
+	tt.CheckContains(event.Message, "cannot open ×")
+
+	// The annotation survives a network transfer.
+	enc := errbase.EncodeError(context.Background(), wrapped)
+	newErr := errbase.DecodeError(context.Background(), enc)
+	tt.CheckStringEqual(errutil.ClientMessage(newErr, "internal error"), "the service is unavailable")
+	tt.Check(!markers.Is(newErr, sentinel))
+	tt.CheckStringEqual(newErr.Error(), wrapped.Error())
+	tt.CheckContains(fmt.Sprintf("%+v", newErr), "cannot open secret.txt: sentinel")
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/withstack"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// Reraise annotates an error with a stack trace captured at the
+// point of the call, and a marker indicating that the error was
+// re-entered into a new call path there. This is useful when an
+// error is caught far from its origin, e.g. after being passed
+// through a channel, and raised again.
+//
+// The original error, including its own stack traces, is preserved
+// and markers.Is() still recognizes it.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - when formatting with `%+v`, with the new stack trace.
+// - in Sentry reports.
+func Reraise(err error) error {
+	return ReraiseWithDepth(1, err)
+}
+
+// ReraiseWithDepth is like Reraise except the depth to capture the
+// stack trace is specified.
+func ReraiseWithDepth(depth int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withReraise{cause: withstack.WithStackDepth(err, depth+1)}
+}
+
+type withReraise struct {
+	cause error
+}
+
+var _ error = (*withReraise)(nil)
+var _ errbase.SafeDetailer = (*withReraise)(nil)
+var _ fmt.Formatter = (*withReraise)(nil)
+var _ errbase.SafeFormatter = (*withReraise)(nil)
+
+func (w *withReraise) Error() string { return w.cause.Error() }
+func (w *withReraise) Cause() error  { return w.cause }
+func (w *withReraise) Unwrap() error { return w.cause }
+
+func (w *withReraise) SafeDetails() []string { return []string{reraiseLabel} }
+
+func (w *withReraise) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withReraise) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Print(redact.Safe(reraiseLabel))
+	}
+	return w.cause
+}
+
+const reraiseLabel = "re-raised here"
+
+func decodeWithReraise(
+	_ context.Context, cause error, _ string, _ []string, _ proto.Message,
+) error {
+	return &withReraise{cause: cause}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withReraise)(nil)), decodeWithReraise)
+}
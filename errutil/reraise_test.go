@@ -0,0 +1,55 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+func makeOriginalError() error { return errutil.New("hello") }
+
+func reraiseElsewhere(err error) error { return errutil.Reraise(err) }
+
+func TestReraise(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.Check(errutil.Reraise(nil) == nil)
+
+	origErr := makeOriginalError()
+	err := reraiseElsewhere(origErr)
+	tt.CheckStringEqual(err.Error(), "hello")
+	tt.Check(markers.Is(err, origErr))
+
+	// Both the original and the new stack traces are shown.
+	s := fmt.Sprintf("%+v", err)
+	tt.CheckContains(s, "(1) re-raised here\n")
+	tt.CheckContains(s, "errutil_test.reraiseElsewhere\n")
+	tt.CheckContains(s, "errutil_test.makeOriginalError\n")
+	tt.Check(strings.Index(s, "reraiseElsewhere") < strings.Index(s, "makeOriginalError"))
+
+	// The marker survives a network transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+	tt.Check(markers.Is(newErr, origErr))
+	tt.CheckContains(fmt.Sprintf("%+v", newErr), "(1) re-raised here\n")
+}
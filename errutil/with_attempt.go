@@ -0,0 +1,104 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithAttempt annotates an error with the number of the attempt of
+// a retry loop that produced it. This helps correlate errors with
+// the retry behavior in reports.
+//
+// If the annotation is applied multiple times, the outermost
+// attempt number wins, i.e. that set by the outermost retry loop.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetAttempt()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithAttempt(err error, n int) error {
+	if err == nil {
+		return nil
+	}
+	return &withAttempt{cause: err, attempt: n}
+}
+
+// GetAttempt retrieves the outermost attempt number annotation
+// in the error's causal chain, or false if there is none.
+func GetAttempt(err error) (int, bool) {
+	v, ok := markers.If(err, func(err error) (interface{}, bool) {
+		if w, ok := err.(*withAttempt); ok {
+			return w.attempt, true
+		}
+		return nil, false
+	})
+	if !ok {
+		return 0, false
+	}
+	return v.(int), true
+}
+
+type withAttempt struct {
+	cause   error
+	attempt int
+}
+
+var _ error = (*withAttempt)(nil)
+var _ errbase.SafeDetailer = (*withAttempt)(nil)
+var _ fmt.Formatter = (*withAttempt)(nil)
+var _ errbase.SafeFormatter = (*withAttempt)(nil)
+
+func (w *withAttempt) Error() string { return w.cause.Error() }
+func (w *withAttempt) Cause() error  { return w.cause }
+func (w *withAttempt) Unwrap() error { return w.cause }
+
+func (w *withAttempt) SafeDetails() []string { return []string{strconv.Itoa(w.attempt)} }
+
+func (w *withAttempt) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withAttempt) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("attempt: %d", redact.Safe(w.attempt))
+	}
+	return w.cause
+}
+
+func decodeWithAttempt(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) == 0 {
+		return nil
+	}
+	n, err := strconv.Atoi(details[0])
+	if err != nil {
+		// Some future version of the library is using a different
+		// encoding. Let DecodeError use the opaque type.
+		return nil
+	}
+	return &withAttempt{cause: cause, attempt: n}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withAttempt)(nil)), decodeWithAttempt)
+}
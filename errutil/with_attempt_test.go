@@ -0,0 +1,63 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+)
+
+func TestWithAttempt(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("woo")
+	_, ok := errutil.GetAttempt(origErr)
+	tt.Check(!ok)
+	tt.Check(errutil.WithAttempt(nil, 1) == nil)
+
+	// The outermost attempt number wins.
+	err := errutil.WithAttempt(errutil.Wrap(errutil.WithAttempt(origErr, 2), "retrying"), 5)
+
+	n, ok := errutil.GetAttempt(err)
+	tt.Check(ok)
+	tt.CheckEqual(n, 5)
+
+	tt.CheckStringEqual(err.Error(), "retrying: woo")
+	tt.CheckContains(fmt.Sprintf("%+v", err), "\n(1) attempt: 5\n")
+	tt.CheckContains(fmt.Sprintf("%+v", err), "\nWraps: (4) attempt: 2\n")
+
+	// The attempt number is considered safe for reporting.
+	tt.CheckContains(string(redact.Sprintf("%+v", err).Redact()), "\n(1) attempt: 5\n")
+
+	// Simulate a network transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+
+	n, ok = errutil.GetAttempt(newErr)
+	tt.Check(ok)
+	tt.CheckEqual(n, 5)
+	n, ok = errutil.GetAttempt(errbase.UnwrapOnce(newErr))
+	tt.Check(ok)
+	tt.CheckEqual(n, 2)
+	tt.CheckDeepEqual(errbase.GetSafeDetails(newErr).SafeDetails, []string{"5"})
+	tt.CheckStringEqual(newErr.Error(), err.Error())
+}
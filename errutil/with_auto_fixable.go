@@ -0,0 +1,132 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithAutoFixable annotates an error as one that automated
+// remediation is allowed to act on, e.g. by restarting a component
+// or repairing a file. This is independent of whether the error is
+// transient or whether the caller should retry the operation.
+//
+// If the error is annotated multiple times, with WithAutoFixable()
+// or WithNotAutoFixable(), the outermost annotation wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `IsAutoFixable()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithAutoFixable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withAutoFixable{cause: err, autoFixable: true}
+}
+
+// WithNotAutoFixable annotates an error as explicitly not eligible
+// for automated remediation. This overrides a WithAutoFixable()
+// annotation on one of its causes.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `IsAutoFixable()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithNotAutoFixable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withAutoFixable{cause: err, autoFixable: false}
+}
+
+// IsAutoFixable returns true iff the outermost annotation in the
+// error's causal chain, among those attached with WithAutoFixable()
+// and WithNotAutoFixable(), marks the error as auto-fixable.
+func IsAutoFixable(err error) bool {
+	v, ok := markers.If(err, func(err error) (interface{}, bool) {
+		if w, ok := err.(*withAutoFixable); ok {
+			return w.autoFixable, true
+		}
+		return nil, false
+	})
+	return ok && v.(bool)
+}
+
+type withAutoFixable struct {
+	cause       error
+	autoFixable bool
+}
+
+var _ error = (*withAutoFixable)(nil)
+var _ errbase.SafeDetailer = (*withAutoFixable)(nil)
+var _ fmt.Formatter = (*withAutoFixable)(nil)
+var _ errbase.SafeFormatter = (*withAutoFixable)(nil)
+
+func (w *withAutoFixable) Error() string { return w.cause.Error() }
+func (w *withAutoFixable) Cause() error  { return w.cause }
+func (w *withAutoFixable) Unwrap() error { return w.cause }
+
+func (w *withAutoFixable) SafeDetails() []string { return []string{w.label()} }
+
+func (w *withAutoFixable) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withAutoFixable) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("(%s)", redact.Safe(w.label()))
+	}
+	return w.cause
+}
+
+const (
+	autoFixableLabel    = "auto-fixable"
+	notAutoFixableLabel = "not-auto-fixable"
+)
+
+func (w *withAutoFixable) label() string {
+	if w.autoFixable {
+		return autoFixableLabel
+	}
+	return notAutoFixableLabel
+}
+
+func decodeWithAutoFixable(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) == 0 {
+		return nil
+	}
+	switch details[0] {
+	case autoFixableLabel:
+		return &withAutoFixable{cause: cause, autoFixable: true}
+	case notAutoFixableLabel:
+		return &withAutoFixable{cause: cause, autoFixable: false}
+	}
+	// Some future version of the library is using a different
+	// encoding. Let DecodeError use the opaque type.
+	return nil
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withAutoFixable)(nil)), decodeWithAutoFixable)
+}
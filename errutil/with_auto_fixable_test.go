@@ -0,0 +1,69 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+)
+
+func TestWithAutoFixable(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("woo")
+	tt.Check(!errutil.IsAutoFixable(origErr))
+	tt.Check(!errutil.IsAutoFixable(nil))
+	tt.Check(errutil.WithAutoFixable(nil) == nil)
+	tt.Check(errutil.WithNotAutoFixable(nil) == nil)
+
+	err := errutil.WithAutoFixable(origErr)
+	tt.Check(errutil.IsAutoFixable(err))
+	tt.Check(errutil.IsAutoFixable(errutil.WithMessage(err, "waa")))
+
+	// The auto-fixable flag is independent of the transient flag.
+	tt.Check(!errutil.IsTransient(err))
+
+	// The outermost annotation wins.
+	err = errutil.WithNotAutoFixable(errutil.WithMessage(err, "waa"))
+	tt.Check(!errutil.IsAutoFixable(err))
+	tt.Check(errutil.IsAutoFixable(errutil.WithAutoFixable(err)))
+
+	tt.CheckStringEqual(err.Error(), "waa: woo")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", err), `waa: woo
+(1) (not-auto-fixable)
+Wraps: (2) waa
+Wraps: (3) (auto-fixable)
+Wraps: (4) woo
+Error types: (1) *errutil.withAutoFixable (2) *errutil.withPrefix (3) *errutil.withAutoFixable (4) *errors.errorString`)
+
+	// The annotation is a safe detail.
+	tt.CheckDeepEqual(errbase.GetSafeDetails(err).SafeDetails, []string{"not-auto-fixable"})
+	tt.CheckContains(string(redact.Sprintf("%+v", err).Redact()), "(3) (auto-fixable)")
+
+	// Simulate a network transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+
+	tt.Check(!errutil.IsAutoFixable(newErr))
+	tt.Check(errutil.IsAutoFixable(errbase.UnwrapOnce(errbase.UnwrapOnce(newErr))))
+	tt.CheckStringEqual(fmt.Sprintf("%+v", newErr), fmt.Sprintf("%+v", err))
+}
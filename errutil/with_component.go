@@ -0,0 +1,115 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/domains"
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithComponent annotates an error with the name of the component
+// or service that produced it. This is a finer-grained origin label
+// than error domains, useful to trace errors across services.
+//
+// The component name must not contain PII: it is considered safe for
+// reporting.
+//
+// If the annotation is applied multiple times, the innermost
+// component, i.e. the one closest to the origin of the error, wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetOriginComponent()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithComponent(err error, name string) error {
+	if err == nil {
+		return nil
+	}
+	return &withComponent{cause: err, name: name}
+}
+
+// GetOriginComponent retrieves the innermost component annotation in
+// the error's causal chain, or false if there is none.
+func GetOriginComponent(err error) (string, bool) {
+	name, ok := "", false
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		if w, isComponent := c.(*withComponent); isComponent {
+			name, ok = w.name, true
+		}
+	}
+	return name, ok
+}
+
+// OriginatedIn returns true if the innermost domain annotation or
+// the innermost component annotation in the error's causal chain
+// matches the given name, regardless of the layers that were added
+// afterwards. The name matches a domain if it is either the domain
+// itself or the argument to domains.NamedDomain() that produced it.
+func OriginatedIn(err error, domainOrComponent string) bool {
+	if all := domains.GetAllDomains(err); len(all) > 0 {
+		d := all[len(all)-1]
+		if d == domains.Domain(domainOrComponent) || d == domains.NamedDomain(domainOrComponent) {
+			return true
+		}
+	}
+	name, ok := GetOriginComponent(err)
+	return ok && name == domainOrComponent
+}
+
+type withComponent struct {
+	cause error
+	name  string
+}
+
+var _ error = (*withComponent)(nil)
+var _ errbase.SafeDetailer = (*withComponent)(nil)
+var _ fmt.Formatter = (*withComponent)(nil)
+var _ errbase.SafeFormatter = (*withComponent)(nil)
+
+func (w *withComponent) Error() string { return w.cause.Error() }
+func (w *withComponent) Cause() error  { return w.cause }
+func (w *withComponent) Unwrap() error { return w.cause }
+
+func (w *withComponent) SafeDetails() []string { return []string{w.name} }
+
+func (w *withComponent) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withComponent) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("component: %s", redact.Safe(w.name))
+	}
+	return w.cause
+}
+
+func decodeWithComponent(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) == 0 {
+		// Some future version of the library is using a different
+		// encoding. Let DecodeError use the opaque type.
+		return nil
+	}
+	return &withComponent{cause: cause, name: details[0]}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withComponent)(nil)), decodeWithComponent)
+}
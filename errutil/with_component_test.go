@@ -0,0 +1,101 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/domains"
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+)
+
+func TestWithComponent(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("woo")
+	_, ok := errutil.GetOriginComponent(origErr)
+	tt.Check(!ok)
+	tt.Check(errutil.WithComponent(nil, "storage") == nil)
+
+	err := errutil.WithComponent(origErr, "storage")
+	// The innermost component wins.
+	err = errutil.WithComponent(errutil.WithMessage(err, "waa"), "gateway")
+
+	name, ok := errutil.GetOriginComponent(err)
+	tt.Check(ok)
+	tt.CheckStringEqual(name, "storage")
+
+	tt.CheckStringEqual(err.Error(), "waa: woo")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", err), `waa: woo
+(1) component: gateway
+Wraps: (2) waa
+Wraps: (3) component: storage
+Wraps: (4) woo
+Error types: (1) *errutil.withComponent (2) *errutil.withPrefix (3) *errutil.withComponent (4) *errors.errorString`)
+
+	// The component name is safe for reporting.
+	tt.CheckDeepEqual(errbase.GetSafeDetails(err).SafeDetails, []string{"gateway"})
+	tt.CheckStringEqual(string(redact.Sprintf("%+v", err).Redact()), `waa: ‹×›
+(1) component: gateway
+Wraps: (2) waa
+Wraps: (3) component: storage
+Wraps: (4) ‹×›
+Error types: (1) *errutil.withComponent (2) *errutil.withPrefix (3) *errutil.withComponent (4) *errors.errorString`)
+
+	// Simulate a network transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+
+	name, ok = errutil.GetOriginComponent(newErr)
+	tt.Check(ok)
+	tt.CheckStringEqual(name, "storage")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", newErr), fmt.Sprintf("%+v", err))
+}
+
+func TestOriginatedIn(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("woo")
+	tt.Check(!errutil.OriginatedIn(nil, "storage"))
+	tt.Check(!errutil.OriginatedIn(origErr, "storage"))
+
+	// Component annotations: the innermost one counts.
+	err := errutil.WithComponent(origErr, "storage")
+	err = errutil.WithComponent(errutil.Wrap(err, "waa"), "gateway")
+	tt.Check(errutil.OriginatedIn(err, "storage"))
+	tt.Check(!errutil.OriginatedIn(err, "gateway"))
+	tt.Check(!errutil.OriginatedIn(err, "sql"))
+
+	// Domain annotations: the innermost one counts, designated either
+	// by the domain itself or by its name.
+	err = domains.WithDomain(origErr, domains.NamedDomain("kv"))
+	err = domains.WithDomain(errutil.Wrap(err, "waa"), domains.NamedDomain("sql"))
+	err = errutil.Wrap(err, "wuu")
+	tt.Check(errutil.OriginatedIn(err, "kv"))
+	tt.Check(errutil.OriginatedIn(err, string(domains.NamedDomain("kv"))))
+	tt.Check(!errutil.OriginatedIn(err, "sql"))
+
+	// Both kinds of annotations.
+	err = errutil.WithComponent(err, "storage")
+	tt.Check(errutil.OriginatedIn(err, "kv"))
+	tt.Check(errutil.OriginatedIn(err, "storage"))
+	tt.Check(!errutil.OriginatedIn(err, "sql"))
+}
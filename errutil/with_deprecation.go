@@ -0,0 +1,106 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithDeprecation annotates an error with a notice that the code path
+// where it occurred is deprecated and slated for removal, along with
+// the replacement to use instead. Unlike hints, which are general
+// advice for the user, this is meant to guide the migration off
+// legacy code paths.
+//
+// The replacement must not contain PII: it is considered safe for
+// reporting.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetDeprecations()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithDeprecation(err error, replacement string) error {
+	if err == nil {
+		return nil
+	}
+	return &withDeprecation{cause: err, replacement: replacement}
+}
+
+// GetDeprecations retrieves the replacements attached with
+// WithDeprecation() in the error's causal chain, using a post-order
+// traversal, i.e. the innermost first. The replacements are
+// de-duplicated.
+func GetDeprecations(err error) []string {
+	return getDeprecationsInternal(err, nil, make(map[string]struct{}))
+}
+
+func getDeprecationsInternal(err error, res []string, seen map[string]struct{}) []string {
+	if c := errbase.UnwrapOnce(err); c != nil {
+		res = getDeprecationsInternal(c, res, seen)
+	}
+	if w, ok := err.(*withDeprecation); ok {
+		if _, ok := seen[w.replacement]; !ok {
+			seen[w.replacement] = struct{}{}
+			res = append(res, w.replacement)
+		}
+	}
+	return res
+}
+
+type withDeprecation struct {
+	cause       error
+	replacement string
+}
+
+var _ error = (*withDeprecation)(nil)
+var _ errbase.SafeDetailer = (*withDeprecation)(nil)
+var _ fmt.Formatter = (*withDeprecation)(nil)
+var _ errbase.SafeFormatter = (*withDeprecation)(nil)
+
+func (w *withDeprecation) Error() string { return w.cause.Error() }
+func (w *withDeprecation) Cause() error  { return w.cause }
+func (w *withDeprecation) Unwrap() error { return w.cause }
+
+func (w *withDeprecation) SafeDetails() []string { return []string{w.replacement} }
+
+func (w *withDeprecation) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withDeprecation) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("deprecated; use: %s", redact.Safe(w.replacement))
+	}
+	return w.cause
+}
+
+func decodeWithDeprecation(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) < 1 {
+		// Some future version of the library is using a different
+		// encoding. Let DecodeError use the opaque type.
+		return nil
+	}
+	return &withDeprecation{cause: cause, replacement: details[0]}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withDeprecation)(nil)), decodeWithDeprecation)
+}
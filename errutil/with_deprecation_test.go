@@ -0,0 +1,53 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+func TestDeprecation(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("hello")
+	tt.Check(errutil.GetDeprecations(origErr) == nil)
+	tt.Check(errutil.WithDeprecation(nil, "NewAPI") == nil)
+
+	// Multiple notices across layers are collected, innermost first,
+	// without duplicates.
+	err := errutil.WithDeprecation(origErr, "NewAPI")
+	err = errutil.Wrap(err, "woo")
+	err = errutil.WithDeprecation(err, "OtherAPI")
+	err = errutil.WithDeprecation(err, "NewAPI")
+	tt.CheckStringEqual(err.Error(), "woo: hello")
+	tt.CheckDeepEqual(errutil.GetDeprecations(err), []string{"NewAPI", "OtherAPI"})
+
+	// The notices are shown in the verbose rendering, and are safe.
+	tt.CheckContains(fmt.Sprintf("%+v", err), "deprecated; use: OtherAPI")
+	tt.CheckDeepEqual(errbase.GetSafeDetails(err).SafeDetails, []string{"NewAPI"})
+
+	// The notices survive a network transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+	tt.CheckDeepEqual(errutil.GetDeprecations(newErr), []string{"NewAPI", "OtherAPI"})
+	tt.CheckContains(fmt.Sprintf("%+v", newErr), "deprecated; use: NewAPI")
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithDuration annotates an error with the amount of time an
+// operation ran before it failed. This is useful for latency
+// attribution.
+//
+// If the annotation is applied multiple times, the outermost
+// duration wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetDuration()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithDuration(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &withDuration{cause: err, duration: d}
+}
+
+// GetDuration retrieves the outermost duration annotation
+// in the error's causal chain, or false if there is none.
+func GetDuration(err error) (time.Duration, bool) {
+	v, ok := markers.If(err, func(err error) (interface{}, bool) {
+		if w, ok := err.(*withDuration); ok {
+			return w.duration, true
+		}
+		return nil, false
+	})
+	if !ok {
+		return 0, false
+	}
+	return v.(time.Duration), true
+}
+
+type withDuration struct {
+	cause    error
+	duration time.Duration
+}
+
+var _ error = (*withDuration)(nil)
+var _ errbase.SafeDetailer = (*withDuration)(nil)
+var _ fmt.Formatter = (*withDuration)(nil)
+var _ errbase.SafeFormatter = (*withDuration)(nil)
+var _ errbase.InlineAnnotator = (*withDuration)(nil)
+
+func (w *withDuration) Error() string { return w.cause.Error() }
+func (w *withDuration) Cause() error  { return w.cause }
+func (w *withDuration) Unwrap() error { return w.cause }
+
+func (w *withDuration) SafeDetails() []string { return []string{w.duration.String()} }
+
+func (w *withDuration) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withDuration) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("after: %s", redact.Safe(w.duration))
+	}
+	return w.cause
+}
+
+// InlineAnnotation implements errbase.InlineAnnotator.
+func (w *withDuration) InlineAnnotation() redact.RedactableString {
+	return redact.Sprintf("+%s", redact.Safe(w.duration))
+}
+
+func decodeWithDuration(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) == 0 {
+		return nil
+	}
+	d, err := time.ParseDuration(details[0])
+	if err != nil {
+		// Some future version of the library is using a different
+		// encoding. Let DecodeError use the opaque type.
+		return nil
+	}
+	return &withDuration{cause: cause, duration: d}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withDuration)(nil)), decodeWithDuration)
+}
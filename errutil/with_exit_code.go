@@ -0,0 +1,105 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithExitCode annotates an error with the exit code that a
+// command-line program should use when terminating due to this
+// error. This lets deep code decide the appropriate exit status.
+//
+// If the annotation is applied multiple times, the outermost
+// exit code wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetExitCode()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return &withExitCode{cause: err, code: code}
+}
+
+// GetExitCode retrieves the outermost exit code annotation
+// in the error's causal chain, or false if there is none.
+func GetExitCode(err error) (int, bool) {
+	v, ok := markers.If(err, func(err error) (interface{}, bool) {
+		if w, ok := err.(*withExitCode); ok {
+			return w.code, true
+		}
+		return nil, false
+	})
+	if !ok {
+		return 0, false
+	}
+	return v.(int), true
+}
+
+type withExitCode struct {
+	cause error
+	code  int
+}
+
+var _ error = (*withExitCode)(nil)
+var _ errbase.SafeDetailer = (*withExitCode)(nil)
+var _ fmt.Formatter = (*withExitCode)(nil)
+var _ errbase.SafeFormatter = (*withExitCode)(nil)
+
+func (w *withExitCode) Error() string { return w.cause.Error() }
+func (w *withExitCode) Cause() error  { return w.cause }
+func (w *withExitCode) Unwrap() error { return w.cause }
+
+func (w *withExitCode) SafeDetails() []string { return []string{strconv.Itoa(w.code)} }
+
+func (w *withExitCode) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withExitCode) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("exit code: %d", redact.Safe(w.code))
+	}
+	return w.cause
+}
+
+func decodeWithExitCode(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) == 0 {
+		return nil
+	}
+	code, err := strconv.Atoi(details[0])
+	if err != nil {
+		// Some future version of the library is using a different
+		// encoding. Let DecodeError use the opaque type.
+		return nil
+	}
+	return &withExitCode{cause: cause, code: code}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withExitCode)(nil)), decodeWithExitCode)
+	errbase.RegisterStrippableAnnotation(errbase.GetTypeKey((*withExitCode)(nil)))
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+func TestWithExitCode(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("woo")
+	_, ok := errutil.GetExitCode(origErr)
+	tt.Check(!ok)
+	tt.Check(errutil.WithExitCode(nil, 1) == nil)
+
+	// The outermost exit code wins.
+	err := errutil.WithExitCode(errutil.WithExitCode(origErr, 2), 3)
+
+	code, ok := errutil.GetExitCode(err)
+	tt.Check(ok)
+	tt.CheckEqual(code, 3)
+
+	tt.CheckStringEqual(err.Error(), "woo")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", err), `woo
+(1) exit code: 3
+Wraps: (2) exit code: 2
+Wraps: (3) woo
+Error types: (1) *errutil.withExitCode (2) *errutil.withExitCode (3) *errors.errorString`)
+
+	// Simulate a network transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+
+	code, ok = errutil.GetExitCode(newErr)
+	tt.Check(ok)
+	tt.CheckEqual(code, 3)
+	code, ok = errutil.GetExitCode(errbase.UnwrapOnce(newErr))
+	tt.Check(ok)
+	tt.CheckEqual(code, 2)
+	tt.CheckStringEqual(fmt.Sprintf("%+v", newErr), fmt.Sprintf("%+v", err))
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errorspb"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithFingerprint annotates an error with a key that overrides its
+// fingerprint, i.e. the key used to group similar errors together
+// in dashboards and Sentry reports. This is useful when errors with
+// the same structure have different meanings and must be grouped
+// separately.
+//
+// The key must not contain PII: it is considered safe for reporting.
+//
+// If the annotation is applied multiple times, the outermost key
+// wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `Fingerprint()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports, where it also overrides the event fingerprint.
+func WithFingerprint(err error, key string) error {
+	if err == nil {
+		return nil
+	}
+	return &withFingerprint{cause: err, key: key}
+}
+
+// GetFingerprintOverride retrieves the outermost key attached with
+// WithFingerprint() in the error's causal chain, or false if there
+// is none.
+func GetFingerprintOverride(err error) (string, bool) {
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		if w, ok := c.(*withFingerprint); ok {
+			return w.key, true
+		}
+	}
+	return "", false
+}
+
+// Fingerprint returns a key suitable to group similar errors. This
+// is the key attached with WithFingerprint(), if any. Otherwise, it
+// is derived from the structure of the error, i.e. the types of its
+// layers, and is independent of the error messages. The result is
+// stable across processes and across network transfers.
+func Fingerprint(err error) string {
+	if key, ok := GetFingerprintOverride(err); ok {
+		return key
+	}
+	h := sha256.New()
+	errbase.WalkWithMarks(err, func(_ error, mark errorspb.ErrorTypeMark, isLeaf bool) {
+		fmt.Fprintf(h, "%s\x00%s\x00%t\x00", mark.FamilyName, mark.Extension, isLeaf)
+	})
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type withFingerprint struct {
+	cause error
+	key   string
+}
+
+var _ error = (*withFingerprint)(nil)
+var _ errbase.SafeDetailer = (*withFingerprint)(nil)
+var _ fmt.Formatter = (*withFingerprint)(nil)
+var _ errbase.SafeFormatter = (*withFingerprint)(nil)
+
+func (w *withFingerprint) Error() string { return w.cause.Error() }
+func (w *withFingerprint) Cause() error  { return w.cause }
+func (w *withFingerprint) Unwrap() error { return w.cause }
+
+func (w *withFingerprint) SafeDetails() []string { return []string{w.key} }
+
+func (w *withFingerprint) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withFingerprint) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("fingerprint: %s", redact.Safe(w.key))
+	}
+	return w.cause
+}
+
+func decodeWithFingerprint(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) == 0 {
+		// Some future version of the library is using a different
+		// encoding. Let DecodeError use the opaque type.
+		return nil
+	}
+	return &withFingerprint{cause: cause, key: details[0]}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withFingerprint)(nil)), decodeWithFingerprint)
+}
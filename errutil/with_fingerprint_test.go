@@ -0,0 +1,63 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+func TestFingerprint(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	// Without override, the fingerprint depends on the structure
+	// of the error but not on the messages.
+	err1 := errutil.Wrap(goErr.New("hello"), "woo")
+	err2 := errutil.Wrap(goErr.New("world"), "waa")
+	err3 := errutil.WithMessage(goErr.New("hello"), "woo")
+	tt.CheckEqual(errutil.Fingerprint(err1), errutil.Fingerprint(err2))
+	tt.Check(errutil.Fingerprint(err1) != errutil.Fingerprint(err3))
+	_, ok := errutil.GetFingerprintOverride(err1)
+	tt.Check(!ok)
+
+	// With override, the fingerprint is the provided key.
+	err := errutil.WithFingerprint(err1, "inner")
+	tt.CheckStringEqual(errutil.Fingerprint(err), "inner")
+	err = errutil.WithFingerprint(errutil.Wrap(err, "outer"), "outer")
+	tt.CheckStringEqual(errutil.Fingerprint(err), "outer")
+	tt.CheckStringEqual(err.Error(), "outer: woo: hello")
+
+	// The key is a safe detail.
+	tt.CheckContains(fmt.Sprintf("%+v", err), "fingerprint: outer")
+	tt.CheckDeepEqual(errbase.GetSafeDetails(err).SafeDetails, []string{"outer"})
+
+	// The override and the structural fingerprint survive a network
+	// transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+	tt.CheckStringEqual(errutil.Fingerprint(newErr), "outer")
+
+	enc = errbase.EncodeError(context.Background(), err1)
+	newErr = errbase.DecodeError(context.Background(), enc)
+	tt.CheckEqual(errutil.Fingerprint(newErr), errutil.Fingerprint(err1))
+
+	tt.Check(errutil.WithFingerprint(nil, "woo") == nil)
+}
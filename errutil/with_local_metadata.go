@@ -0,0 +1,126 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithLocalMetadata annotates an error with key/value metadata that
+// is only available in the current process. This is meant for
+// sensitive debugging context that may be logged locally but must
+// never leave the process.
+//
+// The metadata is considered unsafe: it is never included in safe
+// details, and is redacted in redactable output. It is also not
+// preserved by EncodeError/DecodeError: the annotation is omitted
+// from the encoded error.
+//
+// Detail is shown:
+// - via `GetLocalMetadata()` below.
+// - when formatting with `%+v` (redacted in Sentry reports).
+func WithLocalMetadata(err error, kv map[string]string) error {
+	if err == nil {
+		return nil
+	}
+	m := make(map[string]string, len(kv))
+	for k, v := range kv {
+		m[k] = v
+	}
+	return &withLocalMetadata{cause: err, kv: m}
+}
+
+// GetLocalMetadata retrieves the metadata attached with
+// WithLocalMetadata() in the error's direct causal chain. If the
+// same key was attached multiple times, the outermost value wins.
+// The result is nil if there is no metadata.
+func GetLocalMetadata(err error) map[string]string {
+	var res map[string]string
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		w, ok := c.(*withLocalMetadata)
+		if !ok {
+			continue
+		}
+		if res == nil {
+			res = make(map[string]string, len(w.kv))
+		}
+		for k, v := range w.kv {
+			if _, ok := res[k]; !ok {
+				res[k] = v
+			}
+		}
+	}
+	return res
+}
+
+type withLocalMetadata struct {
+	cause error
+	kv    map[string]string
+}
+
+var _ error = (*withLocalMetadata)(nil)
+var _ fmt.Formatter = (*withLocalMetadata)(nil)
+var _ errbase.SafeFormatter = (*withLocalMetadata)(nil)
+var _ errbase.Cloner = (*withLocalMetadata)(nil)
+
+func (w *withLocalMetadata) Error() string { return w.cause.Error() }
+func (w *withLocalMetadata) Cause() error  { return w.cause }
+func (w *withLocalMetadata) Unwrap() error { return w.cause }
+
+// CloneWithCause implements the errbase.Cloner interface. The metadata
+// is not encoded, so the layer cannot be rebuilt from its encoding.
+func (w *withLocalMetadata) CloneWithCause(cause error) error {
+	kv := make(map[string]string, len(w.kv))
+	for k, v := range w.kv {
+		kv[k] = v
+	}
+	return &withLocalMetadata{cause: cause, kv: kv}
+}
+
+func (w *withLocalMetadata) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withLocalMetadata) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		keys := make([]string, 0, len(w.kv))
+		for k := range w.kv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		p.Printf("local metadata:")
+		for _, k := range keys {
+			p.Printf(" %s=%s", k, w.kv[k])
+		}
+	}
+	return w.cause
+}
+
+// decodeWithLocalMetadata drops the annotation, for the errors
+// encoded by previous versions of the library which did not omit it.
+// The metadata is local-only and was not encoded.
+func decodeWithLocalMetadata(
+	_ context.Context, cause error, _ string, _ []string, _ proto.Message,
+) error {
+	return cause
+}
+
+func init() {
+	errbase.RegisterLocalOnlyWrapper(errbase.GetTypeKey((*withLocalMetadata)(nil)))
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withLocalMetadata)(nil)), decodeWithLocalMetadata)
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+)
+
+func TestWithLocalMetadata(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("woo")
+	tt.Check(errutil.GetLocalMetadata(origErr) == nil)
+	tt.Check(errutil.WithLocalMetadata(nil, map[string]string{"a": "b"}) == nil)
+
+	// The outermost value wins for a given key.
+	err := errutil.WithLocalMetadata(origErr, map[string]string{"user": "alice", "ip": "10.0.0.1"})
+	err = errutil.WithLocalMetadata(err, map[string]string{"user": "bob"})
+
+	tt.CheckDeepEqual(errutil.GetLocalMetadata(err),
+		map[string]string{"user": "bob", "ip": "10.0.0.1"})
+
+	tt.CheckStringEqual(err.Error(), "woo")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", err), `woo
+(1) local metadata: user=bob
+Wraps: (2) local metadata: ip=10.0.0.1 user=alice
+Wraps: (3) woo
+Error types: (1) *errutil.withLocalMetadata (2) *errutil.withLocalMetadata (3) *errors.errorString`)
+
+	// The metadata is not part of the safe details.
+	for _, sd := range errbase.GetAllSafeDetails(err) {
+		for _, d := range sd.SafeDetails {
+			tt.Check(!strings.Contains(d, "alice"))
+			tt.Check(!strings.Contains(d, "bob"))
+		}
+	}
+
+	// The metadata is redacted in redactable output.
+	redacted := redact.Sprintf("%+v", err).Redact()
+	tt.Check(!strings.Contains(string(redacted), "alice"))
+	tt.Check(!strings.Contains(string(redacted), "bob"))
+
+	// The metadata does not survive a network transfer: the
+	// annotation is not encoded at all.
+	enc := errbase.EncodeError(context.Background(), err)
+	tt.CheckDeepEqual(enc, errbase.EncodeError(context.Background(), origErr))
+	newErr := errbase.DecodeError(context.Background(), enc)
+
+	tt.Check(errutil.GetLocalMetadata(newErr) == nil)
+	tt.CheckStringEqual(newErr.Error(), "woo")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", newErr), fmt.Sprintf("%+v", origErr))
+
+	// This is also true when the annotation is not the outermost layer.
+	wrapped := fmt.Errorf("outer: %w", err)
+	enc = errbase.EncodeError(context.Background(), wrapped)
+	tt.CheckDeepEqual(enc, errbase.EncodeError(context.Background(), fmt.Errorf("outer: %w", origErr)))
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithLogged marks an error as already logged. This enables an outer
+// handler to check IsLogged() and avoid logging the same error twice
+// as it is propagated up the call stack.
+//
+// The mark is only available in the current process. It produces no
+// details and is not preserved by EncodeError/DecodeError: the
+// annotation is omitted from the encoded error.
+func WithLogged(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withLogged{cause: err}
+}
+
+// IsLogged returns true iff the error was marked with WithLogged() in
+// the current process.
+func IsLogged(err error) bool {
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		if _, ok := c.(*withLogged); ok {
+			return true
+		}
+	}
+	return false
+}
+
+type withLogged struct {
+	cause error
+}
+
+var _ error = (*withLogged)(nil)
+var _ fmt.Formatter = (*withLogged)(nil)
+var _ errbase.SafeFormatter = (*withLogged)(nil)
+var _ errbase.Cloner = (*withLogged)(nil)
+
+func (w *withLogged) Error() string { return w.cause.Error() }
+func (w *withLogged) Cause() error  { return w.cause }
+func (w *withLogged) Unwrap() error { return w.cause }
+
+// CloneWithCause implements the errbase.Cloner interface. The mark is
+// not encoded, so the layer cannot be rebuilt from its encoding.
+func (w *withLogged) CloneWithCause(cause error) error { return &withLogged{cause: cause} }
+
+func (w *withLogged) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withLogged) SafeFormatError(p errbase.Printer) error { return w.cause }
+
+// decodeWithLogged drops the annotation, for the errors encoded by
+// previous versions of the library which did not omit it. The mark is
+// local-only.
+func decodeWithLogged(_ context.Context, cause error, _ string, _ []string, _ proto.Message) error {
+	return cause
+}
+
+func init() {
+	errbase.RegisterLocalOnlyWrapper(errbase.GetTypeKey((*withLogged)(nil)))
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withLogged)(nil)), decodeWithLogged)
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+func TestWithLogged(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("woo")
+	tt.Check(!errutil.IsLogged(origErr))
+	tt.Check(errutil.WithLogged(nil) == nil)
+
+	err := errutil.WithMessage(errutil.WithLogged(origErr), "waa")
+	tt.Check(errutil.IsLogged(err))
+	tt.CheckStringEqual(err.Error(), "waa: woo")
+
+	// The mark produces no details.
+	tt.CheckDeepEqual(errbase.GetSafeDetails(errbase.UnwrapOnce(err)).SafeDetails, []string(nil))
+
+	// The mark does not survive a network transfer: the annotation is
+	// not encoded at all.
+	enc := errbase.EncodeError(context.Background(), err)
+	tt.CheckDeepEqual(enc, errbase.EncodeError(context.Background(), errutil.WithMessage(origErr, "waa")))
+	newErr := errbase.DecodeError(context.Background(), enc)
+
+	tt.Check(!errutil.IsLogged(newErr))
+	tt.CheckStringEqual(newErr.Error(), "waa: woo")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", newErr),
+		fmt.Sprintf("%+v", errutil.WithMessage(origErr, "waa")))
+}
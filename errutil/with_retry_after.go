@@ -0,0 +1,105 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithRetryAfter annotates an error with the amount of time the
+// caller should wait before retrying the operation, e.g. to signal
+// rate limiting or backoff. A gRPC or HTTP boundary can translate
+// this to the corresponding standard metadata or header.
+//
+// If the annotation is applied multiple times, the outermost
+// duration wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetRetryAfter()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithRetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &withRetryAfter{cause: err, retryAfter: d}
+}
+
+// GetRetryAfter retrieves the outermost retry-after annotation
+// in the error's causal chain, or false if there is none.
+func GetRetryAfter(err error) (time.Duration, bool) {
+	v, ok := markers.If(err, func(err error) (interface{}, bool) {
+		if w, ok := err.(*withRetryAfter); ok {
+			return w.retryAfter, true
+		}
+		return nil, false
+	})
+	if !ok {
+		return 0, false
+	}
+	return v.(time.Duration), true
+}
+
+type withRetryAfter struct {
+	cause      error
+	retryAfter time.Duration
+}
+
+var _ error = (*withRetryAfter)(nil)
+var _ errbase.SafeDetailer = (*withRetryAfter)(nil)
+var _ fmt.Formatter = (*withRetryAfter)(nil)
+var _ errbase.SafeFormatter = (*withRetryAfter)(nil)
+
+func (w *withRetryAfter) Error() string { return w.cause.Error() }
+func (w *withRetryAfter) Cause() error  { return w.cause }
+func (w *withRetryAfter) Unwrap() error { return w.cause }
+
+func (w *withRetryAfter) SafeDetails() []string { return []string{w.retryAfter.String()} }
+
+func (w *withRetryAfter) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withRetryAfter) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("retry after: %s", redact.Safe(w.retryAfter))
+	}
+	return w.cause
+}
+
+func decodeWithRetryAfter(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) == 0 {
+		return nil
+	}
+	d, err := time.ParseDuration(details[0])
+	if err != nil {
+		// Some future version of the library is using a different
+		// encoding. Let DecodeError use the opaque type.
+		return nil
+	}
+	return &withRetryAfter{cause: cause, retryAfter: d}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withRetryAfter)(nil)), decodeWithRetryAfter)
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+func TestWithRetryAfter(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("woo")
+	_, ok := errutil.GetRetryAfter(origErr)
+	tt.Check(!ok)
+	tt.Check(errutil.WithRetryAfter(nil, time.Second) == nil)
+
+	err := errutil.WithRetryAfter(origErr, 1200*time.Millisecond)
+	// The outermost duration wins.
+	err = errutil.WithRetryAfter(errutil.WithMessage(err, "waa"), 3*time.Second)
+
+	d, ok := errutil.GetRetryAfter(err)
+	tt.Check(ok)
+	tt.CheckEqual(d, 3*time.Second)
+
+	tt.CheckStringEqual(err.Error(), "waa: woo")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", err), `waa: woo
+(1) retry after: 3s
+Wraps: (2) waa
+Wraps: (3) retry after: 1.2s
+Wraps: (4) woo
+Error types: (1) *errutil.withRetryAfter (2) *errutil.withPrefix (3) *errutil.withRetryAfter (4) *errors.errorString`)
+
+	// The annotation is a safe detail.
+	tt.CheckDeepEqual(errbase.GetSafeDetails(err).SafeDetails, []string{"3s"})
+
+	// Simulate a network transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+
+	d, ok = errutil.GetRetryAfter(newErr)
+	tt.Check(ok)
+	tt.CheckEqual(d, 3*time.Second)
+	d, ok = errutil.GetRetryAfter(errbase.UnwrapOnce(errbase.UnwrapOnce(newErr)))
+	tt.Check(ok)
+	tt.CheckEqual(d, 1200*time.Millisecond)
+	tt.CheckStringEqual(fmt.Sprintf("%+v", newErr), fmt.Sprintf("%+v", err))
+}
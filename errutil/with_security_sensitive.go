@@ -0,0 +1,97 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithSecuritySensitive flags an error as security sensitive, i.e.
+// its unsafe parts must never appear unredacted in any output. The
+// unsafe parts of the flagged error are redacted:
+//
+//   - by the Error() method of the flag;
+//   - when formatting with fmt, including %v and %+v, like
+//     redact.Sprint() followed by Redact();
+//   - in reports, as usual.
+//
+// The layers wrapped around the flag later are not redacted, so that
+// the Error() method of every layer remains consistent with its
+// rendering with %v. The error marks used by markers.Is() are
+// computed from the unredacted message, so that errors that only
+// differ in their unsafe parts are not equivalent. The flag is
+// preserved across the network.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `IsSecuritySensitive()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithSecuritySensitive(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withSecuritySensitive{cause: err}
+}
+
+// IsSecuritySensitive returns true iff the error was flagged with
+// WithSecuritySensitive(), anywhere in its causal chain or the causes
+// of its multi-errors.
+func IsSecuritySensitive(err error) bool { return errbase.IsSecuritySensitive(err) }
+
+type withSecuritySensitive struct {
+	cause error
+}
+
+var _ error = (*withSecuritySensitive)(nil)
+var _ errbase.SafeDetailer = (*withSecuritySensitive)(nil)
+var _ fmt.Formatter = (*withSecuritySensitive)(nil)
+var _ errbase.SafeFormatter = (*withSecuritySensitive)(nil)
+
+func (w *withSecuritySensitive) Error() string {
+	return redact.Sprint(w.cause).Redact().StripMarkers()
+}
+func (w *withSecuritySensitive) Cause() error  { return w.cause }
+func (w *withSecuritySensitive) Unwrap() error { return w.cause }
+
+func (w *withSecuritySensitive) SafeDetails() []string { return []string{securitySensitiveLabel} }
+
+func (w *withSecuritySensitive) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withSecuritySensitive) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("(%s)", redact.Safe(securitySensitiveLabel))
+	}
+	return w.cause
+}
+
+const securitySensitiveLabel = "security sensitive"
+
+func decodeWithSecuritySensitive(
+	_ context.Context, cause error, _ string, _ []string, _ proto.Message,
+) error {
+	return &withSecuritySensitive{cause: cause}
+}
+
+func init() {
+	tk := errbase.GetTypeKey((*withSecuritySensitive)(nil))
+	errbase.RegisterWrapperDecoder(tk, decodeWithSecuritySensitive)
+	errbase.RegisterSecuritySensitiveLayer(tk)
+}
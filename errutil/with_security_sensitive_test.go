@@ -0,0 +1,110 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+)
+
+func TestSecuritySensitive(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := errutil.Newf("password is %s", "hunter2")
+	tt.Check(!errutil.IsSecuritySensitive(origErr))
+	tt.CheckStringEqual(origErr.Error(), "password is hunter2")
+	tt.Check(errutil.WithSecuritySensitive(nil) == nil)
+
+	err := errutil.WithSecuritySensitive(origErr)
+	tt.Check(errutil.IsSecuritySensitive(err))
+
+	// Even Error() is redacted.
+	tt.CheckStringEqual(err.Error(), "password is ×")
+
+	// All the fmt verbs are redacted.
+	tt.CheckStringEqual(fmt.Sprintf("%v", err), "password is ×")
+	tt.CheckStringEqual(fmt.Sprintf("%s", err), "password is ×")
+	tt.CheckStringEqual(fmt.Sprintf("%q", err), `"password is ×"`)
+	verbose := fmt.Sprintf("%+v", err)
+	tt.CheckContains(verbose, "(security sensitive)")
+	tt.Check(!strings.Contains(verbose, "hunter2"))
+	tt.Check(!strings.Contains(fmt.Sprintf("%#v", err), "hunter2"))
+	tt.CheckStringEqual(redact.Sprint(err).Redact().StripMarkers(), "password is ×")
+
+	// The flag is detected anywhere in the chain. The layers wrapped
+	// around it are not redacted, consistently with their Error()
+	// method.
+	wrapped := errutil.Wrapf(err, "outer %s", "secret")
+	tt.Check(errutil.IsSecuritySensitive(wrapped))
+	tt.CheckStringEqual(wrapped.Error(), "outer secret: password is ×")
+	tt.CheckStringEqual(fmt.Sprintf("%v", wrapped), wrapped.Error())
+	tt.Check(!strings.Contains(fmt.Sprintf("%+v", wrapped), "hunter2"))
+	tt.Check(!strings.Contains(fmt.Sprintf("%#v", wrapped), "hunter2"))
+
+	// The flag is preserved across the network.
+	enc := errbase.EncodeError(context.Background(), wrapped)
+	newErr := errbase.DecodeError(context.Background(), enc)
+	tt.Check(errutil.IsSecuritySensitive(newErr))
+	tt.CheckStringEqual(fmt.Sprintf("%v", newErr), "outer secret: password is ×")
+	tt.Check(!strings.Contains(fmt.Sprintf("%+v", newErr), "hunter2"))
+
+	// The errors that only differ in their unsafe parts are not
+	// equivalent, even though their Error() is the same.
+	alice := errutil.WithSecuritySensitive(errutil.Newf("user %s", "alice"))
+	bob := errutil.WithSecuritySensitive(errutil.Newf("user %s", "bob"))
+	tt.CheckStringEqual(alice.Error(), bob.Error())
+	tt.Check(!markers.Is(alice, bob))
+	tt.Check(!markers.Is(errutil.Wrap(alice, "outer"), errutil.Wrap(bob, "outer")))
+	tt.Check(markers.Is(alice, errutil.WithSecuritySensitive(errutil.Newf("user %s", "alice"))))
+	enc = errbase.EncodeError(context.Background(), alice)
+	tt.Check(markers.Is(errbase.DecodeError(context.Background(), enc), alice))
+	tt.Check(!markers.Is(errbase.DecodeError(context.Background(), enc), bob))
+
+	// Other errors are unaffected.
+	tt.CheckStringEqual(fmt.Sprintf("%v", goErr.New("hello")), "hello")
+}
+
+// cyclicErr is a wrapper whose causal chain may loop back onto itself.
+type cyclicErr struct{ cause error }
+
+func (e *cyclicErr) Error() string { return "cyclic" }
+func (e *cyclicErr) Unwrap() error { return e.cause }
+
+func TestSecuritySensitiveCyclicChain(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	// Checking the flag terminates on a cyclic chain, including
+	// when the chain is formatted with a depth limit.
+	c := &cyclicErr{}
+	c.cause = c
+	tt.Check(!errutil.IsSecuritySensitive(c))
+
+	errbase.SetMaxFormatDepth(3)
+	defer errbase.SetMaxFormatDepth(0)
+	tt.CheckContains(fmt.Sprintf("%+v", errbase.Formattable(c)), "more layers omitted")
+
+	err := errutil.WithSecuritySensitive(c)
+	tt.Check(errutil.IsSecuritySensitive(err))
+	tt.CheckContains(fmt.Sprintf("%+v", err), "more layers omitted")
+}
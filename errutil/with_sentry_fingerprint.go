@@ -0,0 +1,120 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithSentryFingerprint annotates an error with the components of
+// the fingerprint of the Sentry events reported for it, which
+// control how Sentry groups the events into issues. The special
+// component "{{ default }}" can be used to refine Sentry's default
+// grouping instead of replacing it.
+//
+// The components must not contain PII: they are considered safe for
+// reporting. If no component is given, the error is returned
+// unchanged.
+//
+// If the annotation is applied multiple times, the outermost
+// components win. In Sentry reports, the outermost of this annotation
+// and WithFingerprint() determines the event fingerprint.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetSentryFingerprint()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports, where it sets the event fingerprint.
+func WithSentryFingerprint(err error, components ...string) error {
+	if err == nil || len(components) == 0 {
+		return err
+	}
+	return &withSentryFingerprint{cause: err, components: append([]string(nil), components...)}
+}
+
+// GetSentryFingerprint retrieves the outermost components attached
+// with WithSentryFingerprint() in the error's causal chain, or false
+// if there are none.
+func GetSentryFingerprint(err error) ([]string, bool) {
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		if w, ok := c.(*withSentryFingerprint); ok {
+			return append([]string(nil), w.components...), true
+		}
+	}
+	return nil, false
+}
+
+// GetSentryEventFingerprint retrieves the fingerprint of the Sentry
+// events reported for the error: the outermost of the components
+// attached with WithSentryFingerprint() and the key attached with
+// WithFingerprint() in the error's causal chain, or false if there
+// are none.
+func GetSentryEventFingerprint(err error) ([]string, bool) {
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		switch w := c.(type) {
+		case *withSentryFingerprint:
+			return append([]string(nil), w.components...), true
+		case *withFingerprint:
+			return []string{w.key}, true
+		}
+	}
+	return nil, false
+}
+
+type withSentryFingerprint struct {
+	cause      error
+	components []string
+}
+
+var _ error = (*withSentryFingerprint)(nil)
+var _ errbase.SafeDetailer = (*withSentryFingerprint)(nil)
+var _ fmt.Formatter = (*withSentryFingerprint)(nil)
+var _ errbase.SafeFormatter = (*withSentryFingerprint)(nil)
+
+func (w *withSentryFingerprint) Error() string { return w.cause.Error() }
+func (w *withSentryFingerprint) Cause() error  { return w.cause }
+func (w *withSentryFingerprint) Unwrap() error { return w.cause }
+
+func (w *withSentryFingerprint) SafeDetails() []string { return w.components }
+
+func (w *withSentryFingerprint) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withSentryFingerprint) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("sentry fingerprint: %s", redact.Safe(strings.Join(w.components, ", ")))
+	}
+	return w.cause
+}
+
+func decodeWithSentryFingerprint(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) == 0 {
+		// Some future version of the library is using a different
+		// encoding. Let DecodeError use the opaque type.
+		return nil
+	}
+	return &withSentryFingerprint{cause: cause, components: details}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withSentryFingerprint)(nil)), decodeWithSentryFingerprint)
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+func TestSentryFingerprint(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("hello")
+	_, ok := errutil.GetSentryFingerprint(origErr)
+	tt.Check(!ok)
+
+	// Without components, the error is unchanged.
+	tt.Check(errutil.WithSentryFingerprint(origErr) == origErr)
+	tt.Check(errutil.WithSentryFingerprint(nil, "woo") == nil)
+
+	// The outermost components win.
+	err := errutil.WithSentryFingerprint(origErr, "inner")
+	err = errutil.WithSentryFingerprint(errutil.Wrap(err, "outer"), "{{ default }}", "outer")
+	tt.CheckStringEqual(err.Error(), "outer: hello")
+	c, ok := errutil.GetSentryFingerprint(err)
+	tt.Check(ok)
+	tt.CheckDeepEqual(c, []string{"{{ default }}", "outer"})
+
+	// For Sentry events, the outermost of the components and the
+	// grouping key wins.
+	c, ok = errutil.GetSentryEventFingerprint(errutil.WithFingerprint(err, "mykey"))
+	tt.Check(ok)
+	tt.CheckDeepEqual(c, []string{"mykey"})
+	c, ok = errutil.GetSentryEventFingerprint(errutil.WithSentryFingerprint(errutil.WithFingerprint(origErr, "mykey"), "a"))
+	tt.Check(ok)
+	tt.CheckDeepEqual(c, []string{"a"})
+	_, ok = errutil.GetSentryEventFingerprint(origErr)
+	tt.Check(!ok)
+
+	// The components are safe details.
+	tt.CheckContains(fmt.Sprintf("%+v", err), "sentry fingerprint: {{ default }}, outer")
+	tt.CheckDeepEqual(errbase.GetSafeDetails(err).SafeDetails, []string{"{{ default }}", "outer"})
+
+	// The components survive a network transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+	c, ok = errutil.GetSentryFingerprint(newErr)
+	tt.Check(ok)
+	tt.CheckDeepEqual(c, []string{"{{ default }}", "outer"})
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithTenant annotates an error with the ID of the tenant it
+// pertains to, in multi-tenant systems. This lets error pipelines
+// filter and isolate errors per tenant.
+//
+// The ID must not contain PII: it is considered safe for reporting.
+//
+// If the annotation is applied multiple times, the innermost ID,
+// i.e. the one closest to the origin of the error, wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetTenant()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports, also as the "tenant" tag.
+func WithTenant(err error, tenantID string) error {
+	if err == nil {
+		return nil
+	}
+	return &withTenant{cause: err, tenantID: tenantID}
+}
+
+// GetTenant retrieves the innermost tenant ID in the error's causal
+// chain, or false if there is none.
+func GetTenant(err error) (tenantID string, ok bool) {
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		if w, isTenant := c.(*withTenant); isTenant {
+			tenantID, ok = w.tenantID, true
+		}
+	}
+	return tenantID, ok
+}
+
+type withTenant struct {
+	cause    error
+	tenantID string
+}
+
+var _ error = (*withTenant)(nil)
+var _ errbase.SafeDetailer = (*withTenant)(nil)
+var _ fmt.Formatter = (*withTenant)(nil)
+var _ errbase.SafeFormatter = (*withTenant)(nil)
+
+func (w *withTenant) Error() string { return w.cause.Error() }
+func (w *withTenant) Cause() error  { return w.cause }
+func (w *withTenant) Unwrap() error { return w.cause }
+
+func (w *withTenant) SafeDetails() []string { return []string{w.tenantID} }
+
+func (w *withTenant) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withTenant) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("tenant: %s", redact.Safe(w.tenantID))
+	}
+	return w.cause
+}
+
+func decodeWithTenant(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) < 1 {
+		// Some future version of the library is using a different
+		// encoding. Let DecodeError use the opaque type.
+		return nil
+	}
+	return &withTenant{cause: cause, tenantID: details[0]}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withTenant)(nil)), decodeWithTenant)
+}
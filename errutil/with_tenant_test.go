@@ -0,0 +1,55 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+func TestTenant(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("hello")
+	_, ok := errutil.GetTenant(origErr)
+	tt.Check(!ok)
+	tt.Check(errutil.WithTenant(nil, "t1") == nil)
+
+	// The innermost tenant wins.
+	err := errutil.WithTenant(origErr, "inner")
+	err = errutil.WithTenant(errutil.Wrap(err, "woo"), "outer")
+	tt.CheckStringEqual(err.Error(), "woo: hello")
+	tenant, ok := errutil.GetTenant(err)
+	tt.Check(ok)
+	tt.CheckStringEqual(tenant, "inner")
+
+	// The tenant is a safe detail.
+	tt.CheckContains(fmt.Sprintf("%+v", err), "tenant: inner")
+	tt.CheckDeepEqual(errbase.GetSafeDetails(err).SafeDetails, []string{"outer"})
+
+	// The tenant survives a network transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+	tenant, ok = errutil.GetTenant(newErr)
+	tt.Check(ok)
+	tt.CheckStringEqual(tenant, "inner")
+	tt.CheckContains(fmt.Sprintf("%+v", newErr), "tenant: outer")
+}
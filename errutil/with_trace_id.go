@@ -0,0 +1,98 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithTraceID annotates an error with the identifiers of the
+// distributed tracing trace and span where it occurred. This lets
+// logs and reports link to the trace.
+//
+// The identifiers must not contain PII: they are considered safe for
+// reporting.
+//
+// If the annotation is applied multiple times, the innermost
+// identifiers, i.e. those closest to the origin of the error, win.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetTraceIDs()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithTraceID(err error, traceID, spanID string) error {
+	if err == nil {
+		return nil
+	}
+	return &withTraceID{cause: err, traceID: traceID, spanID: spanID}
+}
+
+// GetTraceIDs retrieves the innermost trace and span identifiers in
+// the error's causal chain, or false if there are none.
+func GetTraceIDs(err error) (traceID, spanID string, ok bool) {
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		if w, isTrace := c.(*withTraceID); isTrace {
+			traceID, spanID, ok = w.traceID, w.spanID, true
+		}
+	}
+	return traceID, spanID, ok
+}
+
+type withTraceID struct {
+	cause   error
+	traceID string
+	spanID  string
+}
+
+var _ error = (*withTraceID)(nil)
+var _ errbase.SafeDetailer = (*withTraceID)(nil)
+var _ fmt.Formatter = (*withTraceID)(nil)
+var _ errbase.SafeFormatter = (*withTraceID)(nil)
+
+func (w *withTraceID) Error() string { return w.cause.Error() }
+func (w *withTraceID) Cause() error  { return w.cause }
+func (w *withTraceID) Unwrap() error { return w.cause }
+
+func (w *withTraceID) SafeDetails() []string { return []string{w.traceID, w.spanID} }
+
+func (w *withTraceID) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withTraceID) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("trace: %s span: %s", redact.Safe(w.traceID), redact.Safe(w.spanID))
+	}
+	return w.cause
+}
+
+func decodeWithTraceID(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) < 2 {
+		// Some future version of the library is using a different
+		// encoding. Let DecodeError use the opaque type.
+		return nil
+	}
+	return &withTraceID{cause: cause, traceID: details[0], spanID: details[1]}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withTraceID)(nil)), decodeWithTraceID)
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+)
+
+func TestWithTraceID(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("woo")
+	_, _, ok := errutil.GetTraceIDs(origErr)
+	tt.Check(!ok)
+	tt.Check(errutil.WithTraceID(nil, "abc", "def") == nil)
+
+	err := errutil.WithTraceID(origErr, "4bf92f35", "00f067aa")
+	// The innermost identifiers win.
+	err = errutil.WithTraceID(errutil.WithMessage(err, "waa"), "a3ce929d", "b7ad6b71")
+
+	traceID, spanID, ok := errutil.GetTraceIDs(err)
+	tt.Check(ok)
+	tt.CheckStringEqual(traceID, "4bf92f35")
+	tt.CheckStringEqual(spanID, "00f067aa")
+
+	tt.CheckStringEqual(err.Error(), "waa: woo")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", err), `waa: woo
+(1) trace: a3ce929d span: b7ad6b71
+Wraps: (2) waa
+Wraps: (3) trace: 4bf92f35 span: 00f067aa
+Wraps: (4) woo
+Error types: (1) *errutil.withTraceID (2) *errutil.withPrefix (3) *errutil.withTraceID (4) *errors.errorString`)
+
+	// The identifiers are safe for reporting.
+	tt.CheckDeepEqual(errbase.GetSafeDetails(err).SafeDetails, []string{"a3ce929d", "b7ad6b71"})
+	tt.CheckStringEqual(string(redact.Sprintf("%+v", err).Redact()), `waa: ‹×›
+(1) trace: a3ce929d span: b7ad6b71
+Wraps: (2) waa
+Wraps: (3) trace: 4bf92f35 span: 00f067aa
+Wraps: (4) ‹×›
+Error types: (1) *errutil.withTraceID (2) *errutil.withPrefix (3) *errutil.withTraceID (4) *errors.errorString`)
+
+	// Simulate a network transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+
+	traceID, spanID, ok = errutil.GetTraceIDs(newErr)
+	tt.Check(ok)
+	tt.CheckStringEqual(traceID, "4bf92f35")
+	tt.CheckStringEqual(spanID, "00f067aa")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", newErr), fmt.Sprintf("%+v", err))
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WithTransient annotates an error as transient, i.e. likely to heal
+// by itself after some time, e.g. a connection reset during a
+// server restart. This is independent of whether the caller should
+// retry the operation.
+//
+// If the error is annotated multiple times, with WithTransient() or
+// WithNonTransient(), the outermost annotation wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `IsTransient()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithTransient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withTransient{cause: err, transient: true}
+}
+
+// WithNonTransient annotates an error as explicitly not transient.
+// This overrides a WithTransient() annotation on one of its causes.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `IsTransient()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithNonTransient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withTransient{cause: err, transient: false}
+}
+
+// IsTransient returns true iff the outermost annotation in the
+// error's causal chain, among those attached with WithTransient()
+// and WithNonTransient(), marks the error as transient.
+func IsTransient(err error) bool {
+	v, ok := markers.If(err, func(err error) (interface{}, bool) {
+		if w, ok := err.(*withTransient); ok {
+			return w.transient, true
+		}
+		return nil, false
+	})
+	return ok && v.(bool)
+}
+
+type withTransient struct {
+	cause     error
+	transient bool
+}
+
+var _ error = (*withTransient)(nil)
+var _ errbase.SafeDetailer = (*withTransient)(nil)
+var _ fmt.Formatter = (*withTransient)(nil)
+var _ errbase.SafeFormatter = (*withTransient)(nil)
+
+func (w *withTransient) Error() string { return w.cause.Error() }
+func (w *withTransient) Cause() error  { return w.cause }
+func (w *withTransient) Unwrap() error { return w.cause }
+
+func (w *withTransient) SafeDetails() []string { return []string{w.label()} }
+
+func (w *withTransient) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withTransient) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("(%s)", redact.Safe(w.label()))
+	}
+	return w.cause
+}
+
+const (
+	transientLabel    = "transient"
+	nonTransientLabel = "non-transient"
+)
+
+func (w *withTransient) label() string {
+	if w.transient {
+		return transientLabel
+	}
+	return nonTransientLabel
+}
+
+func decodeWithTransient(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) == 0 {
+		return nil
+	}
+	switch details[0] {
+	case transientLabel:
+		return &withTransient{cause: cause, transient: true}
+	case nonTransientLabel:
+		return &withTransient{cause: cause, transient: false}
+	}
+	// Some future version of the library is using a different
+	// encoding. Let DecodeError use the opaque type.
+	return nil
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withTransient)(nil)), decodeWithTransient)
+}
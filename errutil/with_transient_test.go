@@ -0,0 +1,66 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+)
+
+func TestWithTransient(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := goErr.New("woo")
+	tt.Check(!errutil.IsTransient(origErr))
+	tt.Check(!errutil.IsTransient(nil))
+	tt.Check(errutil.WithTransient(nil) == nil)
+	tt.Check(errutil.WithNonTransient(nil) == nil)
+
+	err := errutil.WithTransient(origErr)
+	tt.Check(errutil.IsTransient(err))
+	tt.Check(errutil.IsTransient(errutil.WithMessage(err, "waa")))
+
+	// The outermost annotation wins.
+	err = errutil.WithNonTransient(errutil.WithMessage(err, "waa"))
+	tt.Check(!errutil.IsTransient(err))
+	tt.Check(errutil.IsTransient(errutil.WithTransient(err)))
+
+	tt.CheckStringEqual(err.Error(), "waa: woo")
+	tt.CheckStringEqual(fmt.Sprintf("%+v", err), `waa: woo
+(1) (non-transient)
+Wraps: (2) waa
+Wraps: (3) (transient)
+Wraps: (4) woo
+Error types: (1) *errutil.withTransient (2) *errutil.withPrefix (3) *errutil.withTransient (4) *errors.errorString`)
+
+	// The annotation is a safe detail.
+	tt.CheckDeepEqual(errbase.GetSafeDetails(err).SafeDetails, []string{"non-transient"})
+	tt.CheckContains(string(redact.Sprintf("%+v", err).Redact()), "(3) (transient)")
+
+	// Simulate a network transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+
+	tt.Check(!errutil.IsTransient(newErr))
+	tt.Check(errutil.IsTransient(errbase.UnwrapOnce(errbase.UnwrapOnce(newErr))))
+	tt.CheckStringEqual(fmt.Sprintf("%+v", newErr), fmt.Sprintf("%+v", err))
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WrapHere wraps an error with a prefix indicating the source
+// location of the caller, in the form "file:line", where the file
+// name is simplified to remove the path prefix:
+//
+//	foo.go:42: <cause>
+//
+// Unlike Wrap(), the location is captured cheaply without a full
+// stack trace. This is meant for quick debugging.
+//
+// The location is considered safe for reporting.
+//
+// Detail is shown:
+// - in the error message.
+// - via `errors.GetSafeDetails()`.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WrapHere(err error) error {
+	return WrapHereWithDepth(1, err)
+}
+
+// WrapHereWithDepth is like WrapHere except the depth to capture the
+// source location is specified.
+func WrapHereWithDepth(depth int, err error) error {
+	if err == nil {
+		return nil
+	}
+	_, file, line, ok := runtime.Caller(1 + depth)
+	if !ok {
+		return err
+	}
+	return &withSourceLocation{cause: err, loc: fmt.Sprintf("%s:%d", filepath.Base(file), line)}
+}
+
+type withSourceLocation struct {
+	cause error
+	loc   string
+}
+
+var _ error = (*withSourceLocation)(nil)
+var _ errbase.SafeDetailer = (*withSourceLocation)(nil)
+var _ fmt.Formatter = (*withSourceLocation)(nil)
+var _ errbase.SafeFormatter = (*withSourceLocation)(nil)
+
+func (w *withSourceLocation) Error() string { return fmt.Sprintf("%s: %v", w.loc, w.cause) }
+func (w *withSourceLocation) Cause() error  { return w.cause }
+func (w *withSourceLocation) Unwrap() error { return w.cause }
+
+func (w *withSourceLocation) SafeDetails() []string { return []string{w.loc} }
+
+func (w *withSourceLocation) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withSourceLocation) SafeFormatError(p errbase.Printer) error {
+	p.Print(redact.Safe(w.loc))
+	return w.cause
+}
+
+func decodeWithSourceLocation(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) < 1 {
+		// Some future version of the library is using a different
+		// encoding. Let DecodeError use the opaque type.
+		return nil
+	}
+	return &withSourceLocation{cause: cause, loc: details[0]}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withSourceLocation)(nil)), decodeWithSourceLocation)
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+)
+
+func TestWrapHere(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.Check(errutil.WrapHere(nil) == nil)
+
+	origErr := goErr.New("woo")
+	_, _, line, _ := runtime.Caller(0)
+	err := errutil.WrapHere(origErr)
+	loc := fmt.Sprintf("wrap_here_test.go:%d", line+1)
+
+	tt.CheckStringEqual(err.Error(), loc+": woo")
+	tt.Check(goErr.Is(err, origErr))
+	tt.CheckStringEqual(fmt.Sprintf("%+v", err), loc+`: woo
+(1) `+loc+`
+Wraps: (2) woo
+Error types: (1) *errutil.withSourceLocation (2) *errors.errorString`)
+
+	// The location is safe for reporting.
+	tt.CheckDeepEqual(errbase.GetSafeDetails(err).SafeDetails, []string{loc})
+	tt.CheckStringEqual(string(redact.Sprint(err).Redact()), loc+": ‹×›")
+
+	// Simulate a network transfer.
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+	tt.CheckStringEqual(newErr.Error(), err.Error())
+	tt.CheckStringEqual(fmt.Sprintf("%+v", newErr), fmt.Sprintf("%+v", err))
+}
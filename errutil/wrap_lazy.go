@@ -0,0 +1,108 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/withstack"
+	"github.com/gogo/protobuf/proto"
+)
+
+// WrapLazy is like Wrap, except that the message prefix is computed
+// by calling fn, only when the message of the error is needed: by
+// Error(), when formatting the error or when encoding it. The result
+// of fn is cached, so that fn is called at most once. This is meant
+// for hot paths where the wrapped error is usually discarded, e.g.
+// before a quick retry. A stack trace is also retained.
+//
+// The prefix returned by fn is considered to contain PII and is
+// redacted in Sentry reports.
+//
+// Detail output:
+// - original error message + prefix via `Error()` and formatting using `%v`/`%s`/`%q`.
+// - everything when formatting with `%+v`.
+// - stack trace via `errors.GetSafeDetails()`.
+// - stack trace and redacted message in Sentry reports.
+func WrapLazy(err error, fn func() string) error {
+	return WrapLazyWithDepth(1, err, fn)
+}
+
+// WrapLazyWithDepth is like WrapLazy except the depth to capture the
+// stack trace is configurable.
+// The the doc of `WrapLazy()` for more details.
+func WrapLazyWithDepth(depth int, err error, fn func() string) error {
+	if err == nil {
+		return nil
+	}
+	err = &withLazyPrefix{cause: err, fn: fn}
+	return withstack.WithStackDepth(err, depth+1)
+}
+
+type withLazyPrefix struct {
+	cause error
+
+	once   sync.Once
+	fn     func() string
+	prefix string
+}
+
+var _ error = (*withLazyPrefix)(nil)
+var _ fmt.Formatter = (*withLazyPrefix)(nil)
+var _ errbase.SafeFormatter = (*withLazyPrefix)(nil)
+
+// getPrefix computes the prefix on first use.
+func (w *withLazyPrefix) getPrefix() string {
+	w.once.Do(func() {
+		if w.fn != nil {
+			w.prefix = w.fn()
+			w.fn = nil
+		}
+	})
+	return w.prefix
+}
+
+func (w *withLazyPrefix) Error() string {
+	prefix := w.getPrefix()
+	if prefix == "" {
+		return w.cause.Error()
+	}
+	return fmt.Sprintf("%s: %v", prefix, w.cause)
+}
+
+func (w *withLazyPrefix) Cause() error  { return w.cause }
+func (w *withLazyPrefix) Unwrap() error { return w.cause }
+
+func (w *withLazyPrefix) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withLazyPrefix) SafeFormatError(p errbase.Printer) error {
+	p.Print(w.getPrefix())
+	return w.cause
+}
+
+func decodeWithLazyPrefix(
+	_ context.Context, cause error, prefix string, _ []string, _ proto.Message,
+) error {
+	w := &withLazyPrefix{cause: cause, prefix: prefix}
+	w.once.Do(func() {})
+	return w
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withLazyPrefix)(nil)), decodeWithLazyPrefix)
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errutil_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+)
+
+func TestWrapLazy(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	calls := 0
+	fn := func() string {
+		calls++
+		return fmt.Sprintf("attempt %d", 3)
+	}
+
+	tt.Check(errutil.WrapLazy(nil, fn) == nil)
+	tt.CheckEqual(calls, 0)
+
+	origErr := goErr.New("woo")
+	err := errutil.WrapLazy(origErr, fn)
+
+	// The prefix is not computed until the message is needed.
+	tt.Check(markers.Is(err, origErr))
+	tt.CheckEqual(calls, 0)
+	tt.CheckStringEqual(err.Error(), "attempt 3: woo")
+	tt.CheckEqual(calls, 1)
+
+	// Once computed, the prefix is cached.
+	tt.CheckStringEqual(fmt.Sprintf("%v", err), "attempt 3: woo")
+	tt.CheckContains(fmt.Sprintf("%+v", err), "\nWraps: (2) attempt 3\nWraps: (3) woo\n")
+	tt.CheckEqual(calls, 1)
+
+	// A stack trace is retained, and the prefix is considered unsafe.
+	tt.CheckContains(fmt.Sprintf("%+v", err), "wrap_lazy_test.go")
+	tt.CheckStringEqual(string(redact.Sprint(err).Redact()), "‹×›: ‹×›")
+
+	// Simulate a network transfer.
+	calls = 0
+	err = errutil.WrapLazy(origErr, fn)
+	enc := errbase.EncodeError(context.Background(), err)
+	tt.CheckEqual(calls, 1)
+	newErr := errbase.DecodeError(context.Background(), enc)
+	tt.CheckStringEqual(newErr.Error(), "attempt 3: woo")
+	tt.Check(markers.Is(newErr, err))
+
+	// An empty prefix leaves the message unchanged.
+	err = errutil.WrapLazy(origErr, func() string { return "" })
+	tt.CheckStringEqual(err.Error(), "woo")
+}
@@ -15,6 +15,9 @@
 package errors
 
 import (
+	"context"
+	"time"
+
 	"github.com/cockroachdb/errors/barriers"
 	"github.com/cockroachdb/errors/errbase"
 	"github.com/cockroachdb/errors/errutil"
@@ -58,6 +61,42 @@ func NewWithDepthf(depth int, format string, args ...interface{}) error {
 	return errutil.NewWithDepthf(depth+1, format, args...)
 }
 
+// TemplatedArg is an argument of an error constructed with
+// NewfStructured(), as returned by GetMessageTemplate().
+type TemplatedArg = errutil.TemplatedArg
+
+// NewfStructured is like Newf(), but also remembers the format string
+// and the arguments of the message. These can be retrieved separately
+// with GetMessageTemplate(), e.g. to aggregate log entries by message
+// template instead of rendered message. Error() renders the message
+// like Newf().
+//
+// Note: the format string is assumed to not contain
+// PII and is included in Sentry reports.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`, with the unsafe arguments redacted.
+// - via `GetMessageTemplate()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func NewfStructured(format string, args ...interface{}) error {
+	return errutil.NewStructuredWithDepthf(1, format, args...)
+}
+
+// NewStructuredWithDepthf is like NewfStructured() except the depth
+// to capture the stack trace is configurable.
+// See the doc of `NewfStructured()` for more details.
+func NewStructuredWithDepthf(depth int, format string, args ...interface{}) error {
+	return errutil.NewStructuredWithDepthf(depth+1, format, args...)
+}
+
+// GetMessageTemplate retrieves the format string and the arguments of
+// the outermost error constructed with NewfStructured() in the
+// error's causal chain, or false if there is none.
+func GetMessageTemplate(err error) (format string, args []TemplatedArg, ok bool) {
+	return errutil.GetMessageTemplate(err)
+}
+
 // Errorf aliases Newf().
 func Errorf(format string, args ...interface{}) error {
 	return errutil.NewWithDepthf(1, format, args...)
@@ -91,6 +130,19 @@ func WithMessagef(err error, format string, args ...interface{}) error {
 	return errutil.WithMessagef(err, format, args...)
 }
 
+// WithOnlyPrefixMessage annotates err with a message which replaces
+// the error's message entirely: the Error() string of the result is
+// just the given message, without the message of the cause appended.
+// However, the cause remains visible to Is() and the full chain is
+// still displayed when formatting with %+v.
+//
+// If err is nil, WithOnlyPrefixMessage returns nil.
+// The message is considered safe for reporting
+// and is included in Sentry reports.
+func WithOnlyPrefixMessage(err error, msg string) error {
+	return errutil.WithOnlyPrefixMessage(err, msg)
+}
+
 // Wrap wraps an error with a message prefix.
 // A stack trace is retained.
 //
@@ -164,6 +216,31 @@ func NewAssertionErrorWithWrappedErrf(origErr error, format string, args ...inte
 	return errutil.NewAssertionErrorWithWrappedErrDepthf(1, origErr, format, args...)
 }
 
+// Assertf returns nil if cond is true, and otherwise an assertion
+// failure as per AssertionFailedf(). This is a shorthand for:
+//
+//	if !cond {
+//	   return AssertionFailedf(format, args...)
+//	}
+//
+// The format string should describe the condition that was asserted.
+// As with AssertionFailedf, it is considered safe for reporting.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`, shows redacted strings.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func Assertf(cond bool, format string, args ...interface{}) error {
+	return errutil.AssertWithDepthf(1, cond, format, args...)
+}
+
+// AssertWithDepthf is like Assertf except the depth to capture the
+// stack trace is configurable.
+// See the doc of `Assertf()` for more details.
+func AssertWithDepthf(depth int, cond bool, format string, args ...interface{}) error {
+	return errutil.AssertWithDepthf(depth+1, cond, format, args...)
+}
+
 // HandleAsAssertionFailure hides an error and turns it into
 // an assertion failure. Both details from the original error and the
 // context of the caller are preserved. The original error is not
@@ -193,6 +270,26 @@ func HandleAsAssertionFailureDepth(depth int, origErr error) error {
 // - if it detects an API use error, its panic object is a valid error.
 func As(err error, target interface{}) bool { return errutil.As(err, target) }
 
+// FindType finds the first error in err's tree whose type is
+// assignable to T, and returns it. The tree is searched depth-first
+// from the outermost layer, following the causes of wrappers and all
+// the branches of multi-errors, in the order they are returned by
+// UnwrapMulti.
+//
+// Secondary errors, e.g. those attached with WithSecondaryError(),
+// are not searched. Use FindTypeIncludingSecondaries() for this.
+func FindType[T any](err error) (T, bool) { return errutil.FindType[T](err) }
+
+// FindTypeIncludingSecondaries is like FindType, but also searches
+// the secondary errors attached to the layers of the tree, as
+// returned by GetSecondaryErrors(). The secondary errors are only
+// searched after the entire primary tree, in the order in which they
+// are encountered, and recursively including their own secondary
+// errors.
+func FindTypeIncludingSecondaries[T any](err error) (T, bool) {
+	return errutil.FindTypeIncludingSecondaries[T](err)
+}
+
 // Join returns an error that wraps the given errors.
 // Any nil error values are discarded.
 // Join returns nil if errs contains no non-nil values.
@@ -208,3 +305,685 @@ func Join(errs ...error) error {
 func JoinWithDepth(depth int, errs ...error) error {
 	return errutil.JoinWithDepth(depth+1, errs...)
 }
+
+// WithDuration annotates an error with the amount of time an
+// operation ran before it failed. This is useful for latency
+// attribution.
+//
+// If the annotation is applied multiple times, the outermost
+// duration wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetDuration()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithDuration(err error, d time.Duration) error { return errutil.WithDuration(err, d) }
+
+// GetDuration retrieves the outermost duration annotation
+// in the error's causal chain, or false if there is none.
+func GetDuration(err error) (time.Duration, bool) { return errutil.GetDuration(err) }
+
+// WithRetryAfter annotates an error with the amount of time the
+// caller should wait before retrying the operation, e.g. to signal
+// rate limiting or backoff. A gRPC or HTTP boundary can translate
+// this to the corresponding standard metadata or header.
+//
+// If the annotation is applied multiple times, the outermost
+// duration wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetRetryAfter()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithRetryAfter(err error, d time.Duration) error { return errutil.WithRetryAfter(err, d) }
+
+// GetRetryAfter retrieves the outermost retry-after annotation
+// in the error's causal chain, or false if there is none.
+func GetRetryAfter(err error) (time.Duration, bool) { return errutil.GetRetryAfter(err) }
+
+// WithTransient annotates an error as transient, i.e. likely to heal
+// by itself after some time, e.g. a connection reset during a
+// server restart. This is independent of whether the caller should
+// retry the operation.
+//
+// If the error is annotated multiple times, with WithTransient() or
+// WithNonTransient(), the outermost annotation wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `IsTransient()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithTransient(err error) error { return errutil.WithTransient(err) }
+
+// WithNonTransient annotates an error as explicitly not transient.
+// This overrides a WithTransient() annotation on one of its causes.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `IsTransient()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithNonTransient(err error) error { return errutil.WithNonTransient(err) }
+
+// IsTransient returns true iff the outermost annotation in the
+// error's causal chain, among those attached with WithTransient()
+// and WithNonTransient(), marks the error as transient.
+func IsTransient(err error) bool { return errutil.IsTransient(err) }
+
+// WithSecuritySensitive flags an error as security sensitive, i.e.
+// its unsafe parts must never appear unredacted in any output. The
+// unsafe parts of the flagged error are redacted:
+//
+//   - by the Error() method of the flag;
+//   - when formatting with fmt, including %v and %+v, like
+//     redact.Sprint() followed by Redact();
+//   - in reports, as usual.
+//
+// The layers wrapped around the flag later are not redacted, so that
+// the Error() method of every layer remains consistent with its
+// rendering with %v. The error marks used by Is() are
+// computed from the unredacted message, so that errors that only
+// differ in their unsafe parts are not equivalent. The flag is
+// preserved across the network.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `IsSecuritySensitive()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithSecuritySensitive(err error) error { return errutil.WithSecuritySensitive(err) }
+
+// IsSecuritySensitive returns true iff the error was flagged with
+// WithSecuritySensitive(), anywhere in its causal chain or the causes
+// of its multi-errors.
+func IsSecuritySensitive(err error) bool { return errutil.IsSecuritySensitive(err) }
+
+// WithAutoFixable annotates an error as one that automated
+// remediation is allowed to act on, e.g. by restarting a component
+// or repairing a file. This is independent of whether the error is
+// transient or whether the caller should retry the operation.
+//
+// If the error is annotated multiple times, with WithAutoFixable()
+// or WithNotAutoFixable(), the outermost annotation wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `IsAutoFixable()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithAutoFixable(err error) error { return errutil.WithAutoFixable(err) }
+
+// WithNotAutoFixable annotates an error as explicitly not eligible
+// for automated remediation. This overrides a WithAutoFixable()
+// annotation on one of its causes.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `IsAutoFixable()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithNotAutoFixable(err error) error { return errutil.WithNotAutoFixable(err) }
+
+// IsAutoFixable returns true iff the outermost annotation in the
+// error's causal chain, among those attached with WithAutoFixable()
+// and WithNotAutoFixable(), marks the error as auto-fixable.
+func IsAutoFixable(err error) bool { return errutil.IsAutoFixable(err) }
+
+// WithDeprecation annotates an error with a notice that the code path
+// where it occurred is deprecated and slated for removal, along with
+// the replacement to use instead. Unlike hints, which are general
+// advice for the user, this is meant to guide the migration off
+// legacy code paths.
+//
+// The replacement must not contain PII: it is considered safe for
+// reporting.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetDeprecations()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithDeprecation(err error, replacement string) error {
+	return errutil.WithDeprecation(err, replacement)
+}
+
+// GetDeprecations retrieves the replacements attached with
+// WithDeprecation() in the error's causal chain, using a post-order
+// traversal, i.e. the innermost first. The replacements are
+// de-duplicated.
+func GetDeprecations(err error) []string { return errutil.GetDeprecations(err) }
+
+// WithExitCode annotates an error with the exit code that a
+// command-line program should use when terminating due to this
+// error. This lets deep code decide the appropriate exit status.
+//
+// If the annotation is applied multiple times, the outermost
+// exit code wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetExitCode()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithExitCode(err error, code int) error { return errutil.WithExitCode(err, code) }
+
+// GetExitCode retrieves the outermost exit code annotation
+// in the error's causal chain, or false if there is none.
+func GetExitCode(err error) (int, bool) { return errutil.GetExitCode(err) }
+
+// WithAttempt annotates an error with the number of the attempt of
+// a retry loop that produced it. This helps correlate errors with
+// the retry behavior in reports.
+//
+// If the annotation is applied multiple times, the outermost
+// attempt number wins, i.e. that set by the outermost retry loop.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetAttempt()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithAttempt(err error, n int) error { return errutil.WithAttempt(err, n) }
+
+// GetAttempt retrieves the outermost attempt number annotation
+// in the error's causal chain, or false if there is none.
+func GetAttempt(err error) (int, bool) { return errutil.GetAttempt(err) }
+
+// WithLocalMetadata annotates an error with key/value metadata that
+// is only available in the current process. This is meant for
+// sensitive debugging context that may be logged locally but must
+// never leave the process.
+//
+// The metadata is considered unsafe: it is never included in safe
+// details, and is redacted in redactable output. It is also not
+// preserved by EncodeError/DecodeError: the annotation is omitted
+// from the encoded error.
+//
+// Detail is shown:
+// - via `GetLocalMetadata()` below.
+// - when formatting with `%+v` (redacted in Sentry reports).
+func WithLocalMetadata(err error, kv map[string]string) error {
+	return errutil.WithLocalMetadata(err, kv)
+}
+
+// GetLocalMetadata retrieves the metadata attached with
+// WithLocalMetadata() in the error's direct causal chain. If the
+// same key was attached multiple times, the outermost value wins.
+// The result is nil if there is no metadata.
+func GetLocalMetadata(err error) map[string]string { return errutil.GetLocalMetadata(err) }
+
+// WithLogged marks an error as already logged. This enables an outer
+// handler to check IsLogged() and avoid logging the same error twice
+// as it is propagated up the call stack.
+//
+// The mark is only available in the current process. It produces no
+// details and is not preserved by EncodeError/DecodeError: the
+// annotation is omitted from the encoded error.
+func WithLogged(err error) error { return errutil.WithLogged(err) }
+
+// IsLogged returns true iff the error was marked with WithLogged() in
+// the current process.
+func IsLogged(err error) bool { return errutil.IsLogged(err) }
+
+// FromPanicValue converts a value recovered from a panic into an
+// error. If the value is already an error, it is returned with a
+// stack trace attached. Otherwise, a new error is constructed with
+// message "panic: <value>", and the original value can be
+// retrieved with GetPanicValue(). If the value is nil, as returned
+// by recover() when there was no panic, the result is nil.
+//
+// The value itself is only available in the current process. Its Go
+// type is considered safe for reporting; its representation is
+// considered unsafe unless the value implements redact.SafeValue.
+//
+// Detail is shown:
+// - via `GetPanicValue()` below, in the current process only.
+// - via `errors.GetSafeDetails()`, with the value redacted.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func FromPanicValue(v interface{}) error { return errutil.FromPanicValueWithDepth(1, v) }
+
+// FromPanicValueWithDepth is like FromPanicValue() except the depth
+// to capture the stack trace is configurable.
+// See the doc of `FromPanicValue()` for more details.
+func FromPanicValueWithDepth(depth int, v interface{}) error {
+	return errutil.FromPanicValueWithDepth(depth+1, v)
+}
+
+// GetPanicValue retrieves the value passed to FromPanicValue() in
+// the error's causal chain. The result is false if there is no such
+// value, including when the error was decoded from the network: the
+// value is not preserved by EncodeError/DecodeError.
+func GetPanicValue(err error) (interface{}, bool) { return errutil.GetPanicValue(err) }
+
+// NormalizeOptions configures Normalize(). The zero value leaves
+// errors unchanged.
+type NormalizeOptions = errutil.NormalizeOptions
+
+// Normalize prepares an error to cross a service boundary, as
+// configured by opts. Each step is a no-op if the error already
+// satisfies it; in particular, Normalize returns err itself if there
+// is nothing to do.
+//
+// Removing annotations requires transforming the error through
+// EncodeError/DecodeError. As a result, when StripStacks is set,
+// the local-only annotations are also removed, and error types that
+// were not registered with the library become opaque, as if the
+// error had been received from the network. The message and the
+// details of the remaining layers are preserved.
+//
+// See also extgrpc.Normalize(), which also ensures that the error
+// carries a gRPC code.
+func Normalize(err error, opts NormalizeOptions) error { return errutil.Normalize(err, opts) }
+
+// WithFingerprint annotates an error with a key that overrides its
+// fingerprint, i.e. the key used to group similar errors together
+// in dashboards and Sentry reports. This is useful when errors with
+// the same structure have different meanings and must be grouped
+// separately.
+//
+// The key must not contain PII: it is considered safe for reporting.
+//
+// If the annotation is applied multiple times, the outermost key
+// wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `Fingerprint()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports, where it also overrides the event fingerprint.
+func WithFingerprint(err error, key string) error { return errutil.WithFingerprint(err, key) }
+
+// GetFingerprintOverride retrieves the outermost key attached with
+// WithFingerprint() in the error's causal chain, or false if there
+// is none.
+func GetFingerprintOverride(err error) (string, bool) { return errutil.GetFingerprintOverride(err) }
+
+// WithSentryFingerprint annotates an error with the components of
+// the fingerprint of the Sentry events reported for it, which
+// control how Sentry groups the events into issues. The special
+// component "{{ default }}" can be used to refine Sentry's default
+// grouping instead of replacing it.
+//
+// The components must not contain PII: they are considered safe for
+// reporting. If no component is given, the error is returned
+// unchanged.
+//
+// If the annotation is applied multiple times, the outermost
+// components win. In Sentry reports, the outermost of this annotation
+// and WithFingerprint() determines the event fingerprint.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetSentryFingerprint()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports, where it sets the event fingerprint.
+func WithSentryFingerprint(err error, components ...string) error {
+	return errutil.WithSentryFingerprint(err, components...)
+}
+
+// GetSentryFingerprint retrieves the outermost components attached
+// with WithSentryFingerprint() in the error's causal chain, or false
+// if there are none.
+func GetSentryFingerprint(err error) ([]string, bool) { return errutil.GetSentryFingerprint(err) }
+
+// GetSentryEventFingerprint retrieves the fingerprint of the Sentry
+// events reported for the error: the outermost of the components
+// attached with WithSentryFingerprint() and the key attached with
+// WithFingerprint() in the error's causal chain, or false if there
+// are none.
+func GetSentryEventFingerprint(err error) ([]string, bool) {
+	return errutil.GetSentryEventFingerprint(err)
+}
+
+// Fingerprint returns a key suitable to group similar errors. This
+// is the key attached with WithFingerprint(), if any. Otherwise, it
+// is derived from the structure of the error, i.e. the types of its
+// layers, and is independent of the error messages. The result is
+// stable across processes and across network transfers.
+func Fingerprint(err error) string { return errutil.Fingerprint(err) }
+
+// SetDebugMode configures the behavior of WithDebugDetail(). Debug
+// mode is disabled by default. This should be called during
+// initialization, e.g. in development builds.
+func SetDebugMode(enabled bool) { errutil.SetDebugMode(enabled) }
+
+// WithDebugDetail decorates an error with a textual detail computed
+// by fn, like WithDetail(), but only in debug mode (see
+// SetDebugMode()). When debug mode is disabled, the error is returned
+// unchanged and fn is not called. This makes it possible to include
+// expensive diagnostic strings in development without paying for
+// them in production.
+//
+// Detail is shown:
+// - when formatting with `%+v`.
+// - with `GetAllDetails()` / `FlattenDetails()`.
+func WithDebugDetail(err error, fn func() string) error { return errutil.WithDebugDetail(err, fn) }
+
+// Merge combines two errors that represent the same failure observed
+// in two places into a single error. The primary error determines the
+// message and the identity of the result for Is() and the other
+// cause analysis functions. The secondary error is attached like with
+// WithSecondaryError(): its stack traces and safe details are
+// included when formatting with %+v and in Sentry reports, but its
+// causes are hidden from cause analysis.
+//
+// In addition, the hints and details of the secondary error that are
+// not already present in the primary error are folded into the
+// result, so that GetAllHints() and GetAllDetails() report those of
+// the primary error first, followed by those of the secondary error.
+//
+// The codes of the secondary error, as reported by GetAllCodes(), are
+// also folded into the result for each kind of code that the primary
+// error does not carry: the primary error wins on conflicts. This
+// applies to the exit codes and to the kinds registered with
+// RegisterCodeWrapper(), e.g. the gRPC and HTTP codes of the extgrpc
+// and exthttp packages.
+//
+// For all other annotations retrieved via the causal chain, e.g. the
+// domain or the telemetry keys, only those of the primary error are
+// visible.
+//
+// If either error is nil, the other is returned as-is.
+func Merge(primary, secondaryErr error) error { return errutil.Merge(primary, secondaryErr) }
+
+// WithComponent annotates an error with the name of the component
+// or service that produced it. This is a finer-grained origin label
+// than error domains, useful to trace errors across services.
+//
+// The component name must not contain PII: it is considered safe for
+// reporting.
+//
+// If the annotation is applied multiple times, the innermost
+// component, i.e. the one closest to the origin of the error, wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetOriginComponent()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithComponent(err error, name string) error { return errutil.WithComponent(err, name) }
+
+// GetOriginComponent retrieves the innermost component annotation in
+// the error's causal chain, or false if there is none.
+func GetOriginComponent(err error) (string, bool) { return errutil.GetOriginComponent(err) }
+
+// OriginatedIn returns true if the innermost domain annotation or
+// the innermost component annotation in the error's causal chain
+// matches the given name, regardless of the layers that were added
+// afterwards. The name matches a domain if it is either the domain
+// itself or the argument to NamedDomain() that produced it.
+func OriginatedIn(err error, domainOrComponent string) bool {
+	return errutil.OriginatedIn(err, domainOrComponent)
+}
+
+// WithTraceID annotates an error with the identifiers of the
+// distributed tracing trace and span where it occurred. This lets
+// logs and reports link to the trace.
+//
+// The identifiers must not contain PII: they are considered safe for
+// reporting.
+//
+// If the annotation is applied multiple times, the innermost
+// identifiers, i.e. those closest to the origin of the error, win.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetTraceIDs()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithTraceID(err error, traceID, spanID string) error {
+	return errutil.WithTraceID(err, traceID, spanID)
+}
+
+// GetTraceIDs retrieves the innermost trace and span identifiers in
+// the error's causal chain, or false if there are none.
+func GetTraceIDs(err error) (traceID, spanID string, ok bool) { return errutil.GetTraceIDs(err) }
+
+// WithTenant annotates an error with the ID of the tenant it
+// pertains to, in multi-tenant systems. This lets error pipelines
+// filter and isolate errors per tenant.
+//
+// The ID must not contain PII: it is considered safe for reporting.
+//
+// If the annotation is applied multiple times, the innermost ID,
+// i.e. the one closest to the origin of the error, wins.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - via `GetTenant()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports, also as the "tenant" tag.
+func WithTenant(err error, tenantID string) error { return errutil.WithTenant(err, tenantID) }
+
+// GetTenant retrieves the innermost tenant ID in the error's causal
+// chain, or false if there is none.
+func GetTenant(err error) (tenantID string, ok bool) { return errutil.GetTenant(err) }
+
+// WrapHere wraps an error with a prefix indicating the source
+// location of the caller, in the form "file:line", where the file
+// name is simplified to remove the path prefix:
+//
+//	foo.go:42: <cause>
+//
+// Unlike Wrap(), the location is captured cheaply without a full
+// stack trace. This is meant for quick debugging.
+//
+// The location is considered safe for reporting.
+//
+// Detail is shown:
+// - in the error message.
+// - via `errors.GetSafeDetails()`.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WrapHere(err error) error { return errutil.WrapHereWithDepth(1, err) }
+
+// WrapHereWithDepth is like WrapHere except the depth to capture the
+// source location is specified.
+func WrapHereWithDepth(depth int, err error) error {
+	return errutil.WrapHereWithDepth(depth+1, err)
+}
+
+// Reraise annotates an error with a stack trace captured at the
+// point of the call, and a marker indicating that the error was
+// re-entered into a new call path there. This is useful when an
+// error is caught far from its origin, e.g. after being passed
+// through a channel, and raised again.
+//
+// The original error, including its own stack traces, is preserved
+// and Is() still recognizes it.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`.
+// - when formatting with `%+v`, with the new stack trace.
+// - in Sentry reports.
+func Reraise(err error) error { return errutil.ReraiseWithDepth(1, err) }
+
+// ReraiseWithDepth is like Reraise except the depth to capture the
+// stack trace is specified.
+func ReraiseWithDepth(depth int, err error) error {
+	return errutil.ReraiseWithDepth(depth+1, err)
+}
+
+// WrapLazy is like Wrap, except that the message prefix is computed
+// by calling fn, only when the message of the error is needed: by
+// Error(), when formatting the error or when encoding it. The result
+// of fn is cached, so that fn is called at most once. This is meant
+// for hot paths where the wrapped error is usually discarded, e.g.
+// before a quick retry. A stack trace is also retained.
+//
+// The prefix returned by fn is considered to contain PII and is
+// redacted in Sentry reports.
+//
+// Detail output:
+// - original error message + prefix via `Error()` and formatting using `%v`/`%s`/`%q`.
+// - everything when formatting with `%+v`.
+// - stack trace via `errors.GetSafeDetails()`.
+// - stack trace and redacted message in Sentry reports.
+func WrapLazy(err error, fn func() string) error { return errutil.WrapLazyWithDepth(1, err, fn) }
+
+// WrapLazyWithDepth is like WrapLazy except the depth to capture the
+// stack trace is configurable.
+// The the doc of `WrapLazy()` for more details.
+func WrapLazyWithDepth(depth int, err error, fn func() string) error {
+	return errutil.WrapLazyWithDepth(depth+1, err, fn)
+}
+
+// ErrorDiagnostics aggregates the annotations attached to an error.
+// It is populated by Diagnostics().
+type ErrorDiagnostics = errutil.ErrorDiagnostics
+
+// Diagnostics collects all the hints, details, issue links,
+// telemetry keys, domains, codes and stack traces attached to an
+// error into a single ErrorDiagnostics value. This is a convenience
+// for tools that need to dump everything known about an error,
+// and avoids calling each of the accessors separately.
+//
+// The error is traversed once. Unlike the individual accessors, the
+// branches of multi-cause errors are inspected too, after the layers
+// above them. The fields that hold a single value, like Domain and
+// ExitCode, keep the outermost value found.
+//
+// The zero value is returned for a nil error.
+func Diagnostics(err error) ErrorDiagnostics { return errutil.Diagnostics(err) }
+
+// IsNotFound returns true iff the error indicates that some
+// requested entity does not exist. This recognizes:
+//
+//   - errors equivalent to fs.ErrNotExist (and thus os.ErrNotExist),
+//     including after transfer through the network;
+//   - errors recognized by a predicate registered with
+//     RegisterNotFoundPredicate(). For example, the extgrpc and exthttp
+//     packages register predicates for the gRPC NotFound code and the
+//     HTTP 404 code, respectively.
+func IsNotFound(err error) bool { return errutil.IsNotFound(err) }
+
+// RegisterNotFoundPredicate registers a function that is used by
+// IsNotFound() to recognize additional "not found" errors. The
+// predicate receives the error passed to IsNotFound() and is
+// responsible for inspecting its causes.
+//
+// This is meant to be called from an init() function.
+func RegisterNotFoundPredicate(pred func(err error) bool) {
+	errutil.RegisterNotFoundPredicate(pred)
+}
+
+// UserFacingMessager can be implemented by errors that carry an
+// explicit message intended for the end user of an API. It is
+// used by ClientMessage().
+type UserFacingMessager = errutil.UserFacingMessager
+
+// ClientMessage computes the message to present to the client of an
+// API for the given error. It uses, in order of precedence:
+//
+//   - the outermost explicit user-facing message in the causal chain,
+//     i.e. an error that implements UserFacingMessager;
+//   - the safe part of the message of the leaf error, with the
+//     unsafe parts redacted, if there is any safe part;
+//   - the generic message provided by the caller.
+//
+// The empty string is returned for a nil error.
+func ClientMessage(err error, generic string) string { return errutil.ClientMessage(err, generic) }
+
+// PublicError constructs an error suitable for the boundary of an
+// API: its message is publicMsg, and it is a barrier, so that the
+// internal error is hidden from Is() and the message of the internal
+// error is not visible to the client. The internal error remains
+// available in the details, for troubleshooting.
+//
+// The public message is also marked as intended for the end user,
+// so that ClientMessage() returns it. It is considered safe for
+// reporting.
+//
+// Detail is shown:
+// - via `ClientMessage()`.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func PublicError(internal error, publicMsg string) error {
+	return errutil.PublicError(internal, publicMsg)
+}
+
+// GetAllCodes collects the code-style annotations attached to an
+// error, keyed by their kind, with the value that wins for each kind
+// as per the corresponding accessor. This is meant for structured
+// loggers, to emit all the classification fields in one call.
+//
+// The exit code attached with WithExitCode() is reported under the
+// kind "exit". Other kinds are provided by the packages that define
+// them using RegisterCodeExtractor(). For example, the extgrpc,
+// exthttp, errclass and errreason packages provide the kinds "grpc",
+// "http", "category" and "reason", respectively.
+//
+// A nil map is returned for a nil error.
+func GetAllCodes(err error) map[string]string { return errutil.GetAllCodes(err) }
+
+// RegisterCodeExtractor registers a function that is used by
+// GetAllCodes() to report the code of the given kind. The function
+// receives the error passed to GetAllCodes() and is responsible for
+// inspecting its causes. It returns false if the error carries no
+// code of that kind. A previous registration for the same kind is
+// replaced.
+//
+// This is meant to be called from an init() function.
+func RegisterCodeExtractor(kind string, fn func(err error) (string, bool)) {
+	errutil.RegisterCodeExtractor(kind, fn)
+}
+
+// RegisterCodeWrapper registers a function that is used by Merge() to
+// copy the code of the given kind from one error to another. The
+// function receives the error to annotate and the error carrying the
+// code, as reported by the function registered for the same kind
+// with RegisterCodeExtractor(), and returns the annotated error. A
+// previous registration for the same kind is replaced.
+//
+// This is meant to be called from an init() function.
+func RegisterCodeWrapper(kind string, fn func(err, from error) error) {
+	errutil.RegisterCodeWrapper(kind, fn)
+}
+
+// LogField is a key/value pair produced by LogFields().
+type LogField = errutil.LogField
+
+// LogFields converts an error into a flat list of fields suitable
+// for structured loggers. The following fields are produced, in
+// this order:
+//
+//   - "error": the message of the error, with the unsafe parts
+//     redacted.
+//   - "error.type": the family name of the outermost layer, as per
+//     errbase.GetTypeMark().
+//   - "error.<kind>_code": one field per code reported by
+//     GetAllCodes(), e.g. "error.grpc_code", in alphabetical order
+//     of kind.
+//   - "error.stack": the innermost stack trace, i.e. that closest to
+//     the origin of the error, if there is one.
+//   - "error.tag.<key>": one field per context tag attached with
+//     WithContextTags(), outermost first. The values are always
+//     redacted, because their safety is not preserved by
+//     GetContextTags().
+//
+// All the values are strings. A nil slice is returned for a nil
+// error.
+func LogFields(err error) []LogField { return errutil.LogFields(err) }
+
+// ContextWithError supports the "capture and retrieve later" pattern,
+// where an error occurring deep in a handler is retrieved by an
+// outer layer, e.g. for logging in a deferred function, without
+// threading it through return values.
+//
+// The outer layer calls ContextWithError() first, possibly with a
+// nil error, to install a slot in the context that it passes down.
+// When ContextWithError() is subsequently called with a context
+// derived from it, the error is stored in that slot and the context
+// is returned unchanged, so that ErrorFromContext() on the outer
+// context returns the last error captured.
+func ContextWithError(ctx context.Context, err error) context.Context {
+	return errutil.ContextWithError(ctx, err)
+}
+
+// ErrorFromContext retrieves the last error captured with
+// ContextWithError() in the context, or nil if there is none.
+func ErrorFromContext(ctx context.Context) error { return errutil.ErrorFromContext(ctx) }
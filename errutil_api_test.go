@@ -29,3 +29,11 @@ func TestJoin(t *testing.T) {
 		t.Errorf("Expected: %s to contain: %s", printed, expected)
 	}
 }
+
+// Make sure that the public API captures the location of its caller.
+func TestWrapHere(t *testing.T) {
+	e := errors.WrapHere(errors.New("abc123"))
+	if !strings.HasPrefix(e.Error(), "errutil_api_test.go:") {
+		t.Errorf("Expected: %s to start with the location of the caller", e)
+	}
+}
@@ -84,6 +84,132 @@ func GetGrpcCode(err error) codes.Code {
 	return codes.Unknown
 }
 
+// GetInnermostGrpcCode retrieves the gRPC code attached with
+// WrapWithGrpcCode() closest to the origin of the error, i.e. at the
+// deepest layer in the error's direct causal chain, or false if there
+// is none.
+//
+// This differs from GetGrpcCode(), which returns the outermost code,
+// i.e. the one attached last, and which defaults to codes.Unknown
+// (or codes.OK for a nil error) instead of reporting its absence. When
+// a single code is attached, both return the same code.
+func GetInnermostGrpcCode(err error) (codes.Code, bool) {
+	code, ok := codes.Unknown, false
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		if w, isCode := c.(*withGrpcCode); isCode {
+			code, ok = w.code, true
+		}
+	}
+	return code, ok
+}
+
+// ToStatus converts an error into a gRPC Status. The status code is
+// that returned by GetGrpcCode() and the encoded error is attached as
+// a status detail, so that the full error can be reconstructed with
+// DecodeError() on the other side. If the error is already a gRPC
+// Status error, its Status is returned as-is. A nil error produces an
+// OK status.
+func ToStatus(err error) *grpcstatus.Status {
+	if err == nil {
+		return grpcstatus.New(codes.OK, "")
+	}
+	if s, ok := gogostatus.FromError(err); ok {
+		return grpcstatus.Convert(s.Err())
+	}
+	return toStatus(err, err.Error())
+}
+
+// ToStatusSafe is like ToStatus, but uses the redacted message of the
+// error as the status message, with unsafe strings replaced by
+// redaction markers. This makes the message suitable for presentation
+// to untrusted clients. The full error is still attached as a status
+// detail, for use by trusted peers. If the error is already a gRPC
+// Status error, its message is redacted likewise.
+func ToStatusSafe(err error) *grpcstatus.Status {
+	if err == nil {
+		return grpcstatus.New(codes.OK, "")
+	}
+	msg := redact.Sprint(err).Redact().StripMarkers()
+	if s, ok := gogostatus.FromError(err); ok {
+		p := s.Proto()
+		p.Message = msg
+		return grpcstatus.Convert(gogostatus.ErrorProto(p))
+	}
+	return toStatus(err, msg)
+}
+
+// toStatus converts an error which is not a gRPC Status error into a
+// Status with the given message.
+func toStatus(err error, msg string) *grpcstatus.Status {
+	s := gogostatus.New(GetGrpcCode(err), msg)
+	enc := errors.EncodeError(context.Background(), err)
+	// Details must be attached using the gogo Status, because
+	// EncodedError is a gogoproto message. See the package
+	// documentation for details.
+	if withDetails, detailErr := s.WithDetails(&enc); detailErr == nil {
+		s = withDetails
+	}
+	return grpcstatus.Convert(s.Err())
+}
+
+// StatusesFor converts a slice of errors into gRPC Statuses, for
+// example to report per-item results from a batch RPC. Each error is
+// converted as per ToStatus(). The ordering is preserved, and nil
+// errors produce an OK status.
+func StatusesFor(errs []error) []*grpcstatus.Status {
+	res := make([]*grpcstatus.Status, len(errs))
+	for i, err := range errs {
+		res[i] = ToStatus(err)
+	}
+	return res
+}
+
+// NormalizeOptions configures Normalize().
+type NormalizeOptions struct {
+	errors.NormalizeOptions
+	// EnsureGrpcCode, if true, attaches a gRPC code to errors that do
+	// not carry one yet, either via WrapWithGrpcCode() or as a gRPC
+	// Status error.
+	EnsureGrpcCode bool
+	// DefaultCode is the code attached when EnsureGrpcCode is set. The
+	// zero value, codes.OK, is replaced by codes.Internal.
+	DefaultCode codes.Code
+}
+
+// Normalize prepares an error to be returned by a gRPC service, as
+// configured by opts. It applies errors.Normalize() and, if
+// requested, ensures that the error carries a gRPC code. Each step
+// is a no-op if the error already satisfies it.
+func Normalize(err error, opts NormalizeOptions) error {
+	if err == nil {
+		return nil
+	}
+	err = errors.Normalize(err, opts.NormalizeOptions)
+	if opts.EnsureGrpcCode && !hasGrpcCode(err) {
+		code := opts.DefaultCode
+		if code == codes.OK {
+			code = codes.Internal
+		}
+		err = WrapWithGrpcCode(err, code)
+	}
+	return err
+}
+
+// hasGrpcCode returns true if the error carries a gRPC code,
+// attached with WrapWithGrpcCode() or carried by a gRPC Status.
+func hasGrpcCode(err error) bool {
+	_, ok := markers.If(err, func(err error) (interface{}, bool) {
+		if _, ok := err.(*withGrpcCode); ok {
+			return nil, true
+		}
+		if _, ok := grpcstatus.FromError(err); ok {
+			return nil, true
+		}
+		return nil, false
+	})
+	return ok
+}
+
 // it's an error.
 func (w *withGrpcCode) Error() string { return w.cause.Error() }
 
@@ -185,4 +311,42 @@ func init() {
 
 	errbase.RegisterWrapperEncoder(errbase.GetTypeKey((*withGrpcCode)(nil)), encodeWithGrpcCode)
 	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withGrpcCode)(nil)), decodeWithGrpcCode)
+	errbase.RegisterStrippableAnnotation(errbase.GetTypeKey((*withGrpcCode)(nil)))
+
+	errors.RegisterNotFoundPredicate(isNotFound)
+	errors.RegisterCodeExtractor("grpc", getGrpcCodeString)
+	errors.RegisterCodeWrapper("grpc", func(err, from error) error {
+		return WrapWithGrpcCode(err, GetGrpcCode(from))
+	})
+}
+
+// getGrpcCodeString is registered with errors.RegisterCodeExtractor
+// and reports the outermost code attached with WrapWithGrpcCode().
+func getGrpcCodeString(err error) (string, bool) {
+	v, ok := markers.If(err, func(err error) (interface{}, bool) {
+		if w, ok := err.(*withGrpcCode); ok {
+			return w.code, true
+		}
+		return nil, false
+	})
+	if !ok {
+		return "", false
+	}
+	return v.(codes.Code).String(), true
+}
+
+// isNotFound is registered with errors.RegisterNotFoundPredicate
+// and recognizes errors with the gRPC NotFound code, either
+// attached with WrapWithGrpcCode() or carried by a gRPC Status.
+func isNotFound(err error) bool {
+	if GetGrpcCode(err) == codes.NotFound {
+		return true
+	}
+	_, ok := markers.If(err, func(err error) (interface{}, bool) {
+		if s, ok := grpcstatus.FromError(err); ok && s.Code() == codes.NotFound {
+			return nil, true
+		}
+		return nil, false
+	})
+	return ok
 }
@@ -68,6 +68,167 @@ Error types: (1) *extgrpc.withGrpcCode (2) *errors.errorString`)
 	tt.Assert(extgrpc.GetGrpcCode(noErr) == codes.OK)
 }
 
+func TestGetInnermostGrpcCode(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := errors.New("hello")
+	_, ok := extgrpc.GetInnermostGrpcCode(err)
+	tt.Check(!ok)
+
+	err = extgrpc.WrapWithGrpcCode(err, codes.NotFound)
+	code, ok := extgrpc.GetInnermostGrpcCode(err)
+	tt.Check(ok)
+	tt.CheckEqual(code, codes.NotFound)
+	tt.CheckEqual(extgrpc.GetGrpcCode(err), codes.NotFound)
+
+	// With codes at two layers, GetGrpcCode returns the outermost and
+	// GetInnermostGrpcCode the innermost.
+	err = extgrpc.WrapWithGrpcCode(errors.Wrap(err, "woo"), codes.Unavailable)
+	tt.CheckEqual(extgrpc.GetGrpcCode(err), codes.Unavailable)
+	code, ok = extgrpc.GetInnermostGrpcCode(err)
+	tt.Check(ok)
+	tt.CheckEqual(code, codes.NotFound)
+
+	// This survives a network transfer.
+	enc := errors.EncodeError(context.Background(), err)
+	newErr := errors.DecodeError(context.Background(), enc)
+	code, ok = extgrpc.GetInnermostGrpcCode(newErr)
+	tt.Check(ok)
+	tt.CheckEqual(code, codes.NotFound)
+}
+
+func TestStatusesFor(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	errs := []error{
+		extgrpc.WrapWithGrpcCode(errors.New("not here"), codes.NotFound),
+		nil,
+		errors.New("uncoded"),
+		grpcstatus.Error(codes.Unavailable, "try later"),
+	}
+	statuses := extgrpc.StatusesFor(errs)
+
+	// Ordering is preserved and nil entries are not dropped.
+	tt.Assert(len(statuses) == len(errs))
+	tt.CheckEqual(statuses[0].Code(), codes.NotFound)
+	tt.CheckEqual(statuses[1].Code(), codes.OK)
+	tt.CheckEqual(statuses[2].Code(), codes.Unknown)
+	tt.CheckEqual(statuses[3].Code(), codes.Unavailable)
+
+	tt.CheckStringEqual(statuses[0].Message(), "not here")
+	tt.CheckStringEqual(statuses[2].Message(), "uncoded")
+	tt.CheckStringEqual(statuses[3].Message(), "try later")
+
+	// The encoded errors are attached as details and
+	// can be decoded back.
+	for _, i := range []int{0, 2} {
+		details := gogostatus.FromGRPCStatus(statuses[i]).Details()
+		tt.Assert(len(details) == 1)
+		enc, ok := details[0].(*errors.EncodedError)
+		tt.Assert(ok)
+		decoded := errors.DecodeError(context.Background(), *enc)
+		tt.CheckStringEqual(decoded.Error(), errs[i].Error())
+		tt.CheckEqual(extgrpc.GetGrpcCode(decoded), extgrpc.GetGrpcCode(errs[i]))
+	}
+	// The OK status has no details.
+	tt.Check(len(statuses[1].Details()) == 0)
+}
+
+func TestToStatusSafe(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := errors.Newf("user %s not found", "alice")
+	err = extgrpc.WrapWithGrpcCode(err, codes.NotFound)
+
+	// The unsafe string is visible in the full message but is
+	// redacted in the status message.
+	tt.CheckStringEqual(err.Error(), "user alice not found")
+	tt.CheckStringEqual(extgrpc.ToStatus(err).Message(), "user alice not found")
+	s := extgrpc.ToStatusSafe(err)
+	tt.CheckEqual(s.Code(), codes.NotFound)
+	tt.CheckStringEqual(s.Message(), "user × not found")
+
+	// The full error is still attached as a detail.
+	details := gogostatus.FromGRPCStatus(s).Details()
+	tt.Assert(len(details) == 1)
+	enc, ok := details[0].(*errors.EncodedError)
+	tt.Assert(ok)
+	decoded := errors.DecodeError(context.Background(), *enc)
+	tt.CheckStringEqual(decoded.Error(), err.Error())
+	tt.CheckEqual(extgrpc.GetGrpcCode(decoded), codes.NotFound)
+
+	// The message of gRPC Status errors is redacted too.
+	s = extgrpc.ToStatusSafe(grpcstatus.Error(codes.Unavailable, "secret"))
+	tt.CheckEqual(s.Code(), codes.Unavailable)
+	tt.Check(!strings.Contains(s.Message(), "secret"))
+
+	tt.CheckEqual(extgrpc.ToStatusSafe(nil).Code(), codes.OK)
+}
+
+func TestGrpcNotFound(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := errors.New("hello")
+	tt.Check(!errors.IsNotFound(err))
+	tt.Check(!errors.IsNotFound(extgrpc.WrapWithGrpcCode(err, codes.Unavailable)))
+	tt.Check(!errors.IsNotFound(grpcstatus.Error(codes.Unavailable, "hello")))
+
+	testData := []error{
+		extgrpc.WrapWithGrpcCode(err, codes.NotFound),
+		grpcstatus.Error(codes.NotFound, "hello"),
+		gogostatus.Error(codes.NotFound, "hello"),
+		errors.Wrap(grpcstatus.Error(codes.NotFound, "hello"), "woo"),
+	}
+	for _, err := range testData {
+		tt.Run(err.Error(), func(tt testutils.T) {
+			tt.Check(errors.IsNotFound(err))
+
+			// Simulate a network transfer.
+			enc := errors.EncodeError(context.Background(), err)
+			tt.Check(errors.IsNotFound(errors.DecodeError(context.Background(), enc)))
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.Check(extgrpc.Normalize(nil, extgrpc.NormalizeOptions{EnsureGrpcCode: true}) == nil)
+
+	// The zero options leave the error unchanged.
+	err := errors.New("hello")
+	tt.Check(extgrpc.Normalize(err, extgrpc.NormalizeOptions{}) == err)
+
+	// By default, the code is Internal.
+	newErr := extgrpc.Normalize(err, extgrpc.NormalizeOptions{EnsureGrpcCode: true})
+	tt.CheckEqual(extgrpc.GetGrpcCode(newErr), codes.Internal)
+	tt.CheckEqual(errors.GetDomain(newErr), errors.NoDomain)
+	tt.Check(errors.HasStack(newErr))
+
+	opts := extgrpc.NormalizeOptions{EnsureGrpcCode: true, DefaultCode: codes.Unavailable}
+	newErr = extgrpc.Normalize(err, opts)
+	tt.CheckEqual(extgrpc.GetGrpcCode(newErr), codes.Unavailable)
+
+	// An existing code is preserved.
+	for _, origErr := range []error{
+		extgrpc.WrapWithGrpcCode(err, codes.NotFound),
+		grpcstatus.Error(codes.NotFound, "hello"),
+		errors.Wrap(gogostatus.Error(codes.NotFound, "hello"), "woo"),
+	} {
+		tt.Check(extgrpc.Normalize(origErr, opts) == origErr)
+	}
+
+	// The other steps are delegated to errors.Normalize().
+	opts = extgrpc.NormalizeOptions{NormalizeOptions: errors.NormalizeOptions{
+		Domain: errors.NamedDomain("mysvc"), StripStacks: true,
+	}}
+	newErr = extgrpc.Normalize(err, opts)
+	tt.CheckEqual(errors.GetDomain(newErr), errors.NamedDomain("mysvc"))
+	tt.Check(!errors.HasStack(newErr))
+	tt.CheckEqual(extgrpc.GetGrpcCode(newErr), codes.Unknown)
+	tt.CheckStringEqual(newErr.Error(), "hello")
+}
+
 // dummyProto is a dummy Protobuf message which satisfies the proto.Message
 // interface but is not registered with either the standard Protobuf or GoGo
 // Protobuf type registries.
@@ -17,6 +17,8 @@ package exthttp
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strconv"
 
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/errors/errbase"
@@ -94,4 +96,26 @@ func decodeWithHTTPCode(
 func init() {
 	errbase.RegisterWrapperEncoder(errbase.GetTypeKey((*withHTTPCode)(nil)), encodeWithHTTPCode)
 	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withHTTPCode)(nil)), decodeWithHTTPCode)
+	errbase.RegisterStrippableAnnotation(errbase.GetTypeKey((*withHTTPCode)(nil)))
+
+	errors.RegisterNotFoundPredicate(isNotFound)
+	errors.RegisterCodeExtractor("http", getHTTPCodeString)
+	errors.RegisterCodeWrapper("http", func(err, from error) error {
+		return WrapWithHTTPCode(err, GetHTTPCode(from, 0))
+	})
+}
+
+// getHTTPCodeString is registered with errors.RegisterCodeExtractor
+// and reports the outermost HTTP code.
+func getHTTPCodeString(err error) (string, bool) {
+	if code := GetHTTPCode(err, 0); code != 0 {
+		return strconv.Itoa(code), true
+	}
+	return "", false
+}
+
+// isNotFound is registered with errors.RegisterNotFoundPredicate
+// and recognizes errors with the HTTP 404 code.
+func isNotFound(err error) bool {
+	return GetHTTPCode(err, 0) == http.StatusNotFound
 }
@@ -52,3 +52,19 @@ func TestHTTP(t *testing.T) {
 Wraps: (2) hello
 Error types: (1) *exthttp.withHTTPCode (2) *errors.errorString`)
 }
+
+func TestHTTPNotFound(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := fmt.Errorf("hello")
+	tt.Check(!errors.IsNotFound(err))
+	tt.Check(!errors.IsNotFound(exthttp.WrapWithHTTPCode(err, 500)))
+
+	err = exthttp.WrapWithHTTPCode(err, 404)
+	tt.Check(errors.IsNotFound(err))
+
+	// Simulate a network transfer.
+	enc := errors.EncodeError(context.Background(), errors.Wrap(err, "woo"))
+	otherErr := errors.DecodeError(context.Background(), enc)
+	tt.Check(errors.IsNotFound(otherErr))
+}
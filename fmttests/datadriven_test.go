@@ -41,6 +41,7 @@ import (
 	"github.com/cockroachdb/errors/safedetails"
 	"github.com/cockroachdb/errors/secondary"
 	"github.com/cockroachdb/errors/telemetrykeys"
+	"github.com/cockroachdb/errors/testutils"
 	"github.com/cockroachdb/errors/withstack"
 	"github.com/cockroachdb/logtags"
 	"github.com/cockroachdb/redact"
@@ -819,30 +820,8 @@ func TestDatadriven(t *testing.T) {
 }
 
 func checkMarkers(buf *bytes.Buffer, d *datadriven.TestData, t *testing.T, s string) {
-	sm := string(redact.StartMarker())
-	em := string(redact.EndMarker())
-	anyMarker := regexp.MustCompile("((?s)[" + sm + em + "].*)")
-	expectOpen := true
-	for {
-		s = anyMarker.FindString(s)
-		if s == "" {
-			break
-		}
-		if expectOpen {
-			if strings.HasPrefix(s, em) {
-				d.Fatalf(t, "unexpected closing redaction marker:\n%s\n\n(suffix: %q)", buf.String(), s)
-			}
-			s = strings.TrimPrefix(s, sm)
-		} else {
-			if strings.HasPrefix(s, sm) {
-				d.Fatalf(t, "unexpected open redaction marker:\n%s\n\n(suffix: %q)", buf.String(), s)
-			}
-			s = strings.TrimPrefix(s, em)
-		}
-		expectOpen = !expectOpen
-	}
-	if !expectOpen {
-		d.Fatalf(t, "unclosed redaction marker:\n%s\n\n(suffix: %q)", buf.String(), s)
+	if !testutils.CheckBalancedMarkers(t, s) {
+		d.Fatalf(t, "unbalanced redaction markers in output:\n%s", buf.String())
 	}
 }
 
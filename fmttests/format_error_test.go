@@ -18,9 +18,6 @@ import (
 	"context"
 	goErr "errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"regexp"
 	"strings"
 	"testing"
 
@@ -371,12 +368,7 @@ func TestHelperForErrorf(t *testing.T) {
 }
 
 func fmtClean(spv string) string {
-	spv = fileref.ReplaceAllString(spv, "<path>:<lineno>")
-	spv = libref.ReplaceAllString(spv, "<path>")
-	spv = stackref.ReplaceAllString(spv, `&stack{...}`)
-	spv = hexref.ReplaceAllString(spv, "0xAAAABBBB")
-	spv = strings.ReplaceAll(spv, "\t", "<tab>")
-	spv = funcNN.ReplaceAllString(spv, `...funcNN...`)
+	spv = testutils.NormalizeErrorOutput(spv)
 
 	// When running the tests with a Go version before 1.16,
 	// the reference test output wrt fs.PathError will not match what the
@@ -389,34 +381,6 @@ func fmtClean(spv string) string {
 	return spv
 }
 
-var funcNN = regexp.MustCompile(`(?m)((\.\.func\d+| func\d+\(\)|\(func\d+\))"?$)|(\.\.func\d+\\n)`)
-
-var stackref = regexp.MustCompile(`(&(?:errors\.stack|withstack\.stack)\{[^}]*\})`)
-var fileref = regexp.MustCompile(`(` +
-	// Any path ending with .{go,s}:NNN:
-	`[a-zA-Z0-9\._/@-]+\.(?:go|s):\d+` +
-	`)`)
-var libroot = func() string {
-	wd, err := os.Getwd()
-	if err != nil {
-		panic(err)
-	}
-	return filepath.Clean(filepath.Join(wd, ".."))
-}()
-var libref = regexp.MustCompile(
-	`((` +
-		// Any path starting with the errors library root directory:
-		libroot +
-		`|` +
-		// Any path containing the error library:
-		`(/[a-zA-Z0-9\._/@-]+)+` +
-		`/github.com/cockroachdb/errors` +
-		`)` +
-		// Followed by some directory components:
-		`(/[a-zA-Z0-9\._/@-]+)*` +
-		`)`)
-var hexref = regexp.MustCompile(`(0x[a-f0-9]{4,})`)
-
 // errNoFmt does neither implement Format() nor FormatError().
 type errNoFmt struct{ msg string }
 
@@ -48,6 +48,23 @@ func WithHintf(err error, format string, args ...interface{}) error {
 	return &withHint{cause: err, hint: fmt.Sprintf(format, args...)}
 }
 
+// WithHintOnce is like WithHint, except that err is returned
+// unchanged if the hint is already one of those returned by
+// GetAllHints(). This avoids accumulating identical hints when an
+// error passes through the same code repeatedly, e.g. in loops or
+// recursion.
+func WithHintOnce(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	for _, h := range GetAllHints(err) {
+		if h == msg {
+			return err
+		}
+	}
+	return &withHint{cause: err, hint: msg}
+}
+
 // GetAllHints retrieves the hints from the error using in post-order
 // traversal. The hints are de-duplicated. Assertion failures, issue
 // links and unimplemented errors are detected and receive standard
@@ -125,6 +142,23 @@ func WithDetailf(err error, format string, args ...interface{}) error {
 	return &withDetail{cause: err, detail: fmt.Sprintf(format, args...)}
 }
 
+// WithDetailOnce is like WithDetail, except that err is returned
+// unchanged if the detail is already one of those returned by
+// GetAllDetails(). This avoids accumulating identical details when
+// an error passes through the same code repeatedly, e.g. in loops or
+// recursion.
+func WithDetailOnce(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	for _, d := range GetAllDetails(err) {
+		if d == msg {
+			return err
+		}
+	}
+	return &withDetail{cause: err, detail: msg}
+}
+
 // GetAllDetails retrieves the details from the error using in post-order
 // traversal.
 func GetAllDetails(err error) []string {
@@ -144,6 +178,30 @@ func FlattenDetails(err error) string {
 	return b.String()
 }
 
+// FlattenDetailsUnique is like FlattenDetails() but omits details
+// identical to one already included, preserving the order in which
+// they were first seen. This is useful when the same detail was
+// attached at multiple levels of the causal chain. GetAllDetails()
+// continues to return all the details.
+//
+// Note that hints are always de-duplicated by GetAllHints(), so
+// FlattenHints() already produces unique output.
+func FlattenDetailsUnique(err error) string {
+	var b bytes.Buffer
+	sep := ""
+	seen := make(map[string]struct{})
+	for _, d := range GetAllDetails(err) {
+		if _, ok := seen[d]; ok {
+			continue
+		}
+		seen[d] = struct{}{}
+		b.WriteString(sep)
+		b.WriteString(d)
+		sep = "\n--\n"
+	}
+	return b.String()
+}
+
 func getAllDetailsInternal(err error, details []string) []string {
 	if c := errbase.UnwrapOnce(err); c != nil {
 		details = getAllDetailsInternal(c, details)
@@ -211,6 +211,59 @@ func TestMultiHintDetail(t *testing.T) {
 	tt.CheckStringEqual(hintdetail.FlattenDetails(err), "foo\n--\nbar")
 }
 
+func TestDuplicateHintDetail(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := errors.New("hello world")
+	err = hintdetail.WithHint(err, "retry later")
+	err = hintdetail.WithDetail(err, "foo")
+	err = hintdetail.WithHint(err, "woo")
+	err = hintdetail.WithDetail(err, "bar")
+	err = hintdetail.WithHint(err, "retry later")
+	err = hintdetail.WithDetail(err, "foo")
+
+	// Hints are always de-duplicated, preserving first-seen order.
+	tt.CheckDeepEqual(hintdetail.GetAllHints(err), []string{"retry later", "woo"})
+	tt.CheckStringEqual(hintdetail.FlattenHints(err), "retry later\n--\nwoo")
+
+	// Details are preserved by GetAllDetails and FlattenDetails...
+	tt.CheckDeepEqual(hintdetail.GetAllDetails(err), []string{"foo", "bar", "foo"})
+	tt.CheckStringEqual(hintdetail.FlattenDetails(err), "foo\n--\nbar\n--\nfoo")
+
+	// ... but de-duplicated by FlattenDetailsUnique.
+	tt.CheckStringEqual(hintdetail.FlattenDetailsUnique(err), "foo\n--\nbar")
+}
+
+func TestHintDetailOnce(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.Check(hintdetail.WithHintOnce(nil, "woo") == nil)
+	tt.Check(hintdetail.WithDetailOnce(nil, "woo") == nil)
+
+	origErr := errors.New("hello world")
+
+	// A second identical hint is a no-op, even across other layers.
+	err := hintdetail.WithHintOnce(origErr, "retry later")
+	err = errors.Wrap(err, "woo")
+	again := hintdetail.WithHintOnce(err, "retry later")
+	tt.Check(again == err)
+
+	// A different hint is added.
+	err = hintdetail.WithHintOnce(err, "check the logs")
+	tt.CheckDeepEqual(hintdetail.GetAllHints(err), []string{"retry later", "check the logs"})
+
+	// Same for details.
+	err = hintdetail.WithDetailOnce(err, "foo")
+	again = hintdetail.WithDetailOnce(err, "foo")
+	tt.Check(again == err)
+	err = hintdetail.WithDetailOnce(err, "bar")
+	tt.CheckDeepEqual(hintdetail.GetAllDetails(err), []string{"foo", "bar"})
+
+	// Hints and details are checked separately.
+	err = hintdetail.WithDetailOnce(err, "retry later")
+	tt.CheckDeepEqual(hintdetail.GetAllDetails(err), []string{"foo", "bar", "retry later"})
+}
+
 func TestFormat(t *testing.T) {
 	tt := testutils.T{t}
 
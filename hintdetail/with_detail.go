@@ -69,4 +69,5 @@ func decodeWithDetail(
 func init() {
 	errbase.RegisterWrapperEncoder(errbase.GetTypeKey((*withDetail)(nil)), encodeWithDetail)
 	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withDetail)(nil)), decodeWithDetail)
+	errbase.RegisterStrippableAnnotation(errbase.GetTypeKey((*withDetail)(nil)))
 }
@@ -69,4 +69,5 @@ func decodeWithHint(
 func init() {
 	errbase.RegisterWrapperEncoder(errbase.GetTypeKey((*withHint)(nil)), encodeWithHint)
 	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withHint)(nil)), decodeWithHint)
+	errbase.RegisterStrippableAnnotation(errbase.GetTypeKey((*withHint)(nil)))
 }
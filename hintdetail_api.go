@@ -43,6 +43,13 @@ func WithHintf(err error, format string, args ...interface{}) error {
 	return hintdetail.WithHintf(err, format, args...)
 }
 
+// WithHintOnce is like WithHint, except that err is returned
+// unchanged if the hint is already one of those returned by
+// GetAllHints(). This avoids accumulating identical hints when an
+// error passes through the same code repeatedly, e.g. in loops or
+// recursion.
+func WithHintOnce(err error, msg string) error { return hintdetail.WithHintOnce(err, msg) }
+
 // WithDetail decorates an error with a textual detail.
 // The detail may contain PII and thus will not reportable.
 // The suggested use case for detail is to augment errors with information
@@ -63,6 +70,13 @@ func WithDetailf(err error, format string, args ...interface{}) error {
 	return hintdetail.WithDetailf(err, format, args...)
 }
 
+// WithDetailOnce is like WithDetail, except that err is returned
+// unchanged if the detail is already one of those returned by
+// GetAllDetails(). This avoids accumulating identical details when
+// an error passes through the same code repeatedly, e.g. in loops or
+// recursion.
+func WithDetailOnce(err error, msg string) error { return hintdetail.WithDetailOnce(err, msg) }
+
 // GetAllHints retrieves the hints from the error using in post-order
 // traversal. The hints are de-duplicated. Assertion failures, issue
 // links and unimplemented errors are detected and receive standard
@@ -80,3 +94,13 @@ func GetAllDetails(err error) []string { return hintdetail.GetAllDetails(err) }
 // FlattenDetails retrieves the details as per GetAllDetails() and
 // concatenates them into a single string.
 func FlattenDetails(err error) string { return hintdetail.FlattenDetails(err) }
+
+// FlattenDetailsUnique is like FlattenDetails() but omits details
+// identical to one already included, preserving the order in which
+// they were first seen. This is useful when the same detail was
+// attached at multiple levels of the causal chain. GetAllDetails()
+// continues to return all the details.
+//
+// Note that hints are always de-duplicated by GetAllHints(), so
+// FlattenHints() already produces unique output.
+func FlattenDetailsUnique(err error) string { return hintdetail.FlattenDetailsUnique(err) }
@@ -94,4 +94,5 @@ func decodeWithIssueLink(
 
 func init() {
 	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withIssueLink)(nil)), decodeWithIssueLink)
+	errbase.RegisterStrippableAnnotation(errbase.GetTypeKey((*withIssueLink)(nil)))
 }
@@ -0,0 +1,111 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package issuelink
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+// RemediationLink is the payload for a remediation link annotation.
+type RemediationLink struct {
+	// Title is the human-readable label of the link.
+	Title string
+	// URL to the remediation resource, e.g. a documentation page.
+	URL string
+}
+
+// WithRemediationLink adds an annotation that refers to a resource
+// which explains how to address the error, e.g. a documentation
+// page. Unlike a hint, the link is structured, so that CLIs and UIs
+// can render it as a clickable link. Issue links, see
+// WithIssueLink(), are a narrower case which refers to an issue
+// tracker.
+//
+// The title and url strings may contain PII and will
+// be considered reportable.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`
+// - via `GetRemediationLinks()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithRemediationLink(err error, title, url string) error {
+	if err == nil {
+		return nil
+	}
+	return &withRemediationLink{cause: err, RemediationLink: RemediationLink{Title: title, URL: url}}
+}
+
+// GetRemediationLinks retrieves the remediation links carried
+// by the error or its direct causes.
+// The links are returned from outermost to innermost level of cause.
+func GetRemediationLinks(err error) (links []RemediationLink) {
+	for ; err != nil; err = errbase.UnwrapOnce(err) {
+		if w, ok := err.(*withRemediationLink); ok {
+			links = append(links, w.RemediationLink)
+		}
+	}
+	return
+}
+
+type withRemediationLink struct {
+	cause error
+	RemediationLink
+}
+
+var _ error = (*withRemediationLink)(nil)
+var _ errbase.SafeDetailer = (*withRemediationLink)(nil)
+var _ fmt.Formatter = (*withRemediationLink)(nil)
+var _ errbase.SafeFormatter = (*withRemediationLink)(nil)
+
+func (w *withRemediationLink) Error() string { return w.cause.Error() }
+func (w *withRemediationLink) Cause() error  { return w.cause }
+func (w *withRemediationLink) Unwrap() error { return w.cause }
+
+func (w *withRemediationLink) SafeDetails() []string {
+	return []string{w.Title, w.URL}
+}
+
+func (w *withRemediationLink) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, verb) }
+
+func (w *withRemediationLink) SafeFormatError(p errbase.Printer) error {
+	if p.Detail() {
+		p.Printf("see: %s (%s)", redact.Safe(w.Title), redact.Safe(w.URL))
+	}
+	return w.cause
+}
+
+func decodeWithRemediationLink(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	var link RemediationLink
+	if len(details) > 0 {
+		link.Title = details[0]
+	}
+	if len(details) > 1 {
+		link.URL = details[1]
+	}
+	return &withRemediationLink{cause: cause, RemediationLink: link}
+}
+
+func init() {
+	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withRemediationLink)(nil)), decodeWithRemediationLink)
+	errbase.RegisterStrippableAnnotation(errbase.GetTypeKey((*withRemediationLink)(nil)))
+}
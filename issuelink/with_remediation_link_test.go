@@ -0,0 +1,75 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package issuelink_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/issuelink"
+	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+	"github.com/pkg/errors"
+)
+
+func TestRemediationLink(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.Check(issuelink.WithRemediationLink(nil, "a", "b") == nil)
+
+	origErr := errors.New("world")
+
+	err := issuelink.WithRemediationLink(
+		issuelink.WithIssueLink(
+			issuelink.WithRemediationLink(origErr, "troubleshooting", "https://example.com/docs/ts"),
+			issuelink.IssueLink{IssueURL: "https://example.com/issues/123"},
+		),
+		"configuration", "https://example.com/docs/config",
+	)
+
+	theTest := func(tt testutils.T, err error) {
+		tt.Check(markers.Is(err, origErr))
+		tt.CheckStringEqual(err.Error(), "world")
+
+		tt.CheckDeepEqual(issuelink.GetRemediationLinks(err), []issuelink.RemediationLink{
+			{Title: "configuration", URL: "https://example.com/docs/config"},
+			{Title: "troubleshooting", URL: "https://example.com/docs/ts"},
+		})
+
+		// Remediation links and issue links are independent.
+		tt.CheckDeepEqual(issuelink.GetAllIssueLinks(err), []issuelink.IssueLink{
+			{IssueURL: "https://example.com/issues/123"},
+		})
+
+		errV := fmt.Sprintf("%+v", err)
+		tt.CheckContains(errV, "\n(1) see: configuration (https://example.com/docs/config)\n")
+		tt.CheckContains(errV, "\nWraps: (2) issue: https://example.com/issues/123\n")
+		tt.CheckContains(errV, "\nWraps: (3) see: troubleshooting (https://example.com/docs/ts)\n")
+
+		// The links are considered safe for reporting.
+		errR := redact.Sprintf("%+v", err).Redact()
+		tt.CheckContains(string(errR), "see: configuration (https://example.com/docs/config)")
+	}
+
+	tt.Run("local", func(tt testutils.T) { theTest(tt, err) })
+
+	enc := errbase.EncodeError(context.Background(), err)
+	newErr := errbase.DecodeError(context.Background(), enc)
+
+	tt.Run("remote", func(tt testutils.T) { theTest(tt, newErr) })
+}
@@ -54,6 +54,35 @@ func UnimplementedErrorf(issueLink IssueLink, format string, args ...interface{}
 // by the error or its direct causes.
 func GetAllIssueLinks(err error) (issues []IssueLink) { return issuelink.GetAllIssueLinks(err) }
 
+// RemediationLink is the payload for a remediation link annotation.
+type RemediationLink = issuelink.RemediationLink
+
+// WithRemediationLink adds an annotation that refers to a resource
+// which explains how to address the error, e.g. a documentation
+// page. Unlike a hint, the link is structured, so that CLIs and UIs
+// can render it as a clickable link. Issue links, see
+// WithIssueLink(), are a narrower case which refers to an issue
+// tracker.
+//
+// The title and url strings may contain PII and will
+// be considered reportable.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`
+// - via `GetRemediationLinks()` below.
+// - when formatting with `%+v`.
+// - in Sentry reports.
+func WithRemediationLink(err error, title, url string) error {
+	return issuelink.WithRemediationLink(err, title, url)
+}
+
+// GetRemediationLinks retrieves the remediation links carried
+// by the error or its direct causes.
+// The links are returned from outermost to innermost level of cause.
+func GetRemediationLinks(err error) (links []RemediationLink) {
+	return issuelink.GetRemediationLinks(err)
+}
+
 // HasIssueLink returns true iff the error or one of its
 // causes has a linked issue payload.
 func HasIssueLink(err error) bool { return issuelink.HasIssueLink(err) }
@@ -91,6 +91,83 @@ func Is(err, reference error) bool {
 	return false
 }
 
+// IsIgnoring is like Is, except that the layers whose error type
+// family name, as reported by errbase.GetTypeMark(), is one of
+// ignoreFamilies are skipped when comparing the error marks. This
+// makes it possible to compare errors irrespective of annotations
+// that otherwise break equivalence, such as error domains.
+//
+// Note that the annotations are only skipped when computing the
+// marks: an ignored layer is not a valid reference by itself.
+func IsIgnoring(err, reference error, ignoreFamilies ...string) bool {
+	if Is(err, reference) {
+		return true
+	}
+	if err == nil || reference == nil || len(ignoreFamilies) == 0 {
+		return false
+	}
+	ignored := make(map[string]struct{}, len(ignoreFamilies))
+	for _, f := range ignoreFamilies {
+		ignored[f] = struct{}{}
+	}
+	refMark := filterMark(getMark(reference), ignored)
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		if _, skip := ignored[errbase.GetTypeMark(c).FamilyName]; skip {
+			continue
+		}
+		if equalMarks(filterMark(getMark(c), ignored), refMark) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsType is like Is, except that only the types of the error marks
+// are compared, and not their messages: it returns true if the error
+// or one of its causes has the same sequence of error types as the
+// reference, including the types of all the causes. This makes it
+// possible to classify errors whose message varies, e.g. because it
+// contains identifiers, by their structure.
+//
+// Note: if any of the error types has been migrated from a previous
+// package location or a different type, ensure that
+// RegisterTypeMigration() was called prior to IsType().
+func IsType(err, reference error) bool {
+	if err == nil || reference == nil {
+		return err == reference
+	}
+	refMark := getMark(reference)
+	refMark.msg = ""
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		m := getMark(c)
+		m.msg = ""
+		if equalMarks(m, refMark) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterMark removes the type marks with the given family names from
+// the error mark.
+func filterMark(m errorMark, ignored map[string]struct{}) errorMark {
+	m.types = filterTypes(m.types, ignored)
+	m.branchTypes = filterTypes(m.branchTypes, ignored)
+	return m
+}
+
+func filterTypes(
+	types []errorspb.ErrorTypeMark, ignored map[string]struct{},
+) []errorspb.ErrorTypeMark {
+	res := make([]errorspb.ErrorTypeMark, 0, len(types))
+	for _, t := range types {
+		if _, skip := ignored[t.FamilyName]; !skip {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
 func tryDelegateToIsMethod(err, reference error) bool {
 	if x, ok := err.(interface{ Is(error) bool }); ok && x.Is(reference) {
 		return true
@@ -108,6 +185,29 @@ func HasType(err error, referenceType error) bool {
 	return isType
 }
 
+// HasAnyType is like HasType except that multiple reference types
+// are compared. It returns true iff err contains an error whose
+// concrete type matches that of any of referenceTypes. Nil references
+// never match.
+func HasAnyType(err error, referenceTypes ...error) bool {
+	typs := make([]reflect.Type, 0, len(referenceTypes))
+	for _, ref := range referenceTypes {
+		if ref != nil {
+			typs = append(typs, reflect.TypeOf(ref))
+		}
+	}
+	_, isType := If(err, func(err error) (interface{}, bool) {
+		errTyp := reflect.TypeOf(err)
+		for _, typ := range typs {
+			if errTyp == typ {
+				return nil, true
+			}
+		}
+		return nil, false
+	})
+	return isType
+}
+
 // HasInterface returns true if err contains an error which implements the
 // interface pointed to by referenceInterface. The type of referenceInterface
 // must be a pointer to an interface type. If referenceInterface is not a
@@ -212,17 +312,35 @@ func IsAny(err error, references ...error) bool {
 }
 
 type errorMark struct {
-	msg   string
+	msg string
+	// types are the type marks of the error and its direct causes.
 	types []errorspb.ErrorTypeMark
+	// branchTypes are the type marks of the causes of the
+	// multi-errors, in pre-order.
+	branchTypes []errorspb.ErrorTypeMark
+	// remote is set for the marks received from the network via
+	// Mark(). Their encoding is that of previous versions of the
+	// library, which does not include branchTypes.
+	remote bool
 }
 
 // equalMarks compares two error markers.
 func equalMarks(m1, m2 errorMark) bool {
-	if m1.msg != m2.msg {
+	if m1.msg != m2.msg || !equalTypes(m1.types, m2.types) {
+		return false
+	}
+	// The branches are only compared when both marks have them: a
+	// remote mark is compared like in previous versions of the
+	// library, so that marks keep matching across versions.
+	return m1.remote || m2.remote || equalTypes(m1.branchTypes, m2.branchTypes)
+}
+
+func equalTypes(t1, t2 []errorspb.ErrorTypeMark) bool {
+	if len(t1) != len(t2) {
 		return false
 	}
-	for i, t := range m1.types {
-		if !t.Equals(m2.types[i]) {
+	for i, t := range t1 {
+		if !t.Equals(t2[i]) {
 			return false
 		}
 	}
@@ -234,13 +352,35 @@ func getMark(err error) errorMark {
 	if m, ok := err.(*withMark); ok {
 		return m.mark
 	}
-	m := errorMark{msg: safeGetErrMsg(err), types: []errorspb.ErrorTypeMark{errbase.GetTypeMark(err)}}
-	for c := errbase.UnwrapOnce(err); c != nil; c = errbase.UnwrapOnce(c) {
-		m.types = append(m.types, errbase.GetTypeMark(c))
-	}
+	m := errorMark{msg: safeGetErrMsg(err)}
+	m.appendLayers(err, &m.types)
 	return m
 }
 
+// appendLayers appends the type marks of the given error and its
+// direct causes to types, and those of the causes of multi-errors to
+// the branch types of the mark. The latter ensure that two
+// multi-errors with the same message are only considered equivalent
+// if all their branches have the same types, not just one of them.
+//
+// The Error() method of the layers flagged as security sensitive
+// redacts their causes. The unredacted message of their causes is
+// appended to the message of the mark, so that errors that only
+// differ in their unsafe parts are not equivalent.
+func (m *errorMark) appendLayers(err error, types *[]errorspb.ErrorTypeMark) {
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		*types = append(*types, errbase.GetTypeMark(c))
+		if errbase.IsSecuritySensitiveLayer(c) {
+			if cause := errbase.UnwrapOnce(c); cause != nil {
+				m.msg += "\x00" + string(safeGetRedactableMsg(cause).StripMarkers())
+			}
+		}
+		for _, me := range errbase.UnwrapMulti(c) {
+			m.appendLayers(me, &m.branchTypes)
+		}
+	}
+}
+
 // safeGetErrMsg extracts an error's Error() but tolerates panics.
 func safeGetErrMsg(err error) (result string) {
 	defer func() {
@@ -252,9 +392,27 @@ func safeGetErrMsg(err error) (result string) {
 	return
 }
 
+// safeGetRedactableMsg is like safeGetErrMsg, for the redactable
+// message of the error, which keeps its unsafe parts.
+func safeGetRedactableMsg(err error) (result redact.RedactableString) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = redact.Sprintf("(%p).Error() panic: %v", err, r)
+		}
+	}()
+	result = redact.Sprint(err)
+	return
+}
+
 // Mark creates an explicit mark for the given error, using
 // the same mark as some reference error.
 //
+// The mark is encoded like in previous versions of the library, so
+// that it matches the reference on nodes running these versions. The
+// encoded mark does not include the causes of the multi-errors in the
+// reference: after a network transfer, the mark matches the errors
+// that differ from the reference only in these causes.
+//
 // Note: if any of the error types has been migrated from a previous
 // package location or a different type, ensure that
 // RegisterTypeMigration() was called prior to Mark().
@@ -296,6 +454,8 @@ func (m *withMark) SafeFormatError(p errbase.Printer) error {
 
 func encodeMark(_ context.Context, err error) (msg string, _ []string, payload proto.Message) {
 	m := err.(*withMark)
+	// The branch types are not encoded, for compatibility with
+	// previous versions of the library.
 	payload = &errorspb.MarkPayload{Msg: m.mark.msg, Types: m.mark.types}
 	return "", nil, payload
 }
@@ -309,7 +469,7 @@ func decodeMark(_ context.Context, cause error, _ string, _ []string, payload pr
 		// DecodeError use the opaque type.
 		return nil
 	}
-	return &withMark{cause: cause, mark: errorMark{msg: m.Msg, types: m.Types}}
+	return &withMark{cause: cause, mark: errorMark{msg: m.Msg, types: m.Types, remote: true}}
 }
 
 func init() {
@@ -16,6 +16,7 @@ package markers_test
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -352,6 +353,89 @@ func TestStandardFmtMultierrorRemoteRecursiveEquivalence(t *testing.T) {
 	tt.Check(!markers.Is(newErr1, err2))
 }
 
+// This check verifies that two multi-errors sharing one branch
+// but differing in another are not considered equivalent, even
+// when their messages are identical.
+func TestStandardFmtMultierrorSharedBranchNonEquivalence(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	shared := errors.New("hello")
+	// b1 and b2 have the same message but different types.
+	b1 := errors.New("world")
+	b2 := pkgErr.New("world")
+
+	err1 := fmt.Errorf("%w %w", shared, b1)
+	err2 := fmt.Errorf("%w %w", shared, b2)
+	tt.CheckStringEqual(err1.Error(), err2.Error())
+
+	tt.Check(markers.Is(err1, shared))
+	tt.Check(markers.Is(err2, shared))
+	tt.Check(!markers.Is(err1, err2))
+	tt.Check(!markers.Is(err2, err1))
+	tt.Check(!markers.IsAny(err1, err2))
+
+	join1 := errors.Join(shared, b1)
+	join2 := errors.Join(shared, b2)
+	tt.Check(!markers.Is(join1, join2))
+	tt.Check(!markers.Is(join2, join1))
+
+	// The same holds after a network round-trip.
+	newErr1 := network(err1)
+	newErr2 := network(err2)
+	tt.Check(markers.Is(newErr1, err1))
+	tt.Check(markers.Is(newErr2, err2))
+	tt.Check(!markers.Is(newErr1, newErr2))
+	tt.Check(!markers.Is(newErr1, err2))
+	tt.Check(!markers.Is(newErr2, err1))
+	tt.Check(!markers.Is(network(join1), join2))
+
+	// Multi-errors with the same structure remain equivalent.
+	err3 := fmt.Errorf("%w %w", errors.New("hello"), errors.New("world"))
+	tt.Check(markers.Is(err3, err1))
+	tt.Check(markers.Is(network(err3), err1))
+}
+
+// legacyMarkedMultiError is the encoding of
+//
+//	markers.Mark(errors.New("marked"), fmt.Errorf("%w %w", errors.New("hello"), errors.New("world")))
+//
+// produced by a version of the library whose error marks did not
+// include the causes of multi-errors.
+const legacyMarkedMultiError = "129b020a460a440a066d61726b6564123a0a1a6572726f72732f2a6572726f72732e6572726f72537472696e67121c0a1a6572726f72732f2a6572726f72732e6572726f72537472696e671ad0010a376769746875622e636f6d2f636f636b726f61636864622f6572726f72732f6d61726b6572732f2a6d61726b6572732e776974684d61726b12390a376769746875622e636f6d2f636f636b726f61636864622f6572726f72732f6d61726b6572732f2a6d61726b6572732e776974684d61726b225a0a32747970652e676f6f676c65617069732e636f6d2f636f636b726f6163682e6572726f727370622e4d61726b5061796c6f616412240a0b68656c6c6f20776f726c6412150a13666d742f2a666d742e777261704572726f7273"
+
+// This check verifies that the error marks of multi-errors remain
+// compatible with previous versions of the library, whose marks did
+// not include the causes of multi-errors.
+func TestMultierrorMarkCrossVersion(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	ref := fmt.Errorf("%w %w", errors.New("hello"), errors.New("world"))
+
+	// A mark encoded by a previous version matches the reference.
+	b, err := hex.DecodeString(legacyMarkedMultiError)
+	tt.AssertEqual(err, nil)
+	var enc errbase.EncodedError
+	tt.AssertEqual(enc.Unmarshal(b), nil)
+	legacyErr := errbase.DecodeError(context.Background(), enc)
+	tt.CheckStringEqual(legacyErr.Error(), "marked")
+	tt.Check(markers.Is(legacyErr, ref))
+	tt.Check(!markers.Is(legacyErr, errors.New("hello world")))
+
+	// The mark encoded by this version is identical, so that it
+	// matches the reference on the nodes running a previous version.
+	newEnc := errbase.EncodeError(context.Background(), markers.Mark(errors.New("marked"), ref))
+	tt.CheckDeepEqual(newEnc.GetWrapper().Details, enc.GetWrapper().Details)
+
+	// Locally, the causes of the multi-errors are compared.
+	other := fmt.Errorf("%w %w", errors.New("hello"), pkgErr.New("world"))
+	marked := markers.Mark(errors.New("marked"), ref)
+	tt.Check(markers.Is(marked, ref))
+	tt.Check(!markers.Is(marked, other))
+	// After a network transfer, they are not.
+	tt.Check(markers.Is(network(marked), ref))
+	tt.Check(markers.Is(network(marked), other))
+}
+
 // This check verifies that IsAny() works.
 func TestIsAny(t *testing.T) {
 	tt := testutils.T{T: t}
@@ -450,6 +534,52 @@ func TestHasType(t *testing.T) {
 	tt.Check(!markers.HasType(nil, nil))
 }
 
+func TestHasAnyType(t *testing.T) {
+	tt := testutils.T{T: t}
+	base := &testError{msg: "hmm"}
+	wrapped := pkgErr.Wrap(base, "boom")
+
+	// One of the references matches.
+	tt.Check(markers.HasAnyType(base, errors.New("woo"), (*testError)(nil)))
+	tt.Check(markers.HasAnyType(wrapped, (*testError)(nil), errors.New("woo")))
+	tt.Check(markers.HasAnyType(wrapped, nil, (*testError)(nil)))
+
+	// None of the references match.
+	tt.Check(!markers.HasAnyType(base, errors.New("woo"), &net.OpError{}))
+	tt.Check(!markers.HasAnyType(wrapped))
+
+	// nil references never match, and nil errors don't contain any
+	// types.
+	tt.Check(!markers.HasAnyType(wrapped, nil, nil))
+	tt.Check(!markers.HasAnyType(nil, nil, (*testError)(nil)))
+}
+
+// This test demonstrates that IsType() compares the types of the
+// errors irrespective of their messages.
+func TestIsType(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err1 := pkgErr.Wrap(&testError{msg: "row 123 not found"}, "lookup")
+	err2 := pkgErr.Wrap(&testError{msg: "row 456 not found"}, "lookup")
+
+	tt.Check(!markers.Is(err1, err2))
+	tt.Check(markers.IsType(err1, err2))
+	tt.Check(markers.IsType(network(err1), err2))
+
+	// The causes of the error are considered, like in Is().
+	tt.Check(markers.IsType(fmt.Errorf("outer: %w", err1), err2))
+
+	// The entire chain of types must match, not just the outermost layer.
+	err3 := pkgErr.Wrap(errors.New("row 789 not found"), "lookup")
+	tt.Check(!markers.IsType(err1, err3))
+	tt.Check(!markers.IsType(&testError{msg: "row 456 not found"}, err1))
+	tt.Check(markers.IsType(err1, &testError{msg: "row 456 not found"}))
+
+	tt.Check(markers.IsType(nil, nil))
+	tt.Check(!markers.IsType(err1, nil))
+	tt.Check(!markers.IsType(nil, err1))
+}
+
 type testErrorInterface interface {
 	foo()
 }
@@ -38,6 +38,14 @@ func Is(err, reference error) bool { return markers.Is(err, reference) }
 // matches that of referenceType.
 func HasType(err, referenceType error) bool { return markers.HasType(err, referenceType) }
 
+// HasAnyType is like HasType except that multiple reference types
+// are compared. It returns true iff err contains an error whose
+// concrete type matches that of any of referenceTypes. Nil references
+// never match.
+func HasAnyType(err error, referenceTypes ...error) bool {
+	return markers.HasAnyType(err, referenceTypes...)
+}
+
 // HasInterface returns true if err contains an error which implements the
 // interface pointed to by referenceInterface. The type of referenceInterface
 // must be a pointer to an interface type. If referenceInterface is not a
@@ -63,9 +71,39 @@ func If(err error, pred func(err error) (interface{}, bool)) (interface{}, bool)
 // RegisterTypeMigration() was called prior to IsAny().
 func IsAny(err error, references ...error) bool { return markers.IsAny(err, references...) }
 
+// IsIgnoring is like Is, except that the layers whose error type
+// family name, as reported by errbase.GetTypeMark(), is one of
+// ignoreFamilies are skipped when comparing the error marks. This
+// makes it possible to compare errors irrespective of annotations
+// that otherwise break equivalence, such as error domains.
+//
+// Note that the annotations are only skipped when computing the
+// marks: an ignored layer is not a valid reference by itself.
+func IsIgnoring(err, reference error, ignoreFamilies ...string) bool {
+	return markers.IsIgnoring(err, reference, ignoreFamilies...)
+}
+
+// IsType is like Is, except that only the types of the error marks
+// are compared, and not their messages: it returns true if the error
+// or one of its causes has the same sequence of error types as the
+// reference, including the types of all the causes. This makes it
+// possible to classify errors whose message varies, e.g. because it
+// contains identifiers, by their structure.
+//
+// Note: if any of the error types has been migrated from a previous
+// package location or a different type, ensure that
+// RegisterTypeMigration() was called prior to IsType().
+func IsType(err, reference error) bool { return markers.IsType(err, reference) }
+
 // Mark creates an explicit mark for the given error, using
 // the same mark as some reference error.
 //
+// The mark is encoded like in previous versions of the library, so
+// that it matches the reference on nodes running these versions. The
+// encoded mark does not include the causes of the multi-errors in the
+// reference: after a network transfer, the mark matches the errors
+// that differ from the reference only in these causes.
+//
 // Note: if any of the error types has been migrated from a previous
 // package location or a different type, ensure that
 // RegisterTypeMigration() was called prior to Mark().
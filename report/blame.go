@@ -0,0 +1,114 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package report
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/withstack"
+	sentry "github.com/getsentry/sentry-go"
+)
+
+// BlameLine returns a single "file:line function" line identifying
+// the code most likely responsible for the error: the application
+// frame closest to the origin of the error in the innermost stack
+// trace of its direct causal chain. Frames from the Go standard
+// library, from this library and from github.com/pkg/errors are
+// skipped, except for those in test files.
+//
+// The empty string is returned if the error has no stack trace or
+// no application frame was found.
+func BlameLine(err error) string {
+	var st *withstack.ReportableStackTrace
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		if s := withstack.GetReportableStackTrace(c); s != nil {
+			st = s
+		}
+	}
+	if st == nil {
+		return ""
+	}
+	// The frames are ordered with the oldest first.
+	for i := len(st.Frames) - 1; i >= 0; i-- {
+		f := &st.Frames[i]
+		if isLibraryFrame(f) {
+			continue
+		}
+		return fmt.Sprintf("%s:%d %s", filepath.Base(f.AbsPath), f.Lineno, f.Function)
+	}
+	return ""
+}
+
+// isLibraryFrame returns true if the given frame belongs to the Go
+// standard library, this library or github.com/pkg/errors. The
+// frames in test files are considered to belong to the application.
+func isLibraryFrame(f *sentry.Frame) bool {
+	if strings.HasSuffix(f.AbsPath, "_test.go") {
+		return false
+	}
+	// Frames without a source file cannot be blamed.
+	if f.AbsPath == "" || f.AbsPath == "unknown" {
+		return true
+	}
+	// The standard library is recognized by its source directory, as
+	// the import paths of the application may have no dot either,
+	// e.g. for package main.
+	if goSrcRoot != "" && strings.HasPrefix(f.AbsPath, goSrcRoot+string(filepath.Separator)) {
+		return true
+	}
+	for _, lib := range libraryPackages {
+		if f.Module == lib || strings.HasPrefix(f.Module, lib+"/") {
+			return true
+		}
+	}
+	// The library may also be vendored or replaced under another
+	// import path: recognize it by its source directory.
+	return strings.HasPrefix(f.AbsPath, libRoot+string(filepath.Separator))
+}
+
+// libraryPackages are the package path prefixes of the error
+// libraries.
+var libraryPackages = []string{
+	"github.com/cockroachdb/errors",
+	"github.com/pkg/errors",
+}
+
+// libRoot is the source directory of this library.
+var libRoot = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Clean(filepath.Join(filepath.Dir(file), ".."))
+}()
+
+// goSrcRoot is the source directory of the Go standard library, as
+// it appears in the stack traces. It is derived from the location of
+// a function of the runtime package. It is empty if the binary was
+// built without full source paths.
+var goSrcRoot = func() string {
+	fn := runtime.FuncForPC(reflect.ValueOf(runtime.Gosched).Pointer())
+	if fn == nil {
+		return ""
+	}
+	file, _ := fn.FileLine(fn.Entry())
+	if !filepath.IsAbs(file) {
+		return ""
+	}
+	// The file is in the "runtime" directory of the source root.
+	return filepath.Clean(filepath.Dir(filepath.Dir(file)))
+}()
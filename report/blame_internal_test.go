@@ -0,0 +1,50 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package report
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/errors/testutils"
+	sentry "github.com/getsentry/sentry-go"
+)
+
+func TestIsLibraryFrame(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	for _, tc := range []struct {
+		f   sentry.Frame
+		lib bool
+	}{
+		// The application, including package main and the modules
+		// whose path has no dot.
+		{sentry.Frame{Module: "main", AbsPath: "/home/user/myapp/main.go"}, false},
+		{sentry.Frame{Module: "myapp/server", AbsPath: "/home/user/myapp/server/server.go"}, false},
+		{sentry.Frame{Module: "example.com/app", AbsPath: "/home/user/app/app.go"}, false},
+		// The standard library.
+		{sentry.Frame{Module: "runtime", AbsPath: goSrcRoot + "/runtime/proc.go"}, true},
+		{sentry.Frame{Module: "net/http", AbsPath: goSrcRoot + "/net/http/server.go"}, true},
+		// The error libraries.
+		{sentry.Frame{Module: "github.com/pkg/errors", AbsPath: "/go/pkg/mod/github.com/pkg/errors@v0.9.1/errors.go"}, true},
+		{sentry.Frame{Module: "vendored/errors/errutil", AbsPath: libRoot + "/errutil/utilities.go"}, true},
+		// Frames without a source file.
+		{sentry.Frame{Module: "unknown", AbsPath: "unknown"}, true},
+		// Test files are part of the application.
+		{sentry.Frame{Module: "github.com/cockroachdb/errors/report", AbsPath: libRoot + "/report/blame_test.go"}, false},
+	} {
+		tt.CheckEqual(isLibraryFrame(&tc.f), tc.lib)
+	}
+	tt.Check(goSrcRoot != "")
+}
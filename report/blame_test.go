@@ -0,0 +1,49 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package report_test
+
+import (
+	goErr "errors"
+	"testing"
+
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/report"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/withstack"
+)
+
+func TestBlameLine(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.CheckStringEqual(report.BlameLine(nil), "")
+	tt.CheckStringEqual(report.BlameLine(goErr.New("hello")), "")
+
+	// The blame line points at the test function, not at the
+	// frames of the testing package or the runtime above it.
+	err := errutil.New("hello")
+	tt.CheckRegexpEqual(report.BlameLine(err), `^blame_test\.go:\d+ TestBlameLine$`)
+
+	// The innermost stack trace is used.
+	err = errutil.Wrap(makeErr(), "woo")
+	tt.CheckRegexpEqual(report.BlameLine(err), `^blame_test\.go:\d+ makeErr$`)
+
+	// Frames from the errors library are skipped.
+	err = withstack.WithStackDepth(goErr.New("hello"), -1)
+	tt.CheckRegexpEqual(report.BlameLine(err), `^blame_test\.go:\d+ TestBlameLine$`)
+}
+
+func makeErr() error {
+	return errutil.New("hello")
+}
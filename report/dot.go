@@ -0,0 +1,90 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+)
+
+// maxDOTMessageBytes is the maximum length of the message shown in
+// each node of the graph produced by FormatDOT.
+const maxDOTMessageBytes = 40
+
+// FormatDOT renders the tree of layers of an error as a graph in the
+// Graphviz DOT language. This is meant to help visualize complex
+// errors, for example multi-errors with nested branches.
+//
+// Each node is an error layer, labeled with its type and the first
+// line of its message, with the unsafe parts redacted. Edges go from
+// each layer to its cause(s), including all the branches of
+// multi-errors.
+func FormatDOT(err error) string {
+	var buf strings.Builder
+	buf.WriteString("digraph error {\n")
+	if err != nil {
+		next := 0
+		formatDOTNode(&buf, err, &next)
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// formatDOTNode prints the node for err and the subgraph of its
+// causes. Nodes are numbered in depth-first order.
+func formatDOTNode(buf *strings.Builder, err error, next *int) {
+	id := *next
+	*next++
+
+	mark := errbase.GetTypeMark(err)
+	label := lastPathComponent(mark.FamilyName)
+	if mark.Extension != "" {
+		label += " (" + mark.Extension + ")"
+	}
+	label += "\n" + shortDOTMessage(err)
+	fmt.Fprintf(buf, "  n%d [label=%s];\n", id, strconv.Quote(label))
+
+	causes := errbase.UnwrapMulti(err)
+	if cause := errbase.UnwrapOnce(err); cause != nil {
+		causes = []error{cause}
+	}
+	for _, cause := range causes {
+		// The cause's node gets the next ID.
+		fmt.Fprintf(buf, "  n%d -> n%d;\n", id, *next)
+		formatDOTNode(buf, cause, next)
+	}
+}
+
+// shortDOTMessage returns the first line of the redacted message of
+// err, truncated to maxDOTMessageBytes.
+func shortDOTMessage(err error) string {
+	msg := redact.Sprint(err).Redact().StripMarkers()
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		msg = msg[:i]
+	}
+	if len(msg) > maxDOTMessageBytes {
+		cut := maxDOTMessageBytes
+		for cut > 0 && !utf8.RuneStart(msg[cut]) {
+			cut--
+		}
+		msg = msg[:cut] + "…"
+	}
+	return msg
+}
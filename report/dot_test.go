@@ -0,0 +1,66 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package report_test
+
+import (
+	goErr "errors"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/join"
+	"github.com/cockroachdb/errors/report"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+func TestFormatDOT(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.CheckStringEqual(report.FormatDOT(nil), "digraph error {\n}\n")
+
+	err := join.Join(
+		errutil.Wrap(errutil.Newf("hello %s", "world"), "woo"),
+		goErr.New("secret"),
+	)
+	dot := report.FormatDOT(err)
+
+	// The join, the stack and prefix from Wrap, the stack and leaf
+	// from Newf, and the second branch.
+	tt.CheckEqual(strings.Count(dot, "[label="), 6)
+	tt.CheckEqual(strings.Count(dot, " -> "), 5)
+	// Both branches of the multi-error are connected to it.
+	tt.Check(strings.Contains(dot, "n0 -> n1;\n"))
+	tt.Check(strings.Contains(dot, "n0 -> n5;\n"))
+
+	tt.CheckStringEqual(dot, `digraph error {
+  n0 [label="*join.joinError\nwoo: hello ×"];
+  n0 -> n1;
+  n1 [label="*withstack.withStack\nwoo: hello ×"];
+  n1 -> n2;
+  n2 [label="*errutil.withPrefix\nwoo: hello ×"];
+  n2 -> n3;
+  n3 [label="*withstack.withStack\nhello ×"];
+  n3 -> n4;
+  n4 [label="*errutil.leafError\nhello ×"];
+  n0 -> n5;
+  n5 [label="*errors.errorString\n×"];
+}
+`)
+
+	// Long messages are truncated, and only the first line is shown.
+	dot = report.FormatDOT(errutil.New(strings.Repeat("a", 50) + "\nsecond line"))
+	tt.Check(strings.Contains(dot, `\n`+strings.Repeat("a", 40)+`…"]`))
+	tt.Check(!strings.Contains(dot, "second"))
+}
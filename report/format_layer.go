@@ -0,0 +1,90 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errorspb"
+	"github.com/cockroachdb/redact"
+)
+
+// FormatLayer renders a single layer of an error: the first one, in
+// the order of errbase.WalkWithMarks(), whose error family is the
+// given family, for example
+// "github.com/cockroachdb/errors/withstack/*withstack.withStack".
+// It returns false if there is no such layer.
+//
+// The result is the verbose rendering of the layer alone, as it
+// would appear in the output of %+v, with its message, its details,
+// and its complete stack trace if it has one. Like the rest of the
+// report, the unsafe parts are redacted. For example:
+//
+//	attached stack trace
+//	  -- stack trace:
+//	  | main.foo
+//	  | 	main.go:42
+//	  | ...
+func FormatLayer(err error, family string) (string, bool) {
+	var layer error
+	errbase.WalkWithMarks(err, func(l error, mark errorspb.ErrorTypeMark, _ bool) {
+		if layer == nil && mark.FamilyName == family {
+			layer = l
+		}
+	})
+	if layer == nil {
+		return "", false
+	}
+
+	var buf strings.Builder
+	buf.WriteString(layerEntry(redact.Sprintf("%+v", layer).Redact().StripMarkers()))
+	writeStackTrace(&buf, layer)
+	return buf.String(), true
+}
+
+// writeStackTrace writes the complete stack trace of the given layer
+// if it has one. The stack trace is printed separately from the
+// entry of the layer, because the rendering of the layer within its
+// chain may elide the part it shares with its causes.
+func writeStackTrace(buf *strings.Builder, layer error) {
+	if st, ok := layer.(errbase.StackTraceProvider); ok && len(st.StackTrace()) > 0 {
+		buf.WriteString("\n  -- stack trace:")
+		buf.WriteString(strings.ReplaceAll(fmt.Sprintf("%+v", st.StackTrace()), "\n", "\n  | "))
+	}
+}
+
+// layerEntry extracts the entry of the outermost layer from the
+// verbose rendering of an error, without its number and without its
+// stack trace. The entry starts at the line "(1) ..." and continues
+// with the detail lines prefixed by "  |".
+func layerEntry(verbose string) string {
+	lines := strings.Split(verbose, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "(1)") {
+			continue
+		}
+		entry := []string{strings.TrimPrefix(strings.TrimPrefix(line, "(1)"), " ")}
+		for _, l := range lines[i+1:] {
+			if !strings.HasPrefix(l, "  |") {
+				break
+			}
+			entry = append(entry, l)
+		}
+		return strings.Join(entry, "\n")
+	}
+	return ""
+}
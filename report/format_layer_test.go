@@ -0,0 +1,67 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package report_test
+
+import (
+	goErr "errors"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/domains"
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/hintdetail"
+	"github.com/cockroachdb/errors/report"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/withstack"
+)
+
+func TestFormatLayer(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := errutil.Newf("hello %s", "world")
+	err = hintdetail.WithHint(err, "try again")
+	err = domains.WithDomain(err, "mydomain")
+	err = errutil.Wrap(err, "woo")
+
+	_, ok := report.FormatLayer(err, "some/other/*pkg.type")
+	tt.Check(!ok)
+	_, ok = report.FormatLayer(nil, "some/other/*pkg.type")
+	tt.Check(!ok)
+
+	// The first stack trace layer is the outermost one. Its
+	// stack trace is printed in full, even though it is elided in the
+	// rendering of the entire error.
+	stackFamily := errbase.GetTypeMark(withstack.WithStack(goErr.New(""))).FamilyName
+	s, ok := report.FormatLayer(err, stackFamily)
+	tt.Check(ok)
+	tt.Check(strings.HasPrefix(s, "attached stack trace\n  -- stack trace:\n  | github.com/cockroachdb/errors/report_test.TestFormatLayer\n"))
+	tt.Check(!strings.Contains(s, "repeated from below"))
+	tt.CheckContains(s, "\n  | testing.tRunner\n")
+
+	// Other layers are printed without the layers around them.
+	s, ok = report.FormatLayer(err, errbase.GetTypeMark(domains.WithDomain(goErr.New(""), "")).FamilyName)
+	tt.Check(ok)
+	tt.CheckStringEqual(s, "mydomain")
+
+	s, ok = report.FormatLayer(err, errbase.GetTypeMark(errutil.WithMessage(goErr.New(""), "")).FamilyName)
+	tt.Check(ok)
+	tt.CheckStringEqual(s, "woo")
+
+	// Unsafe parts are redacted.
+	s, ok = report.FormatLayer(err, errbase.GetTypeMark(errbase.UnwrapAll(errutil.New(""))).FamilyName)
+	tt.Check(ok)
+	tt.CheckStringEqual(s, "hello ×")
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/redact"
+)
+
+// FormatLogfmt renders the fields produced by errutil.LogFields() in
+// the logfmt format, for example:
+//
+//	error="woo: ×" error.type=... error.grpc_code=NotFound error.stack="..."
+//
+// Like in errutil.LogFields(), the unsafe parts are redacted. The
+// redaction markers are then removed, like in FormatYAML(), and the
+// leading newline of the stack trace is trimmed. The values which
+// contain spaces, quotes, equal signs or control characters, e.g.
+// the newlines of a stack trace, are quoted and escaped. The fields
+// with an empty value are omitted. The empty string is returned for
+// a nil error.
+func FormatLogfmt(err error) string {
+	var buf strings.Builder
+	for _, f := range errutil.LogFields(err) {
+		v := string(redact.RedactableString(fmt.Sprint(f.Value)).StripMarkers())
+		if f.Key == "error.stack" {
+			v = strings.TrimPrefix(v, "\n")
+		}
+		if v == "" {
+			continue
+		}
+		if buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(f.Key)
+		buf.WriteByte('=')
+		if needsLogfmtQuoting(v) {
+			buf.WriteString(strconv.Quote(v))
+		} else {
+			buf.WriteString(v)
+		}
+	}
+	return buf.String()
+}
+
+// needsLogfmtQuoting returns true if the value must be quoted to be
+// parsed back as a single logfmt value.
+func needsLogfmtQuoting(v string) bool {
+	for _, r := range v {
+		if r == '"' || r == '=' || r == '\\' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package report_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/contexttags"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/extgrpc"
+	"github.com/cockroachdb/errors/exthttp"
+	"github.com/cockroachdb/errors/report"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/logtags"
+	"google.golang.org/grpc/codes"
+)
+
+// parseLogfmt splits a logfmt line into its key/value pairs. It
+// returns false if the line is not well-formed.
+func parseLogfmt(s string) (keys, values []string, ok bool) {
+	for s != "" {
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 || strings.ContainsAny(s[:eq], " \"") {
+			return nil, nil, false
+		}
+		keys = append(keys, s[:eq])
+		s = s[eq+1:]
+		var v string
+		if strings.HasPrefix(s, `"`) {
+			q, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, nil, false
+			}
+			if v, err = strconv.Unquote(q); err != nil {
+				return nil, nil, false
+			}
+			s = s[len(q):]
+		} else {
+			end := strings.IndexByte(s, ' ')
+			if end < 0 {
+				end = len(s)
+			}
+			v, s = s[:end], s[end:]
+		}
+		values = append(values, v)
+		if s != "" {
+			if s[0] != ' ' {
+				return nil, nil, false
+			}
+			s = s[1:]
+		}
+	}
+	return keys, values, true
+}
+
+func TestFormatLogfmt(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.CheckStringEqual(report.FormatLogfmt(nil), "")
+
+	ctx := logtags.AddTag(context.Background(), "user", "alice")
+	// The tag without a value produces an empty field, which is omitted.
+	ctx = logtags.AddTag(ctx, "flag", nil)
+	err := errutil.Newf(`cannot "open" %s`, "secret.txt")
+	err = extgrpc.WrapWithGrpcCode(err, codes.NotFound)
+	err = exthttp.WrapWithHTTPCode(err, 404)
+	err = contexttags.WithContextTags(err, ctx)
+
+	s := report.FormatLogfmt(err)
+	tt.Check(!strings.Contains(s, "\n"))
+	tt.Check(!strings.Contains(s, "secret.txt"))
+	tt.Check(!strings.Contains(s, "alice"))
+
+	keys, values, ok := parseLogfmt(s)
+	tt.Check(ok)
+	tt.CheckDeepEqual(keys, []string{
+		"error", "error.type", "error.grpc_code", "error.http_code", "error.stack", "error.tag.user",
+	})
+	if len(values) == len(keys) {
+		tt.CheckStringEqual(values[0], `cannot "open" ×`)
+		tt.CheckStringEqual(values[1], "github.com/cockroachdb/errors/contexttags/*contexttags.withContext")
+		tt.CheckStringEqual(values[2], "NotFound")
+		tt.CheckStringEqual(values[3], "404")
+		tt.Check(strings.HasPrefix(values[4], "github.com/cockroachdb/errors/report_test.TestFormatLogfmt\n"))
+		tt.CheckStringEqual(values[5], "×")
+	}
+	tt.Check(strings.HasPrefix(s, `error="cannot \"open\" ×" error.type=github.com/cockroachdb/errors/contexttags/*contexttags.withContext error.grpc_code=NotFound error.http_code=404 error.stack="github.com/`))
+	tt.Check(strings.HasSuffix(s, " error.tag.user=×"))
+	// The redaction markers are removed.
+	tt.Check(!strings.ContainsAny(s, "‹›"))
+}
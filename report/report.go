@@ -17,9 +17,11 @@ package report
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/cockroachdb/errors/domains"
 	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
 	"github.com/cockroachdb/errors/withstack"
 	"github.com/cockroachdb/redact"
 	sentry "github.com/getsentry/sentry-go"
@@ -97,6 +99,9 @@ import (
 // is included in the Sentry report. This does not affect error types
 // provided by the library, but could impact error types defined by
 // 3rd parties. This limitation may be lifted in a later version.
+//
+// The report is built even if the error belongs to a family configured
+// with SetBenignFamilies(): such errors are skipped by ReportError().
 func BuildSentryReport(err error) (event *sentry.Event, extraDetails map[string]interface{}) {
 	if err == nil {
 		// No error: do nothing.
@@ -111,6 +116,10 @@ func BuildSentryReport(err error) (event *sentry.Event, extraDetails map[string]
 		stacks = append(stacks, st)
 
 		sd := errbase.GetSafeDetails(c)
+		if !includeBarrierDetails && errbase.IsMaskingLayer(c) {
+			// Only keep the type of the barrier.
+			sd.SafeDetails = nil
+		}
 		details = append(details, sd)
 	})
 	module := string(domains.GetDomain(err))
@@ -128,16 +137,21 @@ func BuildSentryReport(err error) (event *sentry.Event, extraDetails map[string]
 		fmt.Fprintf(&longMsgBuf, "%s:%d: ", f, l)
 	}
 	// Include the verbose error printout, with sensitive bits redacted out.
-	verboseErr := redact.Sprintf("%+v", err).Redact().StripMarkers()
+	var verboseErr string
+	if includeBarrierDetails {
+		verboseErr = redact.Sprintf("%+v", err).Redact().StripMarkers()
+	} else {
+		verboseErr = redact.Sprintf("%+v", withoutMaskedErrors{err}).Redact().StripMarkers()
+	}
 	if verboseErr != redactedMarker {
 		idx := strings.IndexByte(verboseErr, '\n')
 		if idx == -1 {
-			firstDetailLine = verboseErr
+			firstDetailLine = truncateDetail(verboseErr)
 		} else {
-			firstDetailLine = verboseErr[:idx]
+			firstDetailLine = truncateDetail(verboseErr[:idx])
 		}
 	}
-	fmt.Fprint(&longMsgBuf, verboseErr)
+	fmt.Fprint(&longMsgBuf, truncateLines(verboseErr))
 
 	// sep is used to separate the entries in the longMsgBuf / Message
 	// payload.
@@ -268,6 +282,7 @@ func BuildSentryReport(err error) (event *sentry.Event, extraDetails map[string]
 				if j := strings.IndexByte(d, '\n'); j >= 0 {
 					d = d[:j]
 				}
+				d = truncateDetail(d)
 				if d != "" {
 					longMsgBuf.WriteString(": ")
 					longMsgBuf.WriteString(d)
@@ -286,7 +301,7 @@ func BuildSentryReport(err error) (event *sentry.Event, extraDetails map[string]
 	}
 
 	// Produce the full error type description.
-	extras["error types"] = typesBuf.String()
+	extras["error types"] = truncateDetail(typesBuf.String())
 
 	// Sentry is mightily annoying.
 	reverseExceptionOrder(exceptions)
@@ -296,6 +311,12 @@ func BuildSentryReport(err error) (event *sentry.Event, extraDetails map[string]
 	event.Message = longMsgBuf.String()
 	event.Exception = exceptions
 
+	// Honor the fingerprint attached with WithSentryFingerprint() or
+	// the grouping key attached with WithFingerprint(), if any.
+	if components, ok := errutil.GetSentryEventFingerprint(err); ok {
+		event.Fingerprint = components
+	}
+
 	// If there is no exception payload, synthesize one.
 	if len(event.Exception) == 0 {
 		// We know we don't have a stack trace to extract line/function
@@ -353,6 +374,118 @@ func BuildSentryReport(err error) (event *sentry.Event, extraDetails map[string]
 
 var redactedMarker = redact.RedactableString(redact.RedactedMarker()).StripMarkers()
 
+// maxDetailBytes can be configured using SetMaxDetailBytes() below.
+var maxDetailBytes int
+
+// SetMaxDetailBytes configures BuildSentryReport() to truncate the
+// detail strings included in reports to n bytes, to protect the
+// reporting pipeline from oversized payloads. This applies to each
+// line of the verbose printout of the error, to the safe details
+// included in the report composition, and to the extra data
+// payloads. Truncated strings end with "…(truncated)". The error
+// objects themselves are not modified.
+//
+// The default, zero, disables truncation. This should be called
+// during initialization, before any report is built.
+func SetMaxDetailBytes(n int) {
+	maxDetailBytes = n
+}
+
+// truncatedMarker is appended to truncated detail strings.
+const truncatedMarker = "…(truncated)"
+
+// truncateDetail truncates s to maxDetailBytes bytes, if configured.
+func truncateDetail(s string) string {
+	if maxDetailBytes <= 0 || len(s) <= maxDetailBytes {
+		return s
+	}
+	n := maxDetailBytes
+	// Avoid cutting a multi-byte character in the middle.
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + truncatedMarker
+}
+
+// truncateLines applies truncateDetail to each line of s.
+func truncateLines(s string) string {
+	if maxDetailBytes <= 0 {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = truncateDetail(l)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// includeBarrierDetails can be configured using
+// SetIncludeBarrierDetails() below.
+var includeBarrierDetails = true
+
+// SetIncludeBarrierDetails configures whether BuildSentryReport()
+// includes the details of the errors hidden behind barriers, i.e.
+// their safe details and the "cause hidden behind barrier" block of
+// the verbose printout. When disabled, barriers only contribute
+// their type and message to reports, for environments where even
+// the safe details of handled errors must not be reported.
+//
+// The default is true. This should be called during initialization,
+// before any report is built.
+func SetIncludeBarrierDetails(include bool) {
+	includeBarrierDetails = include
+}
+
+// withoutMaskedErrors formats an error with the details of the
+// layers that conceal another error, e.g. barriers, omitted.
+type withoutMaskedErrors struct {
+	err error
+}
+
+// SafeFormat implements redact.SafeFormatter.
+func (e withoutMaskedErrors) SafeFormat(s redact.SafePrinter, verb rune) {
+	errbase.FormatRedactableErrorWithOptions(e.err, s, verb, errbase.FormatHideMaskedErrors())
+}
+
+// benignFamilies can be configured using SetBenignFamilies() below.
+var benignFamilies map[string]struct{}
+
+// SetBenignFamilies configures ReportError() to skip the errors that
+// contain a layer, at any depth, whose error family is one of the
+// given families. The family of an error type is the FamilyName of
+// its error type mark, as reported by errbase.GetTypeMark(), for
+// example "github.com/cockroachdb/errors/errutil/*errutil.leafError".
+// BuildSentryReport() still builds a report for such errors.
+//
+// Each call replaces the previous configuration. Calling it without
+// arguments reports all errors again, which is the default. This
+// should be called during initialization, before any error is
+// reported.
+func SetBenignFamilies(families ...string) {
+	if len(families) == 0 {
+		benignFamilies = nil
+		return
+	}
+	benignFamilies = make(map[string]struct{}, len(families))
+	for _, f := range families {
+		benignFamilies[f] = struct{}{}
+	}
+}
+
+// isBenign returns true iff one of the layers of err belongs to a
+// family configured with SetBenignFamilies().
+func isBenign(err error) (benign bool) {
+	if len(benignFamilies) == 0 {
+		return false
+	}
+	visitAllMulti(err, func(c error) {
+		if _, ok := benignFamilies[errbase.GetTypeMark(c).FamilyName]; ok {
+			benign = true
+		}
+	})
+	return benign
+}
+
 // ReportError reports the given error to Sentry. The caller is responsible for
 // checking whether telemetry is enabled, and calling the sentry.Flush()
 // function to wait for the report to be uploaded. (By default,
@@ -361,9 +494,21 @@ var redactedMarker = redact.RedactableString(redact.RedactedMarker()).StripMarke
 // Note: an empty 'eventID' can be returned which signifies that the error was
 // not reported. This can occur when Sentry client hasn't been properly
 // configured or Sentry client decided to not report the error (due to
-// configured sampling rate, callbacks, Sentry's event processors, etc).
+// configured sampling rate, callbacks, Sentry's event processors, etc),
+// or when the error belongs to a family configured with
+// SetBenignFamilies().
+//
+// The tenant attached with errutil.WithTenant(), if any, is reported as the
+// "tenant" tag.
 func ReportError(err error) (eventID string) {
+	if isBenign(err) {
+		// The operator does not want this error to be reported.
+		return ""
+	}
 	event, extraDetails := BuildSentryReport(err)
+	if event == nil {
+		return ""
+	}
 
 	for extraKey, extraValue := range extraDetails {
 		event.Extra[extraKey] = extraValue
@@ -377,6 +522,9 @@ func ReportError(err error) (eventID string) {
 	tags := map[string]string{
 		"report_type": "error",
 	}
+	if tenantID, ok := errutil.GetTenant(err); ok {
+		tags["tenant"] = tenantID
+	}
 	for key, value := range tags {
 		event.Tags[key] = value
 	}
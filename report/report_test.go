@@ -21,8 +21,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/errors/barriers"
 	"github.com/cockroachdb/errors/domains"
 	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
 	"github.com/cockroachdb/errors/report"
 	"github.com/cockroachdb/errors/safedetails"
 	"github.com/cockroachdb/errors/testutils"
@@ -156,6 +158,134 @@ github.com/cockroachdb/errors/report_test/*report_test.myWrapper (some/previous/
 	tt.Check(hasStack)
 }
 
+func TestSetMaxDetailBytes(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	long := strings.Repeat("x", 1000)
+	err := safedetails.WithSafeDetails(goErr.New("boom"), "%s", safedetails.Safe(long))
+
+	report.SetMaxDetailBytes(100)
+	defer report.SetMaxDetailBytes(0)
+
+	event, extras := report.BuildSentryReport(err)
+	tt.Check(!strings.Contains(event.Message, long))
+	tt.CheckContains(event.Message, strings.Repeat("x", 100)+"…(truncated)")
+	for _, exc := range event.Exception {
+		tt.Check(len(exc.Value) <= 100+len("…(truncated)"))
+	}
+	for _, v := range extras {
+		if s, ok := v.(string); ok {
+			tt.Check(len(s) <= 100+len("…(truncated)"))
+		}
+	}
+
+	// Without a limit, the detail is reported in full.
+	report.SetMaxDetailBytes(0)
+	event, _ = report.BuildSentryReport(err)
+	tt.CheckContains(event.Message, long)
+	tt.Check(!strings.Contains(event.Message, "…(truncated)"))
+}
+
+func TestReportFingerprint(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := goErr.New("hello")
+	event, _ := report.BuildSentryReport(err)
+	tt.Check(event.Fingerprint == nil)
+
+	event, _ = report.BuildSentryReport(errutil.WithFingerprint(err, "mykey"))
+	tt.CheckDeepEqual(event.Fingerprint, []string{"mykey"})
+
+	// The outermost of the components attached with
+	// WithSentryFingerprint and the grouping key wins.
+	event, _ = report.BuildSentryReport(
+		errutil.WithSentryFingerprint(errutil.WithFingerprint(err, "mykey"), "{{ default }}", "a"))
+	tt.CheckDeepEqual(event.Fingerprint, []string{"{{ default }}", "a"})
+	event, _ = report.BuildSentryReport(
+		errutil.WithFingerprint(errutil.WithSentryFingerprint(err, "{{ default }}", "a"), "mykey"))
+	tt.CheckDeepEqual(event.Fingerprint, []string{"mykey"})
+}
+
+func TestSetBenignFamilies(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	var events []*sentry.Event
+	client, err := sentry.NewClient(
+		sentry.ClientOptions{
+			Transport: interceptingTransport{
+				SendFunc: func(event *sentry.Event) {
+					events = append(events, event)
+				},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sentry.CurrentHub().BindClient(client)
+
+	report.SetBenignFamilies(errbase.GetTypeMark(&benignErr{}).FamilyName)
+	defer report.SetBenignFamilies()
+
+	// An error of a benign family is not reported, even when
+	// wrapped.
+	benign := errutil.Wrap(&benignErr{}, "woo")
+	tt.CheckStringEqual(report.ReportError(benign), "")
+
+	// The report can still be built explicitly.
+	event, _ := report.BuildSentryReport(benign)
+	tt.Check(event != nil)
+
+	// The family is also recognized in a branch of a multi-cause
+	// error.
+	multi := errutil.JoinWithDepth(0, goErr.New("hello"), &benignErr{})
+	tt.CheckStringEqual(report.ReportError(multi), "")
+	tt.CheckEqual(len(events), 0)
+
+	// Other errors are still reported.
+	tt.Check(report.ReportError(goErr.New("hello")) != "")
+	tt.CheckEqual(len(events), 1)
+
+	// Without configuration, all errors are reported.
+	report.SetBenignFamilies()
+	tt.Check(report.ReportError(benign) != "")
+	tt.CheckEqual(len(events), 2)
+}
+
+func TestReportTenant(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	var events []*sentry.Event
+	client, err := sentry.NewClient(
+		sentry.ClientOptions{
+			Transport: interceptingTransport{
+				SendFunc: func(event *sentry.Event) {
+					events = append(events, event)
+				},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sentry.CurrentHub().BindClient(client)
+
+	// The tenant is promoted to a tag.
+	tt.Check(report.ReportError(errutil.WithTenant(goErr.New("hello"), "t1")) != "")
+	tt.CheckEqual(len(events), 1)
+	tt.CheckStringEqual(events[0].Tags["tenant"], "t1")
+
+	// Without tenant, there is no tag.
+	tt.Check(report.ReportError(goErr.New("hello")) != "")
+	tt.CheckEqual(len(events), 2)
+	_, ok := events[1].Tags["tenant"]
+	tt.Check(!ok)
+}
+
+type benignErr struct{}
+
+func (*benignErr) Error() string { return "benign" }
+
 func wrapWithMigratedType(err error) error {
 	errbase.RegisterTypeMigration("some/previous/path", "prevpkg.prevType", (*myWrapper)(nil))
 	return &myWrapper{cause: err}
@@ -191,3 +321,40 @@ func (it interceptingTransport) Configure(sentry.ClientOptions) {
 func (it interceptingTransport) SendEvent(event *sentry.Event) {
 	it.SendFunc(event)
 }
+
+func TestSetIncludeBarrierDetails(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	inner := safedetails.WithSafeDetails(
+		errutil.Newf("inner %s", safedetails.Safe("safe-inner-msg")),
+		"%s", safedetails.Safe("safe-inner-detail"))
+	err := errutil.Wrap(barriers.HandledWithMessagef(inner, "handled"), "outer")
+
+	checkReport := func(include bool) {
+		event, extras := report.BuildSentryReport(err)
+		var payload strings.Builder
+		payload.WriteString(event.Message)
+		for _, exc := range event.Exception {
+			fmt.Fprintf(&payload, "\n%s\n%s", exc.Type, exc.Value)
+		}
+		for _, v := range extras {
+			fmt.Fprintf(&payload, "\n%v", v)
+		}
+		p := payload.String()
+
+		// The barrier's own message and type are always reported.
+		tt.CheckContains(p, "outer: handled")
+		tt.CheckContains(p, "*barriers.barrierErr")
+
+		for _, s := range []string{"cause hidden behind barrier", "safe-inner-msg", "safe-inner-detail"} {
+			tt.CheckEqual(strings.Contains(p, s), include)
+		}
+	}
+
+	// By default, the details of the hidden error are reported.
+	checkReport(true)
+
+	report.SetIncludeBarrierDetails(false)
+	defer report.SetIncludeBarrierDetails(true)
+	checkReport(false)
+}
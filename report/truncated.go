@@ -0,0 +1,82 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errorspb"
+)
+
+// FormatTruncated renders an error for display in a limited space.
+// The outermost maxLayers layers, in the order of
+// errbase.WalkWithMarks(), are rendered verbosely as they would
+// appear in the output of %+v. The remaining layers are summarized
+// with a note that surfaces the message of the innermost one. For
+// example:
+//
+//	(1) woo
+//	(2) attached stack trace
+//	  -- stack trace:
+//	  | main.foo
+//	  | 	main.go:42
+//	  | ...
+//	… and 3 more layers (innermost: hello)
+//
+// Unlike the other functions in this package, FormatTruncated does
+// not redact the unsafe parts of the error.
+func FormatTruncated(err error, maxLayers int) string {
+	if err == nil {
+		return ""
+	}
+	if maxLayers < 0 {
+		maxLayers = 0
+	}
+	var buf strings.Builder
+	var leaf error
+	n := 0
+	errbase.WalkWithMarks(err, func(layer error, _ errorspb.ErrorTypeMark, _ bool) {
+		n++
+		leaf = layer
+		if n > maxLayers {
+			return
+		}
+		if n > 1 {
+			buf.WriteByte('\n')
+		}
+		verbose := fmt.Sprintf("%+v", layer)
+		entry := layerEntry(verbose)
+		if entry == "" {
+			// Simple errors without details are rendered by %+v as
+			// their message alone.
+			entry = verbose
+		}
+		fmt.Fprintf(&buf, "(%d) %s", n, entry)
+		writeStackTrace(&buf, layer)
+	})
+	if omitted := n - maxLayers; omitted > 0 {
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		noun := "layers"
+		if omitted == 1 {
+			noun = "layer"
+		}
+		fmt.Fprintf(&buf, "… and %d more %s (innermost: %s)", omitted, noun, leaf.Error())
+	}
+	return buf.String()
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package report_test
+
+import (
+	goErr "errors"
+	"testing"
+
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/hintdetail"
+	"github.com/cockroachdb/errors/report"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+func TestFormatTruncated(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.CheckStringEqual(report.FormatTruncated(nil, 3), "")
+
+	err := goErr.New("hello")
+	for i := 0; i < 5; i++ {
+		err = errutil.WithMessagef(err, "layer %d", i)
+	}
+	err = hintdetail.WithHint(err, "try again")
+
+	// The outermost layers are rendered verbosely, the others are
+	// summarized.
+	tt.CheckStringEqual(report.FormatTruncated(err, 2), `(1) try again
+(2) layer 4
+… and 5 more layers (innermost: hello)`)
+	tt.CheckStringEqual(report.FormatTruncated(err, 6), `(1) try again
+(2) layer 4
+(3) layer 3
+(4) layer 2
+(5) layer 1
+(6) layer 0
+… and 1 more layer (innermost: hello)`)
+	tt.CheckStringEqual(report.FormatTruncated(err, 0), "… and 7 more layers (innermost: hello)")
+
+	// Layers with details are rendered with them.
+	s := report.FormatTruncated(errutil.Wrap(err, "woo"), 2)
+	tt.CheckContains(s, "(1) attached stack trace\n  -- stack trace:\n  | github.com/cockroachdb/errors/report_test.TestFormatTruncated\n")
+	tt.CheckContains(s, "\n(2) woo\n… and 7 more layers (innermost: hello)")
+
+	// Without truncation, there is no note.
+	tt.CheckStringEqual(report.FormatTruncated(goErr.New("hello"), 1), "(1) hello")
+}
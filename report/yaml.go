@@ -0,0 +1,121 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+)
+
+// FormatYAML renders the tree of layers of an error as a YAML
+// document, for readable dumps and tooling. Each layer is a mapping
+// with the following keys, the last three being omitted when empty:
+//
+//   - type: the type of the layer;
+//   - message: its message, with the unsafe parts redacted;
+//   - details: its safe details, as a list, except for the layers
+//     with a stack trace;
+//   - stack: its stack trace, as a block scalar;
+//   - causes: the layers of its cause(s), including all the branches
+//     of multi-errors, as a list.
+//
+// For example:
+//
+//	type: "*errutil.withPrefix"
+//	message: "woo: hello ×"
+//	causes:
+//	  - type: "*withstack.withStack"
+//	    message: "hello ×"
+//	    stack: |
+//	      main.foo
+//	      	main.go:42
+//	      ...
+//
+// The empty string is returned for a nil error.
+func FormatYAML(err error) string {
+	if err == nil {
+		return ""
+	}
+	var buf strings.Builder
+	formatYAMLNode(&buf, err, "", "")
+	return buf.String()
+}
+
+// formatYAMLNode prints the mapping for err and its causes. The
+// first key is prefixed by first, and the others by indent.
+func formatYAMLNode(buf *strings.Builder, err error, first, indent string) {
+	prefix := first
+	key := func(k string) {
+		buf.WriteString(prefix)
+		buf.WriteString(k)
+		buf.WriteByte(':')
+		prefix = indent
+	}
+
+	mark := errbase.GetTypeMark(err)
+	typ := lastPathComponent(mark.FamilyName)
+	if mark.Extension != "" {
+		typ += " (" + mark.Extension + ")"
+	}
+	key("type")
+	fmt.Fprintf(buf, " %s\n", yamlQuote(typ))
+
+	key("message")
+	fmt.Fprintf(buf, " %s\n", yamlQuote(redact.Sprint(err).Redact().StripMarkers()))
+
+	st, hasStack := err.(errbase.StackTraceProvider)
+	hasStack = hasStack && len(st.StackTrace()) > 0
+
+	// The safe details of the stack trace carriers are their stack
+	// trace, which is rendered separately below.
+	if details := errbase.GetSafeDetails(err).SafeDetails; len(details) > 0 && !hasStack {
+		key("details")
+		buf.WriteByte('\n')
+		for _, d := range details {
+			fmt.Fprintf(buf, "%s  - %s\n", indent, yamlQuote(d))
+		}
+	}
+
+	if hasStack {
+		key("stack")
+		buf.WriteString(" |\n")
+		frames := strings.TrimPrefix(fmt.Sprintf("%+v", st.StackTrace()), "\n")
+		for _, line := range strings.Split(frames, "\n") {
+			fmt.Fprintf(buf, "%s  %s\n", indent, line)
+		}
+	}
+
+	causes := errbase.UnwrapMulti(err)
+	if cause := errbase.UnwrapOnce(err); cause != nil {
+		causes = []error{cause}
+	}
+	if len(causes) > 0 {
+		key("causes")
+		buf.WriteByte('\n')
+		for _, cause := range causes {
+			formatYAMLNode(buf, cause, indent+"  - ", indent+"    ")
+		}
+	}
+}
+
+// yamlQuote renders s as a YAML double-quoted scalar. The escape
+// sequences produced by strconv.Quote are all valid in YAML.
+func yamlQuote(s string) string {
+	return strconv.Quote(s)
+}
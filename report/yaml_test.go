@@ -0,0 +1,71 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package report_test
+
+import (
+	goErr "errors"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/join"
+	"github.com/cockroachdb/errors/report"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/withstack"
+)
+
+func TestFormatYAML(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	tt.CheckStringEqual(report.FormatYAML(nil), "")
+
+	err := join.Join(
+		errutil.WithFingerprint(errutil.WithMessage(goErr.New("hello"), "woo"), "my\"key"),
+		goErr.New("line 1\nline 2"),
+	)
+	tt.CheckStringEqual(report.FormatYAML(err), `type: "*join.joinError"
+message: "woo: ×\n×\n×"
+causes:
+  - type: "*errutil.withFingerprint"
+    message: "woo: ×"
+    details:
+      - "my\"key"
+    causes:
+      - type: "*errutil.withPrefix"
+        message: "woo: ×"
+        details:
+          - "woo"
+        causes:
+          - type: "*errors.errorString"
+            message: "×"
+  - type: "*errors.errorString"
+    message: "×\n×"
+`)
+
+	// Stack traces are rendered as block scalars.
+	y := report.FormatYAML(errutil.WithMessage(withstack.WithStack(goErr.New("hello")), "woo"))
+	tt.Check(strings.HasPrefix(y, `type: "*errutil.withPrefix"
+message: "woo: ×"
+details:
+  - "woo"
+causes:
+  - type: "*withstack.withStack"
+    message: "×"
+    stack: |
+      github.com/cockroachdb/errors/report_test.TestFormatYAML
+      	`))
+	tt.CheckContains(y, "\n      testing.tRunner\n")
+	tt.CheckContains(y, "\n    causes:\n      - type: \"*errors.errorString\"\n")
+}
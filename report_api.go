@@ -87,6 +87,8 @@ import (
 // provided by the library, but could impact error types defined by
 // 3rd parties. This limitation may be lifted in a later version.
 //
+// The report is built even if the error belongs to a family configured
+// with SetBenignFamilies(): such errors are skipped by ReportError().
 func BuildSentryReport(err error) (*sentry.Event, map[string]interface{}) {
 	return report.BuildSentryReport(err)
 }
@@ -99,5 +101,134 @@ func BuildSentryReport(err error) (*sentry.Event, map[string]interface{}) {
 // Note: an empty 'eventID' can be returned which signifies that the error was
 // not reported. This can occur when Sentry client hasn't been properly
 // configured or Sentry client decided to not report the error (due to
-// configured sampling rate, callbacks, Sentry's event processors, etc).
+// configured sampling rate, callbacks, Sentry's event processors, etc),
+// or when the error belongs to a family configured with
+// SetBenignFamilies().
+//
+// The tenant attached with WithTenant(), if any, is reported as the
+// "tenant" tag.
 func ReportError(err error) string { return report.ReportError(err) }
+
+// SetMaxDetailBytes configures BuildSentryReport() to truncate the
+// detail strings included in reports to n bytes, to protect the
+// reporting pipeline from oversized payloads. This applies to each
+// line of the verbose printout of the error, to the safe details
+// included in the report composition, and to the extra data
+// payloads. Truncated strings end with "…(truncated)". The error
+// objects themselves are not modified.
+//
+// The default, zero, disables truncation. This should be called
+// during initialization, before any report is built.
+func SetMaxDetailBytes(n int) { report.SetMaxDetailBytes(n) }
+
+// SetBenignFamilies configures ReportError() to skip the errors that
+// contain a layer, at any depth, whose error family is one of the
+// given families. The family of an error type is the FamilyName of
+// its error type mark, as reported by errbase.GetTypeMark(), for
+// example "github.com/cockroachdb/errors/errutil/*errutil.leafError".
+// BuildSentryReport() still builds a report for such errors.
+//
+// Each call replaces the previous configuration. Calling it without
+// arguments reports all errors again, which is the default. This
+// should be called during initialization, before any error is
+// reported.
+func SetBenignFamilies(families ...string) { report.SetBenignFamilies(families...) }
+
+// SetIncludeBarrierDetails configures whether BuildSentryReport()
+// includes the details of the errors hidden behind barriers, i.e.
+// their safe details and the "cause hidden behind barrier" block of
+// the verbose printout. When disabled, barriers only contribute
+// their type and message to reports, for environments where even
+// the safe details of handled errors must not be reported.
+//
+// The default is true. This should be called during initialization,
+// before any report is built.
+func SetIncludeBarrierDetails(include bool) { report.SetIncludeBarrierDetails(include) }
+
+// BlameLine returns a single "file:line function" line identifying
+// the code most likely responsible for the error: the application
+// frame closest to the origin of the error in the innermost stack
+// trace of its direct causal chain. Frames from the Go standard
+// library, from this library and from github.com/pkg/errors are
+// skipped, except for those in test files.
+//
+// The empty string is returned if the error has no stack trace or
+// no application frame was found.
+func BlameLine(err error) string { return report.BlameLine(err) }
+
+// FormatDOT renders the tree of layers of an error as a graph in the
+// Graphviz DOT language. This is meant to help visualize complex
+// errors, for example multi-errors with nested branches.
+//
+// Each node is an error layer, labeled with its type and the first
+// line of its message, with the unsafe parts redacted. Edges go from
+// each layer to its cause(s), including all the branches of
+// multi-errors.
+func FormatDOT(err error) string { return report.FormatDOT(err) }
+
+// FormatLayer renders a single layer of an error: the first one, in
+// the order of WalkWithMarks(), whose error family is the
+// given family, for example
+// "github.com/cockroachdb/errors/withstack/*withstack.withStack".
+// It returns false if there is no such layer.
+//
+// The result is the verbose rendering of the layer alone, as it
+// would appear in the output of %+v, with its message, its details,
+// and its complete stack trace if it has one. Like the rest of the
+// report, the unsafe parts are redacted. For example:
+//
+//	attached stack trace
+//	  -- stack trace:
+//	  | main.foo
+//	  | 	main.go:42
+//	  | ...
+func FormatLayer(err error, family string) (string, bool) {
+	return report.FormatLayer(err, family)
+}
+
+// FormatTruncated renders an error for display in a limited space.
+// The outermost maxLayers layers, in the order of WalkWithMarks(),
+// are rendered verbosely as they would appear in the output of %+v.
+// The remaining layers are summarized with a note that surfaces the
+// message of the innermost one. For example:
+//
+//	(1) woo
+//	(2) attached stack trace
+//	  -- stack trace:
+//	  | main.foo
+//	  | 	main.go:42
+//	  | ...
+//	… and 3 more layers (innermost: hello)
+//
+// Unlike the other report functions, FormatTruncated does not
+// redact the unsafe parts of the error.
+func FormatTruncated(err error, maxLayers int) string { return report.FormatTruncated(err, maxLayers) }
+
+// FormatYAML renders the tree of layers of an error as a YAML
+// document, for readable dumps and tooling. Each layer is a mapping
+// with the following keys, the last three being omitted when empty:
+//
+//   - type: the type of the layer;
+//   - message: its message, with the unsafe parts redacted;
+//   - details: its safe details, as a list, except for the layers
+//     with a stack trace;
+//   - stack: its stack trace, as a block scalar;
+//   - causes: the layers of its cause(s), including all the branches
+//     of multi-errors, as a list.
+//
+// The empty string is returned for a nil error.
+func FormatYAML(err error) string { return report.FormatYAML(err) }
+
+// FormatLogfmt renders the fields produced by LogFields() in the
+// logfmt format, for example:
+//
+//	error="woo: ×" error.type=... error.grpc_code=NotFound error.stack="..."
+//
+// Like in LogFields(), the unsafe parts are redacted. The redaction
+// markers are then removed, like in FormatYAML(), and the leading
+// newline of the stack trace is trimmed. The values which contain
+// spaces, quotes, equal signs or control characters, e.g. the
+// newlines of a stack trace, are quoted and escaped. The fields with
+// an empty value are omitted. The empty string is returned for a nil
+// error.
+func FormatLogfmt(err error) string { return report.FormatLogfmt(err) }
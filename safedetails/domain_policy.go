@@ -0,0 +1,125 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package safedetails
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/cockroachdb/errors/domains"
+	"github.com/cockroachdb/redact"
+)
+
+// Policy customizes how Redact() processes errors in a given
+// domain. The zero value is the default policy, under which only
+// the unsafe parts of the message are redacted.
+type Policy struct {
+	// RedactAll, when set, causes the entire message to be
+	// redacted, including the parts otherwise considered safe.
+	RedactAll bool
+	// SafePatterns lists regular expressions whose matches are
+	// redacted even in the parts of the message otherwise
+	// considered safe.
+	SafePatterns []*regexp.Regexp
+}
+
+// SetDomainRedactionPolicy sets the policy used by Redact() for
+// errors in the given domain, as reported by domains.GetDomain().
+// Errors in domains without a policy are redacted as usual. Setting
+// the zero Policy restores the default behavior for the domain.
+//
+// This function is not safe for concurrent use with Redact() and is
+// meant to be called during initialization.
+func SetDomainRedactionPolicy(domain domains.Domain, p Policy) {
+	if !p.RedactAll && len(p.SafePatterns) == 0 {
+		delete(domainPolicies, domain)
+		return
+	}
+	if domainPolicies == nil {
+		domainPolicies = make(map[domains.Domain]Policy)
+	}
+	domainPolicies[domain] = p
+}
+
+var domainPolicies map[domains.Domain]Policy
+
+// TestingWithEmptyDomainRedactionPolicies is intended for use by tests.
+func TestingWithEmptyDomainRedactionPolicies() (restore func()) {
+	save := domainPolicies
+	domainPolicies = nil
+	return func() { domainPolicies = save }
+}
+
+// applyDomainPolicy applies the redaction policy of the domain of
+// the given error, if any, to its redactable rendering.
+func applyDomainPolicy(err error, s redact.RedactableBytes) redact.RedactableBytes {
+	if len(domainPolicies) == 0 {
+		return s
+	}
+	p, ok := domainPolicies[domains.GetDomain(err)]
+	if !ok {
+		return s
+	}
+	if p.RedactAll {
+		return redact.RedactableBytes(redact.RedactedMarker())
+	}
+	for _, re := range p.SafePatterns {
+		s = redactSafeMatches(s, re)
+	}
+	return s
+}
+
+// redactSafeMatches encloses the matches of the given regular
+// expression in the safe parts of a redactable string within
+// redaction markers.
+func redactSafeMatches(s redact.RedactableBytes, re *regexp.Regexp) redact.RedactableBytes {
+	start, end := redact.StartMarker(), redact.EndMarker()
+	var buf bytes.Buffer
+	for len(s) > 0 {
+		safe := s
+		i := bytes.Index(s, start)
+		if i < 0 {
+			s = nil
+		} else {
+			safe = s[:i]
+			s = s[i:]
+		}
+		k := 0
+		for _, m := range re.FindAllIndex(safe, -1) {
+			if m[0] == m[1] {
+				continue
+			}
+			buf.Write(safe[k:m[0]])
+			buf.Write(start)
+			buf.Write(safe[m[0]:m[1]])
+			buf.Write(end)
+			k = m[1]
+		}
+		buf.Write(safe[k:])
+		if i < 0 {
+			break
+		}
+		// Copy the unsafe part as-is.
+		j := bytes.Index(s[len(start):], end)
+		if j < 0 {
+			buf.Write(s)
+			break
+		}
+		j += len(start) + len(end)
+		buf.Write(s[:j])
+		s = s[j:]
+	}
+	return redact.RedactableBytes(buf.Bytes())
+}
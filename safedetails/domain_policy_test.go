@@ -0,0 +1,63 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package safedetails_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/cockroachdb/errors/domains"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/safedetails"
+	"github.com/cockroachdb/errors/testutils"
+)
+
+func TestDomainRedactionPolicy(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	defer safedetails.TestingWithEmptyDomainRedactionPolicies()()
+
+	const payments = domains.Domain("payments")
+	const billing = domains.Domain("billing")
+	const audit = domains.Domain("audit")
+	safedetails.SetDomainRedactionPolicy(payments, safedetails.Policy{RedactAll: true})
+	safedetails.SetDomainRedactionPolicy(billing, safedetails.Policy{
+		SafePatterns: []*regexp.Regexp{regexp.MustCompile(`account [0-9]+`)},
+	})
+
+	mk := func(d domains.Domain) error {
+		err := errutil.Newf("charge on account 1234 failed for %s", "alice")
+		return domains.WithDomain(err, d)
+	}
+
+	// The default policy is unchanged for errors without a domain
+	// and errors in domains without a policy.
+	tt.CheckStringEqual(safedetails.Redact(mk(domains.NoDomain)), `charge on account 1234 failed for ×`)
+	tt.CheckStringEqual(safedetails.Redact(mk(audit)), `charge on account 1234 failed for ×`)
+
+	// Distinct policies yield distinct output for the same message.
+	tt.CheckStringEqual(safedetails.Redact(mk(payments)), `×`)
+	tt.CheckStringEqual(safedetails.Redact(mk(billing)), `charge on × failed for ×`)
+
+	// The domain is found through wrappers.
+	tt.CheckStringEqual(safedetails.Redact(errutil.Wrap(mk(billing), "outer")), `outer: charge on × failed for ×`)
+
+	// The plain message is unaffected.
+	tt.CheckStringEqual(mk(payments).Error(), `charge on account 1234 failed for alice`)
+
+	// Setting the zero policy restores the default behavior.
+	safedetails.SetDomainRedactionPolicy(payments, safedetails.Policy{})
+	tt.CheckStringEqual(safedetails.Redact(mk(payments)), `charge on account 1234 failed for ×`)
+}
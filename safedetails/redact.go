@@ -19,7 +19,16 @@ import "github.com/cockroachdb/redact"
 // Redact returns a redacted version of the supplied item that is safe to use in
 // anonymized reporting.
 //
+// The patterns registered with RegisterRedactionPattern() are
+// applied to the unsafe parts prior to redaction. If r is an error
+// whose domain has a policy set via SetDomainRedactionPolicy(), the
+// policy is applied as well.
+//
 // NB: this interface is obsolete. Use redact.Sprint() directly.
 func Redact(r interface{}) string {
-	return redact.Sprint(r).Redact().StripMarkers()
+	s := applyRedactionPatterns(redact.RedactableBytes(redact.Sprint(r)))
+	if err, ok := r.(error); ok {
+		s = applyDomainPolicy(err, s)
+	}
+	return string(s.Redact().StripMarkers())
 }
@@ -0,0 +1,135 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package safedetails
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+)
+
+// RegisterRedactionPattern registers a regular expression whose
+// matches are replaced by the given replacement string in the unsafe
+// parts of redactable error messages, e.g. to remove e-mail
+// addresses or credit card numbers as a defense-in-depth
+// measure.
+//
+// The replacement applies when errors are formatted with
+// redaction markers (e.g. via redact.Sprint()) and in the output of
+// Redact(). It only affects the content that would otherwise be
+// emitted as unsafe; the replacement string itself is considered
+// safe and is emitted outside of redaction markers.
+//
+// This function is not safe for concurrent use with error
+// formatting and is meant to be called during initialization.
+func RegisterRedactionPattern(re *regexp.Regexp, replacement string) {
+	redactionPatterns = append(redactionPatterns, redactionPattern{
+		re:          re,
+		replacement: []byte(redact.EscapeMarkers([]byte(replacement))),
+	})
+}
+
+type redactionPattern struct {
+	re          *regexp.Regexp
+	replacement []byte
+}
+
+var redactionPatterns []redactionPattern
+
+// TestingWithEmptyRedactionPatterns is intended for use by tests.
+func TestingWithEmptyRedactionPatterns() (restore func()) {
+	save := redactionPatterns
+	redactionPatterns = nil
+	return func() { redactionPatterns = save }
+}
+
+// textPiece is a fragment of an unsafe string during the
+// application of the redaction patterns.
+type textPiece struct {
+	text []byte
+	safe bool
+}
+
+// applyRedactionPatterns applies the registered redaction patterns
+// to the unsafe parts of the given redactable string.
+func applyRedactionPatterns(s redact.RedactableBytes) redact.RedactableBytes {
+	if len(redactionPatterns) == 0 {
+		return s
+	}
+	start, end := redact.StartMarker(), redact.EndMarker()
+	var buf bytes.Buffer
+	for len(s) > 0 {
+		i := bytes.Index(s, start)
+		if i < 0 {
+			buf.Write(s)
+			break
+		}
+		buf.Write(s[:i])
+		s = s[i+len(start):]
+		j := bytes.Index(s, end)
+		unsafe := s
+		if j < 0 {
+			s = nil
+		} else {
+			unsafe = s[:j]
+			s = s[j+len(end):]
+		}
+		for _, p := range filterUnsafe(unsafe) {
+			if p.safe {
+				buf.Write(p.text)
+			} else {
+				buf.Write(start)
+				buf.Write(p.text)
+				buf.Write(end)
+			}
+		}
+	}
+	return redact.RedactableBytes(buf.Bytes())
+}
+
+// filterUnsafe splits an unsafe string into pieces according to the
+// registered redaction patterns. Each pattern is applied in turn to
+// the remaining unsafe pieces.
+func filterUnsafe(unsafe []byte) []textPiece {
+	pieces := []textPiece{{text: unsafe}}
+	for _, p := range redactionPatterns {
+		var next []textPiece
+		for _, piece := range pieces {
+			if piece.safe {
+				next = append(next, piece)
+				continue
+			}
+			k := 0
+			for _, m := range p.re.FindAllIndex(piece.text, -1) {
+				if m[0] > k {
+					next = append(next, textPiece{text: piece.text[k:m[0]]})
+				}
+				next = append(next, textPiece{text: p.replacement, safe: true})
+				k = m[1]
+			}
+			if k < len(piece.text) {
+				next = append(next, textPiece{text: piece.text[k:]})
+			}
+		}
+		pieces = next
+	}
+	return pieces
+}
+
+func init() {
+	errbase.RegisterRedactableOutputFilter(applyRedactionPatterns)
+}
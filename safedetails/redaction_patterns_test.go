@@ -0,0 +1,49 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package safedetails_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/safedetails"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/redact"
+)
+
+func TestRedactionPattern(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	defer safedetails.TestingWithEmptyRedactionPatterns()()
+	safedetails.RegisterRedactionPattern(regexp.MustCompile(`[a-z]+@[a-z.]+`), "<email>")
+
+	// An unsafe leaf message.
+	err := errors.New("no user foo@example.com here")
+	tt.CheckEqual(redact.Sprint(err), redact.RedactableString(`‹no user ›<email>‹ here›`))
+	tt.CheckStringEqual(safedetails.Redact(err), `×<email>×`)
+	// The plain message is unchanged.
+	tt.CheckStringEqual(err.Error(), `no user foo@example.com here`)
+
+	// A message with safe and unsafe parts. Only the unsafe
+	// parts are affected.
+	err = errutil.Newf("safe a@b.c: %s", "foo@example.com")
+	tt.CheckEqual(redact.Sprint(err), redact.RedactableString(`safe a@b.c: <email>`))
+	tt.CheckStringEqual(safedetails.Redact(err), `safe a@b.c: <email>`)
+
+	// Non-error values.
+	tt.CheckStringEqual(safedetails.Redact("write to foo@example.com"), `×<email>`)
+}
@@ -15,6 +15,8 @@
 package errors
 
 import (
+	"regexp"
+
 	"github.com/cockroachdb/errors/safedetails"
 	"github.com/cockroachdb/redact"
 )
@@ -54,3 +56,34 @@ func Safe(v interface{}) redact.SafeValue { return safedetails.Safe(v) }
 //
 // NB: this interface is obsolete. Use redact.Sprint() directly.
 func Redact(r interface{}) string { return safedetails.Redact(r) }
+
+// RegisterRedactionPattern registers a regular expression whose
+// matches are replaced by the given replacement string in the unsafe
+// parts of redactable error messages, e.g. to remove e-mail
+// addresses or credit card numbers as a defense-in-depth
+// measure.
+//
+// The replacement applies when errors are formatted with
+// redaction markers (e.g. via redact.Sprint()) and in the output of
+// Redact(). It only affects the content that would otherwise be
+// emitted as unsafe; the replacement string itself is considered
+// safe and is emitted outside of redaction markers.
+func RegisterRedactionPattern(re *regexp.Regexp, replacement string) {
+	safedetails.RegisterRedactionPattern(re, replacement)
+}
+
+// RedactionPolicy customizes how Redact() processes errors in a
+// given domain. The zero value is the default policy, under which
+// only the unsafe parts of the message are redacted.
+type RedactionPolicy = safedetails.Policy
+
+// SetDomainRedactionPolicy sets the policy used by Redact() for
+// errors in the given domain, as reported by GetDomain(). Errors in
+// domains without a policy are redacted as usual. Setting the zero
+// RedactionPolicy restores the default behavior for the domain.
+//
+// This function is not safe for concurrent use with Redact() and is
+// meant to be called during initialization.
+func SetDomainRedactionPolicy(domain Domain, p RedactionPolicy) {
+	safedetails.SetDomainRedactionPolicy(domain, p)
+}
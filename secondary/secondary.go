@@ -14,6 +14,8 @@
 
 package secondary
 
+import "github.com/cockroachdb/errors/errbase"
+
 // WithSecondaryError enhances the error given as first argument with
 // an annotation that carries the error given as second argument.  The
 // second error does not participate in cause analysis (Is, etc) and
@@ -35,6 +37,53 @@ func WithSecondaryError(err error, additionalErr error) error {
 	return &withSecondaryError{cause: err, secondaryError: additionalErr}
 }
 
+// WithSecondaryErrorSummary is like WithSecondaryError, but only the
+// given summary is revealed when printing out the error and in
+// reports, instead of the full secondary error. This is useful when
+// the secondary error is large.
+//
+// The full secondary error remains accessible in the current process
+// via GetSecondaryErrors(). It is not transmitted over the network:
+// after decoding, only the summary remains.
+//
+// The summary must not contain PII: it is considered safe for
+// reporting.
+//
+// If additionalErr is nil, the first error is returned as-is.
+//
+// Detail is shown:
+// - via `errors.GetSafeDetails()`, shows the summary.
+// - via `GetSecondaryErrors()` below, until the error is encoded.
+// - when formatting with `%+v`, shows the summary.
+// - in Sentry reports, shows the summary.
+func WithSecondaryErrorSummary(err error, additionalErr error, summary string) error {
+	if err == nil || additionalErr == nil {
+		return err
+	}
+	return &withSecondaryErrorSummary{cause: err, secondaryError: additionalErr, summary: summary}
+}
+
+// GetSecondaryErrors retrieves the secondary errors attached to the
+// error's direct causal chain with WithSecondaryError() or
+// WithSecondaryErrorSummary(), from outermost to innermost.
+//
+// The secondary errors attached with WithSecondaryErrorSummary() are
+// not included after the error has been transmitted over the network.
+func GetSecondaryErrors(err error) []error {
+	var res []error
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		switch e := c.(type) {
+		case *withSecondaryError:
+			res = append(res, e.secondaryError)
+		case *withSecondaryErrorSummary:
+			if e.secondaryError != nil {
+				res = append(res, e.secondaryError)
+			}
+		}
+	}
+	return res
+}
+
 // CombineErrors returns err, or, if err is nil, otherErr.
 // if err is non-nil, otherErr is attached as secondary error.
 // See the documentation of `WithSecondaryError()` for details.
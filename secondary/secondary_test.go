@@ -23,6 +23,7 @@ import (
 
 	"github.com/cockroachdb/errors/errbase"
 	"github.com/cockroachdb/errors/markers"
+	"github.com/cockroachdb/errors/report"
 	"github.com/cockroachdb/errors/secondary"
 	"github.com/cockroachdb/errors/testutils"
 	"github.com/kr/pretty"
@@ -230,3 +231,47 @@ func (e *werrFmt) FormatError(p errbase.Printer) error {
 	}
 	return e.cause
 }
+
+func TestWithSecondaryErrorSummary(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	baseErr := goErr.New("woo")
+	origErr := errors.New("hello original")
+	secErr := errors.Wrap(origErr, "huge")
+
+	tt.Check(secondary.WithSecondaryErrorSummary(nil, secErr, "x") == nil)
+	tt.CheckEqual(secondary.WithSecondaryErrorSummary(baseErr, nil, "x"), baseErr)
+
+	err := secondary.WithSecondaryErrorSummary(baseErr, secErr, "1 huge error")
+
+	// The secondary error is hidden from cause analysis.
+	tt.CheckStringEqual(err.Error(), "woo")
+	tt.Check(!markers.Is(err, origErr))
+
+	// Only the summary is printed and reported.
+	tt.CheckStringEqual(fmt.Sprintf("%+v", err), `woo
+(1) secondary error summary: 1 huge error
+Wraps: (2) woo
+Error types: (1) *secondary.withSecondaryErrorSummary (2) *errors.errorString`)
+	tt.CheckDeepEqual(errbase.GetSafeDetails(err).SafeDetails, []string{"1 huge error"})
+	event, _ := report.BuildSentryReport(err)
+	tt.Check(strings.Contains(event.Message, "1 huge error"))
+	tt.Check(!strings.Contains(event.Message, "huge:"))
+
+	// The full secondary error remains available locally.
+	tt.CheckDeepEqual(secondary.GetSecondaryErrors(err), []error{secErr})
+
+	// Also for regular secondary errors, from outermost to innermost.
+	otherErr := goErr.New("other")
+	err2 := secondary.WithSecondaryError(err, otherErr)
+	tt.CheckDeepEqual(secondary.GetSecondaryErrors(err2), []error{otherErr, secErr})
+	tt.CheckEqual(len(secondary.GetSecondaryErrors(baseErr)), 0)
+
+	// After a network traversal, only the summary remains.
+	enc := errbase.EncodeError(context.Background(), err2)
+	newErr := errbase.DecodeError(context.Background(), enc)
+	tt.CheckStringEqual(fmt.Sprintf("%+v", errbase.UnwrapOnce(newErr)), fmt.Sprintf("%+v", err))
+	secs := secondary.GetSecondaryErrors(newErr)
+	tt.CheckEqual(len(secs), 1)
+	tt.CheckStringEqual(secs[0].Error(), "other")
+}
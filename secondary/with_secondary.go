@@ -86,4 +86,5 @@ func init() {
 	tn := errbase.GetTypeKey((*withSecondaryError)(nil))
 	errbase.RegisterWrapperDecoder(tn, decodeWithSecondaryError)
 	errbase.RegisterWrapperEncoder(tn, encodeWithSecondaryError)
+	errbase.RegisterStrippableAnnotation(tn)
 }
@@ -0,0 +1,85 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package secondary
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
+)
+
+type withSecondaryErrorSummary struct {
+	cause error
+
+	// secondaryError is the full secondary error. It is only
+	// available locally, and is nil after decoding.
+	secondaryError error
+
+	// summary is what gets printed and reported in lieu of the
+	// secondary error.
+	summary string
+}
+
+var _ error = (*withSecondaryErrorSummary)(nil)
+var _ errbase.SafeDetailer = (*withSecondaryErrorSummary)(nil)
+var _ fmt.Formatter = (*withSecondaryErrorSummary)(nil)
+var _ errbase.SafeFormatter = (*withSecondaryErrorSummary)(nil)
+var _ errbase.Cloner = (*withSecondaryErrorSummary)(nil)
+
+// SafeDetails reports the summary of the secondary error.
+func (e *withSecondaryErrorSummary) SafeDetails() []string { return []string{e.summary} }
+
+// Printing a withSecondaryErrorSummary reveals only the summary.
+func (e *withSecondaryErrorSummary) Format(s fmt.State, verb rune) {
+	errbase.FormatError(e, s, verb)
+}
+
+func (e *withSecondaryErrorSummary) SafeFormatError(p errbase.Printer) (next error) {
+	if p.Detail() {
+		p.Printf("secondary error summary: %s", redact.Safe(e.summary))
+	}
+	return e.cause
+}
+
+// CloneWithCause implements the errbase.Cloner interface. The
+// secondary error is not encoded, so the layer cannot be rebuilt from
+// its encoding without losing it.
+func (e *withSecondaryErrorSummary) CloneWithCause(cause error) error {
+	return &withSecondaryErrorSummary{cause: cause, secondaryError: e.secondaryError, summary: e.summary}
+}
+
+func (e *withSecondaryErrorSummary) Error() string { return e.cause.Error() }
+func (e *withSecondaryErrorSummary) Cause() error  { return e.cause }
+func (e *withSecondaryErrorSummary) Unwrap() error { return e.cause }
+
+func decodeWithSecondaryErrorSummary(
+	_ context.Context, cause error, _ string, details []string, _ proto.Message,
+) error {
+	if len(details) == 0 {
+		// Some future version of the library is using a different
+		// encoding. Let DecodeError use the opaque type.
+		return nil
+	}
+	return &withSecondaryErrorSummary{cause: cause, summary: details[0]}
+}
+
+func init() {
+	tn := errbase.GetTypeKey((*withSecondaryErrorSummary)(nil))
+	errbase.RegisterWrapperDecoder(tn, decodeWithSecondaryErrorSummary)
+	errbase.RegisterStrippableAnnotation(tn)
+}
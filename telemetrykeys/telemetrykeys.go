@@ -14,7 +14,12 @@
 
 package telemetrykeys
 
-import "github.com/cockroachdb/errors/errbase"
+import (
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors/errbase"
+)
 
 // WithTelemetry annotates err with the given telemetry key(s).
 // The telemetry keys must be PII-free.
@@ -50,3 +55,22 @@ func GetTelemetryKeys(err error) []string {
 	}
 	return res
 }
+
+// GetLayerTelemetryKeys retrieves the telemetry keys carried by the
+// error (not its causes), or nil if it is not a telemetry annotation.
+func GetLayerTelemetryKeys(err error) []string {
+	if w, ok := err.(*withTelemetry); ok {
+		return w.keys
+	}
+	return nil
+}
+
+// TelemetryLabel returns the (de-duplicated) telemetry keys present
+// in the direct causal chain of the error, sorted and joined by
+// commas, e.g. "a,b,c". The result is deterministic, so that it can
+// be used as a metric label. It is empty if there are no keys.
+func TelemetryLabel(err error) string {
+	keys := GetTelemetryKeys(err)
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
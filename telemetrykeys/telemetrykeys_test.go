@@ -47,6 +47,13 @@ func TestTelemetry(t *testing.T) {
 	keys := telemetrykeys.GetTelemetryKeys(err)
 	sort.Strings(keys)
 	tt.CheckDeepEqual(keys, []string{"a", "b", "c"})
+	tt.CheckStringEqual(telemetrykeys.TelemetryLabel(err), "a,b,c")
+	tt.CheckStringEqual(telemetrykeys.TelemetryLabel(baseErr), "")
+
+	// GetLayerTelemetryKeys only looks at the given layer.
+	layer := telemetrykeys.WithTelemetry(err, "d")
+	tt.CheckDeepEqual(telemetrykeys.GetLayerTelemetryKeys(layer), []string{"d"})
+	tt.Check(telemetrykeys.GetLayerTelemetryKeys(err) == nil)
 
 	errV := fmt.Sprintf("%+v", err)
 	tt.Check(strings.Contains(errV, `keys: [a b]`))
@@ -61,6 +68,7 @@ func TestTelemetry(t *testing.T) {
 	keys = telemetrykeys.GetTelemetryKeys(newErr)
 	sort.Strings(keys)
 	tt.CheckDeepEqual(keys, []string{"a", "b", "c"})
+	tt.CheckStringEqual(telemetrykeys.TelemetryLabel(newErr), "a,b,c")
 
 	errV = fmt.Sprintf("%+v", newErr)
 	tt.Check(strings.Contains(errV, `keys: [a b]`))
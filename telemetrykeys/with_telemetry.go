@@ -57,4 +57,5 @@ func decodeWithTelemetry(
 
 func init() {
 	errbase.RegisterWrapperDecoder(errbase.GetTypeKey((*withTelemetry)(nil)), decodeWithTelemetry)
+	errbase.RegisterStrippableAnnotation(errbase.GetTypeKey((*withTelemetry)(nil)))
 }
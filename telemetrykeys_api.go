@@ -30,3 +30,9 @@ func WithTelemetry(err error, keys ...string) error { return telemetrykeys.WithT
 // all telemetry keys present in the direct causal chain
 // of the error. The keys may not be sorted.
 func GetTelemetryKeys(err error) []string { return telemetrykeys.GetTelemetryKeys(err) }
+
+// TelemetryLabel returns the (de-duplicated) telemetry keys present
+// in the direct causal chain of the error, sorted and joined by
+// commas, e.g. "a,b,c". The result is deterministic, so that it can
+// be used as a metric label. It is empty if there are no keys.
+func TelemetryLabel(err error) string { return telemetrykeys.TelemetryLabel(err) }
@@ -0,0 +1,100 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errcheck
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+)
+
+// CheckAllVerbs checks that err can be printed with all the
+// formatting verbs commonly applied to errors, directly and via
+// errbase.Formattable() and redact.Sprint(), without panicking. It
+// also checks that:
+//
+//   - %v prints the same as Error();
+//   - %+v via Formattable() starts with the message returned by
+//     Error();
+//   - the output of redact.Sprint(), with markers stripped, is
+//     congruent with either %v or %v via Formattable().
+//
+// This is meant to be used by authors of custom error types to
+// exercise their Format() and SafeFormatError() implementations.
+//
+// If any check fails, the test is marked as failed. The return value
+// is true iff all the checks succeed.
+func CheckAllVerbs(t testing.TB, err error) bool {
+	t.Helper()
+	if err == nil {
+		return true
+	}
+	ok := true
+	fail := func(format string, args ...interface{}) {
+		t.Helper()
+		t.Errorf(format, args...)
+		ok = false
+	}
+	print := func(desc string, fn func() string) string {
+		t.Helper()
+		s, p := catchPanic(fn)
+		if p != nil {
+			fail("%s panicked: %v", desc, p)
+		} else if strings.Contains(s, "(PANIC=") {
+			fail("%s panicked: %s", desc, s)
+		}
+		return s
+	}
+
+	msg := print("Error()", err.Error)
+	for _, verb := range []string{"%v", "%+v", "%s", "%q", "%x", "%X", "%#v"} {
+		verb := verb
+		print(verb, func() string { return fmt.Sprintf(verb, err) })
+		print(verb+" via Formattable()", func() string {
+			return fmt.Sprintf(verb, errbase.Formattable(err))
+		})
+	}
+	for _, verb := range []string{"%v", "%+v", "%s"} {
+		verb := verb
+		print("redact.Sprintf("+verb+")", func() string {
+			return string(redact.Sprintf(verb, err))
+		})
+	}
+	if !ok {
+		return false
+	}
+
+	if v := fmt.Sprintf("%v", err); v != msg {
+		fail("%%v not same as Error():\nError(): %q\n%%v:      %q", msg, v)
+	}
+	vf := fmt.Sprintf("%v", errbase.Formattable(err))
+	if vpf := fmt.Sprintf("%+v", errbase.Formattable(err)); !strings.HasPrefix(vpf, msg) {
+		fail("%%+v via Formattable() does not start with Error():\nError(): %q\n%%+v via Formattable():\n%s", msg, vpf)
+	}
+	if r := redact.Sprint(err).StripMarkers(); r != msg && r != vf {
+		fail("redact.Sprint() not congruent with %%v:\n%%v:              %q\nredact.Sprint(): %q", msg, r)
+	}
+	return ok
+}
+
+// catchPanic calls fn and returns its result, or the panic object if
+// it panicked.
+func catchPanic(fn func() string) (s string, p interface{}) {
+	defer func() { p = recover() }()
+	return fn(), nil
+}
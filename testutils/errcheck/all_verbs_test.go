@@ -0,0 +1,63 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errcheck_test
+
+import (
+	goErr "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/testutils/errcheck"
+)
+
+// panickyErr panics when formatted with %+v.
+type panickyErr struct{}
+
+func (e *panickyErr) Error() string { return "panicky" }
+func (e *panickyErr) Format(s fmt.State, verb rune) {
+	if s.Flag('+') {
+		panic("woops")
+	}
+	fmt.Fprint(s, e.Error())
+}
+
+// shoutyErr prints a different message with %v.
+type shoutyErr struct{}
+
+func (e *shoutyErr) Error() string                 { return "hello" }
+func (e *shoutyErr) Format(s fmt.State, verb rune) { fmt.Fprint(s, "HELLO") }
+
+func TestCheckAllVerbs(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	errcheck.CheckAllVerbs(t, nil)
+	errcheck.CheckAllVerbs(t, goErr.New("hello"))
+	errcheck.CheckAllVerbs(t, fmt.Errorf("woo: %w", goErr.New("hello")))
+	errcheck.CheckAllVerbs(t, errutil.Wrap(errutil.Newf("hello %s", "world"), "woo"))
+	errcheck.CheckAllVerbs(t, &consistentErr{cause: goErr.New("hello")})
+
+	r := &testutils.Recorder{TB: t}
+	tt.Check(!errcheck.CheckAllVerbs(r, &panickyErr{}))
+	tt.Check(strings.HasPrefix(r.Last(), `%+v panicked: %!v(PANIC=Format method: woops)`))
+
+	r = &testutils.Recorder{TB: t}
+	tt.Check(!errcheck.CheckAllVerbs(r, &shoutyErr{}))
+	tt.CheckStringEqual(r.Last(), `%v not same as Error():
+Error(): "hello"
+%v:      "HELLO"`)
+}
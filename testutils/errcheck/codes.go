@@ -0,0 +1,54 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errcheck
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/errors/extgrpc"
+	"github.com/cockroachdb/errors/exthttp"
+	"google.golang.org/grpc/codes"
+)
+
+// CheckGrpcCode checks that the gRPC code of the error, as reported
+// by extgrpc.GetGrpcCode(), is the expected code. If it is not, the
+// test is marked as failed and the actual code is printed alongside
+// the verbose representation of the error. The return value is true
+// iff the code matches.
+func CheckGrpcCode(t testing.TB, err error, expected codes.Code) bool {
+	t.Helper()
+	if actual := extgrpc.GetGrpcCode(err); actual != expected {
+		t.Errorf("unexpected gRPC code: got %s, expected %s\nerror: %+v",
+			actual, expected, err)
+		return false
+	}
+	return true
+}
+
+// CheckHTTPCode checks that the HTTP code of the error, as reported
+// by exthttp.GetHTTPCode(), is the expected code. An error without
+// an HTTP code is reported as having code 0. If the code does not
+// match, the test is marked as failed and the actual code is printed
+// alongside the verbose representation of the error. The return
+// value is true iff the code matches.
+func CheckHTTPCode(t testing.TB, err error, expected int) bool {
+	t.Helper()
+	if actual := exthttp.GetHTTPCode(err, 0); actual != expected {
+		t.Errorf("unexpected HTTP code: got %d, expected %d\nerror: %+v",
+			actual, expected, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errcheck_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/extgrpc"
+	"github.com/cockroachdb/errors/exthttp"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/testutils/errcheck"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCheckGrpcCode(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := extgrpc.WrapWithGrpcCode(errutil.New("hello"), codes.NotFound)
+
+	r := &testutils.Recorder{TB: t}
+	tt.Check(errcheck.CheckGrpcCode(r, err, codes.NotFound))
+	tt.CheckStringEqual(r.Last(), "")
+
+	// An error without a code has code Unknown.
+	tt.Check(errcheck.CheckGrpcCode(r, errutil.New("hello"), codes.Unknown))
+	tt.CheckStringEqual(r.Last(), "")
+	tt.Check(errcheck.CheckGrpcCode(r, nil, codes.OK))
+	tt.CheckStringEqual(r.Last(), "")
+
+	tt.Check(!errcheck.CheckGrpcCode(r, err, codes.Unavailable))
+	tt.CheckContains(r.Last(), "unexpected gRPC code: got NotFound, expected Unavailable\nerror: hello\n(1) gRPC code: NotFound")
+}
+
+func TestCheckHTTPCode(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	err := exthttp.WrapWithHTTPCode(errutil.New("hello"), http.StatusNotFound)
+
+	r := &testutils.Recorder{TB: t}
+	tt.Check(errcheck.CheckHTTPCode(r, err, http.StatusNotFound))
+	tt.CheckStringEqual(r.Last(), "")
+
+	// An error without a code has code 0.
+	tt.Check(errcheck.CheckHTTPCode(r, errutil.New("hello"), 0))
+	tt.CheckStringEqual(r.Last(), "")
+
+	tt.Check(!errcheck.CheckHTTPCode(r, err, http.StatusOK))
+	tt.CheckContains(r.Last(), "unexpected HTTP code: got 404, expected 200\nerror: hello\n(1) http code: 404")
+}
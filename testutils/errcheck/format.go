@@ -0,0 +1,57 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errcheck
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/redact"
+)
+
+// CheckErrorStringConsistency checks that the message returned by
+// the Error() method of err is the same as the one produced by the
+// library when formatting the error with %v via errbase.Formattable(),
+// and as the one produced by redact.Sprint() with the redaction
+// markers stripped. A divergence usually indicates that a custom
+// error type implements Error() and its formatting methods, e.g.
+// SafeFormatError(), inconsistently.
+//
+// If the messages differ, the test is marked as failed and the
+// divergent forms are printed alongside the verbose representation
+// of the error. The return value is true iff the messages are
+// consistent.
+func CheckErrorStringConsistency(t testing.TB, err error) bool {
+	t.Helper()
+	if err == nil {
+		return true
+	}
+	msg := err.Error()
+	var buf strings.Builder
+	if v := fmt.Sprintf("%v", errbase.Formattable(err)); v != msg {
+		fmt.Fprintf(&buf, "\n%%v via Formattable(): %q", v)
+	}
+	if r := redact.Sprint(err).StripMarkers(); r != msg {
+		fmt.Fprintf(&buf, "\nredact.Sprint():      %q", r)
+	}
+	if buf.Len() == 0 {
+		return true
+	}
+	t.Errorf("error message not consistent with its formatted forms:\nError():              %q%s\nerror: %+v",
+		msg, buf.String(), errbase.Formattable(err))
+	return false
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errcheck_test
+
+import (
+	goErr "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/testutils/errcheck"
+)
+
+// consistentErr implements Error() by delegating to the library.
+type consistentErr struct {
+	cause error
+}
+
+func (e *consistentErr) Error() string                 { return fmt.Sprintf("%v", e) }
+func (e *consistentErr) Unwrap() error                 { return e.cause }
+func (e *consistentErr) Format(s fmt.State, verb rune) { errbase.FormatError(e, s, verb) }
+func (e *consistentErr) SafeFormatError(p errbase.Printer) error {
+	p.Print("consistent")
+	return e.cause
+}
+
+// inconsistentErr forgets the prefix in Error().
+type inconsistentErr struct {
+	cause error
+}
+
+func (e *inconsistentErr) Error() string                 { return e.cause.Error() }
+func (e *inconsistentErr) Unwrap() error                 { return e.cause }
+func (e *inconsistentErr) Format(s fmt.State, verb rune) { errbase.FormatError(e, s, verb) }
+func (e *inconsistentErr) SafeFormatError(p errbase.Printer) error {
+	p.Print("inconsistent")
+	return e.cause
+}
+
+func TestConsistent(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	errcheck.CheckErrorStringConsistency(t, nil)
+	errcheck.CheckErrorStringConsistency(t, goErr.New("hello"))
+	errcheck.CheckErrorStringConsistency(t, errutil.Wrap(errutil.Newf("hello %s", "world"), "woo"))
+
+	r := &testutils.Recorder{TB: t}
+	tt.Check(errcheck.CheckErrorStringConsistency(r, &consistentErr{cause: goErr.New("hello")}))
+	tt.CheckStringEqual(r.Last(), "")
+}
+
+func TestInconsistent(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	r := &testutils.Recorder{TB: t}
+	tt.Check(!errcheck.CheckErrorStringConsistency(r, &inconsistentErr{cause: goErr.New("hello")}))
+	tt.Check(strings.HasPrefix(r.Last(), `error message not consistent with its formatted forms:
+Error():              "hello"
+%v via Formattable(): "inconsistent: hello"
+redact.Sprint():      "inconsistent: hello"
+error: inconsistent: hello
+(1) inconsistent
+`))
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package errcheck provides test assertions about errors: their
+// layer structure, their codes and their formatting.
+//
+// This is a separate package from testutils because testutils is
+// used by the internal tests of errbase, which this package depends
+// on.
+package errcheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errorspb"
+)
+
+// CheckStructurallyEqual checks that the two errors have the same
+// layer structure: the same tree of layers, where each layer has the
+// same error type mark and contributes the same message. Other
+// details, in particular stack traces, are ignored.
+//
+// This is stronger than markers.Is(), which only compares the
+// messages and types overall, and weaker than reflect.DeepEqual(),
+// which fails on errors with different stack traces or that have
+// been transferred through the network.
+//
+// If the errors differ, the test is marked as failed and the layers
+// of both errors are printed. The return value is true iff the errors
+// are structurally equal.
+func CheckStructurallyEqual(t testing.TB, a, b error) bool {
+	t.Helper()
+	la := layers(a)
+	lb := layers(b)
+	if len(la) == len(lb) {
+		i := 0
+		for ; i < len(la) && la[i] == lb[i]; i++ {
+		}
+		if i == len(la) {
+			return true
+		}
+	}
+	t.Errorf("errors not structurally equal; got:\n%s\nexpected:\n%s",
+		printLayers(la, lb), printLayers(lb, la))
+	return false
+}
+
+// layer describes one layer of an error's structure.
+type layer struct {
+	depth   int
+	mark    errorspb.ErrorTypeMark
+	message string
+	full    bool
+}
+
+func (l layer) String() string {
+	var buf strings.Builder
+	buf.WriteString(strings.Repeat("  ", l.depth))
+	buf.WriteString(l.mark.FamilyName)
+	if l.mark.Extension != "" {
+		fmt.Fprintf(&buf, " (%s)", l.mark.Extension)
+	}
+	fmt.Fprintf(&buf, ": %q", l.message)
+	if l.full {
+		buf.WriteString(" (full message)")
+	}
+	return buf.String()
+}
+
+// layers computes the structure of the error in pre-order. The
+// structure is extracted from the error's encoded form, so that the
+// messages contributed by each layer are computed in the same way
+// as when the error is transferred through the network.
+func layers(err error) []layer {
+	if err == nil {
+		return nil
+	}
+	enc := errbase.EncodeError(context.Background(), err)
+	return appendLayers(nil, &enc, 0)
+}
+
+func appendLayers(res []layer, enc *errorspb.EncodedError, depth int) []layer {
+	switch e := enc.Error.(type) {
+	case *errorspb.EncodedError_Leaf:
+		res = append(res, layer{
+			depth:   depth,
+			mark:    e.Leaf.Details.ErrorTypeMark,
+			message: e.Leaf.Message,
+		})
+		for _, c := range e.Leaf.MultierrorCauses {
+			res = appendLayers(res, c, depth+1)
+		}
+	case *errorspb.EncodedError_Wrapper:
+		res = append(res, layer{
+			depth:   depth,
+			mark:    e.Wrapper.Details.ErrorTypeMark,
+			message: e.Wrapper.Message,
+			full:    e.Wrapper.MessageType == errorspb.MessageType_FULL_MESSAGE,
+		})
+		res = appendLayers(res, &e.Wrapper.Cause, depth)
+	}
+	return res
+}
+
+// printLayers renders the layers in ls, highlighting those that
+// differ from the layer at the same position in other.
+func printLayers(ls, other []layer) string {
+	if len(ls) == 0 {
+		return "  <nil>"
+	}
+	var buf strings.Builder
+	for i, l := range ls {
+		marker := " "
+		if i >= len(other) || other[i] != l {
+			marker = ">"
+		}
+		fmt.Fprintf(&buf, "%s (%d) %s\n", marker, i+1, l)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
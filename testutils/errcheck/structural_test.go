@@ -0,0 +1,85 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package errcheck_test
+
+import (
+	"context"
+	goErr "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/errutil"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/testutils/errcheck"
+)
+
+func network(err error) error {
+	enc := errbase.EncodeError(context.Background(), err)
+	return errbase.DecodeError(context.Background(), enc)
+}
+
+func makeErr() error {
+	err := errutil.New("hello")
+	err = errutil.Wrap(err, "woo")
+	return fmt.Errorf("waa: %w", err)
+}
+
+func TestStructurallyEqual(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	// A decoded error is structurally equal to a fresh one,
+	// even though the stack traces differ.
+	errcheck.CheckStructurallyEqual(t, network(makeErr()), makeErr())
+	errcheck.CheckStructurallyEqual(t, makeErr(), makeErr())
+	errcheck.CheckStructurallyEqual(t, nil, nil)
+
+	multi := func() error { return goErr.Join(makeErr(), goErr.New("other")) }
+	errcheck.CheckStructurallyEqual(t, network(multi()), multi())
+
+	r := &testutils.Recorder{TB: t}
+	tt.Check(errcheck.CheckStructurallyEqual(r, makeErr(), makeErr()))
+	tt.CheckStringEqual(r.Last(), "")
+}
+
+func TestStructurallyDifferent(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	// A different message in one layer.
+	r := &testutils.Recorder{TB: t}
+	other := fmt.Errorf("waa: %w", errutil.Wrap(errutil.New("hello"), "wuu"))
+	tt.Check(!errcheck.CheckStructurallyEqual(r, makeErr(), other))
+	tt.CheckStringEqual(r.Last(), `errors not structurally equal; got:
+  (1) fmt/*fmt.wrapError: "waa"
+  (2) github.com/cockroachdb/errors/withstack/*withstack.withStack: ""
+> (3) github.com/cockroachdb/errors/errutil/*errutil.withPrefix: "woo: hello"
+  (4) github.com/cockroachdb/errors/withstack/*withstack.withStack: ""
+  (5) github.com/cockroachdb/errors/errutil/*errutil.leafError: "hello"
+expected:
+  (1) fmt/*fmt.wrapError: "waa"
+  (2) github.com/cockroachdb/errors/withstack/*withstack.withStack: ""
+> (3) github.com/cockroachdb/errors/errutil/*errutil.withPrefix: "wuu: hello"
+  (4) github.com/cockroachdb/errors/withstack/*withstack.withStack: ""
+  (5) github.com/cockroachdb/errors/errutil/*errutil.leafError: "hello"`)
+
+	// Same messages, but a different layer structure.
+	r = &testutils.Recorder{TB: t}
+	tt.Check(!errcheck.CheckStructurallyEqual(r, errutil.New("hello"), goErr.New("hello")))
+	tt.Check(r.Last() != "")
+
+	r = &testutils.Recorder{TB: t}
+	tt.Check(!errcheck.CheckStructurallyEqual(r, goErr.New("hello"), nil))
+	tt.Check(r.Last() != "")
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package testutils
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/redact"
+)
+
+// CheckBalancedMarkers checks that the redaction markers in s are
+// balanced: every start marker is followed by an end marker before
+// the next start marker, and there is no end marker without a
+// preceding start marker. This is meant to validate the redactable
+// output of SafeFormatError() implementations, e.g. via
+// redact.Sprint(). It reports a test error and returns false if the
+// markers are not balanced.
+func CheckBalancedMarkers(t testing.TB, s string) bool {
+	t.Helper()
+	if msg, suffix := findUnbalancedMarker(s); msg != "" {
+		t.Errorf("%s:\n%s\n\n(suffix: %q)", msg, s, suffix)
+		return false
+	}
+	return true
+}
+
+var anyMarker = regexp.MustCompile("((?s)[" +
+	string(redact.StartMarker()) + string(redact.EndMarker()) + "].*)")
+
+// findUnbalancedMarker returns a description of the first redaction
+// marker in s that breaks the balance and the suffix of s starting at
+// that marker, or empty strings if the markers are balanced.
+func findUnbalancedMarker(s string) (msg, suffix string) {
+	sm := string(redact.StartMarker())
+	em := string(redact.EndMarker())
+	expectOpen := true
+	for {
+		s = anyMarker.FindString(s)
+		if s == "" {
+			break
+		}
+		if expectOpen {
+			if strings.HasPrefix(s, em) {
+				return "unexpected closing redaction marker", s
+			}
+			s = strings.TrimPrefix(s, sm)
+		} else {
+			if strings.HasPrefix(s, sm) {
+				return "unexpected open redaction marker", s
+			}
+			s = strings.TrimPrefix(s, em)
+		}
+		expectOpen = !expectOpen
+	}
+	if !expectOpen {
+		return "unclosed redaction marker", s
+	}
+	return "", ""
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package testutils
+
+import (
+	"testing"
+)
+
+func TestCheckBalancedMarkers(t *testing.T) {
+	tt := T{T: t}
+
+	for _, s := range []string{
+		"",
+		"hello",
+		"‹hello›",
+		"‹hello› world ‹›",
+		"a\n‹b\nc›\nd",
+	} {
+		r := &Recorder{TB: t}
+		tt.Check(CheckBalancedMarkers(r, s))
+		tt.CheckEqual(len(r.Errors), 0)
+	}
+
+	for _, test := range []struct {
+		s      string
+		expMsg string
+	}{
+		{"hello›", "unexpected closing redaction marker"},
+		{"‹hello", "unclosed redaction marker"},
+		{"‹hello ‹world››", "unexpected open redaction marker"},
+		{"‹a› b› c", "unexpected closing redaction marker"},
+	} {
+		r := &Recorder{TB: t}
+		tt.Check(!CheckBalancedMarkers(r, test.s))
+		tt.Assert(len(r.Errors) == 1)
+		tt.CheckContains(r.Errors[0], test.expMsg)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package testutils
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// NormalizeErrorOutput removes the volatile parts of the verbose
+// output of an error, as produced by %+v, so that it can be compared
+// to a reference output across machines and code changes:
+//
+//   - file paths and line numbers are replaced by "<path>:<lineno>";
+//   - paths inside the errors library are replaced by "<path>";
+//   - the %#v representation of stack traces is replaced by
+//     "&stack{...}";
+//   - hexadecimal addresses are replaced by "0xAAAABBBB";
+//   - tab characters are replaced by "<tab>";
+//   - the suffixes of the names of anonymous functions are replaced by
+//     "...funcNN...".
+func NormalizeErrorOutput(s string) string {
+	s = fileref.ReplaceAllString(s, "<path>:<lineno>")
+	s = libref.ReplaceAllString(s, "<path>")
+	s = stackref.ReplaceAllString(s, `&stack{...}`)
+	s = hexref.ReplaceAllString(s, "0xAAAABBBB")
+	s = strings.ReplaceAll(s, "\t", "<tab>")
+	s = funcNN.ReplaceAllString(s, `...funcNN...`)
+	return s
+}
+
+// CheckFormatEqual checks that the two errors format identically
+// with %+v, after their output is normalized with
+// NormalizeErrorOutput(). If they do not, the test is marked as
+// failed and both normalized outputs are printed. The return value
+// is true iff the outputs are equal.
+func CheckFormatEqual(t testing.TB, a, b error) bool {
+	t.Helper()
+	as := NormalizeErrorOutput(fmt.Sprintf("%+v", a))
+	bs := NormalizeErrorOutput(fmt.Sprintf("%+v", b))
+	if as != bs {
+		t.Errorf("errors do not format identically; got:\n%s\nexpected:\n%s", as, bs)
+		return false
+	}
+	return true
+}
+
+var funcNN = regexp.MustCompile(`(?m)((\.\.func\d+| func\d+\(\)|\(func\d+\))"?$)|(\.\.func\d+\\n)`)
+
+var stackref = regexp.MustCompile(`(&(?:errors\.stack|withstack\.stack)\{[^}]*\})`)
+var fileref = regexp.MustCompile(`(` +
+	// Any path ending with .{go,s}:NNN:
+	`[a-zA-Z0-9\._/@-]+\.(?:go|s):\d+` +
+	`)`)
+var libroot = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Clean(filepath.Join(filepath.Dir(file), ".."))
+}()
+var libref = regexp.MustCompile(
+	`((` +
+		// Any path starting with the errors library root directory:
+		libroot +
+		`|` +
+		// Any path containing the error library:
+		`(/[a-zA-Z0-9\._/@-]+)+` +
+		`/github.com/cockroachdb/errors` +
+		`)` +
+		// Followed by some directory components:
+		`(/[a-zA-Z0-9\._/@-]+)*` +
+		`)`)
+var hexref = regexp.MustCompile(`(0x[a-f0-9]{4,})`)
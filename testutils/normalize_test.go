@@ -0,0 +1,72 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package testutils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errutil"
+)
+
+func makeStackedErr(msg string) error {
+	return errutil.Wrap(errutil.New(msg), "woo")
+}
+
+func TestNormalizeErrorOutput(t *testing.T) {
+	tt := T{T: t}
+
+	err := makeStackedErr("hello")
+	tt.CheckStringEqual(NormalizeErrorOutput(fmt.Sprintf("%+v", err)), `woo: hello
+(1) attached stack trace
+  -- stack trace:
+  | github.com/cockroachdb/errors/testutils.makeStackedErr
+  | <tab><path>:<lineno>
+  | [...repeated from below...]
+Wraps: (2) woo
+Wraps: (3) attached stack trace
+  -- stack trace:
+  | github.com/cockroachdb/errors/testutils.makeStackedErr
+  | <tab><path>:<lineno>
+  | github.com/cockroachdb/errors/testutils.TestNormalizeErrorOutput
+  | <tab><path>:<lineno>
+  | testing.tRunner
+  | <tab><path>:<lineno>
+  | runtime.goexit
+  | <tab><path>:<lineno>
+Wraps: (4) hello
+Error types: (1) *withstack.withStack (2) *errutil.withPrefix (3) *withstack.withStack (4) *errutil.leafError`)
+
+	tt.CheckStringEqual(NormalizeErrorOutput("&withstack.stack{0x1234abcd, 0x5678ef01}"), "&stack{...}")
+	tt.CheckStringEqual(NormalizeErrorOutput("ptr 0x1234abcd"), "ptr 0xAAAABBBB")
+}
+
+func TestCheckFormatEqual(t *testing.T) {
+	tt := T{T: t}
+
+	// Errors constructed at different lines format identically
+	// after normalization.
+	a := makeStackedErr("hello")
+	b := makeStackedErr("hello")
+	r := &Recorder{TB: t}
+	tt.Check(CheckFormatEqual(r, a, b))
+	tt.CheckEqual(len(r.Errors), 0)
+
+	r = &Recorder{TB: t}
+	tt.Check(!CheckFormatEqual(r, a, makeStackedErr("world")))
+	tt.Assert(len(r.Errors) == 1)
+	tt.CheckContains(r.Errors[0], "errors do not format identically; got:\nwoo: hello\n")
+	tt.CheckContains(r.Errors[0], "\nexpected:\nwoo: world\n")
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package testutils
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Recorder is a testing.TB which records the errors reported via
+// Errorf() instead of failing the test. This is meant to test the
+// assertion helpers of this package and its subpackages.
+type Recorder struct {
+	testing.TB
+	Errors []string
+}
+
+// Helper implements testing.TB.
+func (r *Recorder) Helper() {}
+
+// Errorf implements testing.TB.
+func (r *Recorder) Errorf(format string, args ...interface{}) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+// Last returns the last recorded error, or the empty string if there
+// is none.
+func (r *Recorder) Last() string {
+	if len(r.Errors) == 0 {
+		return ""
+	}
+	return r.Errors[len(r.Errors)-1]
+}
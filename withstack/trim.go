@@ -0,0 +1,57 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package withstack
+
+import (
+	"runtime"
+	"strings"
+)
+
+// TrimStackBelow removes the frames of the stack trace carried by err
+// that are deeper than the first frame whose function name contains
+// fnNameSubstring. This can be used to hide framework or runtime
+// frames below a meaningful boundary. The matching frame itself is
+// preserved.
+//
+// TrimStackBelow only operates on the outermost layer of err, which
+// must be a stack trace wrapper of this library, as is the case for
+// the errors produced by New(), Wrap(), WithStack() and their
+// variants. This layer is replaced by a copy carrying the trimmed
+// stack. The trimmed stack is reflected when formatting with `%+v`
+// and by GetReportableStackTrace().
+//
+// If the outermost layer is not a stack trace wrapper of this
+// library, for example if it is another wrapper around the stack
+// trace or a stack trace from github.com/pkg/errors, or if no frame
+// matches, err is returned unchanged.
+func TrimStackBelow(err error, fnNameSubstring string) error {
+	w, ok := err.(*withStack)
+	if !ok {
+		return err
+	}
+	frames := w.StackTrace()
+	for i, f := range frames {
+		fn := runtime.FuncForPC(uintptr(f) - 1)
+		if fn == nil || !strings.Contains(fn.Name(), fnNameSubstring) {
+			continue
+		}
+		trimmed := make(stack, i+1)
+		for j := range trimmed {
+			trimmed[j] = uintptr(frames[j])
+		}
+		return &withStack{cause: w.cause, stack: &trimmed}
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package withstack_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/withstack"
+	pkgErr "github.com/pkg/errors"
+)
+
+func TestTrimStackBelow(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	origErr := errors.New("hello")
+	err := withstack.WithStack(origErr)
+	full := fmt.Sprintf("%+v", err)
+	tt.Check(strings.Contains(full, "runtime.goexit"))
+
+	trimmed := withstack.TrimStackBelow(err, "testing.tRunner")
+	tt.CheckStringEqual(trimmed.Error(), err.Error())
+	tt.CheckEqual(errors.Unwrap(trimmed), origErr)
+	tt.CheckStringEqual(fmt.Sprintf("%T", trimmed), fmt.Sprintf("%T", err))
+
+	// The frames below tRunner are removed from the formatted output.
+	v := fmt.Sprintf("%+v", trimmed)
+	tt.Check(strings.Contains(v, "TestTrimStackBelow"))
+	tt.Check(strings.Contains(v, "testing.tRunner"))
+	tt.Check(!strings.Contains(v, "runtime.goexit"))
+
+	// The original error is unchanged.
+	tt.CheckStringEqual(fmt.Sprintf("%+v", err), full)
+
+	// The reportable stack trace is trimmed too. It has the oldest
+	// frame first.
+	r := withstack.GetReportableStackTrace(trimmed)
+	tt.Assert(r != nil && len(r.Frames) > 0)
+	tt.CheckStringEqual(r.Frames[0].Function, "tRunner")
+	tt.CheckStringEqual(r.Frames[0].Module, "testing")
+
+	// No matching frame: the error is unchanged.
+	tt.CheckEqual(withstack.TrimStackBelow(err, "nonexistent.function"), err)
+
+	// No stack trace: the error is unchanged.
+	tt.CheckEqual(withstack.TrimStackBelow(origErr, "testing.tRunner"), origErr)
+
+	// The stack trace is not carried by the outermost layer: the
+	// error is unchanged.
+	wrapped := fmt.Errorf("woo: %w", err)
+	tt.CheckEqual(withstack.TrimStackBelow(wrapped, "testing.tRunner"), wrapped)
+
+	// The stack traces of other libraries are not modified.
+	pkgStack := pkgErr.WithStack(origErr)
+	tt.CheckEqual(withstack.TrimStackBelow(pkgStack, "testing.tRunner"), pkgStack)
+	pkgLeaf := pkgErr.New("hello")
+	tt.CheckEqual(withstack.TrimStackBelow(pkgLeaf, "testing.tRunner"), pkgLeaf)
+}
@@ -47,7 +47,30 @@ func WithStackDepth(err error, depth int) error {
 	if err == nil {
 		return nil
 	}
-	return &withStack{cause: err, stack: callers(depth + 1)}
+	err = &withStack{cause: err, stack: callers(depth + 1)}
+	errbase.CallConstructionHook(err)
+	return err
+}
+
+// HasStack returns true iff the error or one of its causes in the
+// direct causal chain carries a stack trace. This can be used to
+// avoid capturing redundant stack traces.
+//
+// This recognizes the same stack traces as GetReportableStackTrace(),
+// including those that have been transferred through the network.
+func HasStack(err error) bool {
+	for c := err; c != nil; c = errbase.UnwrapOnce(c) {
+		if _, ok := c.(errbase.StackTraceProvider); ok {
+			return true
+		}
+		if sd, ok := c.(errbase.SafeDetailer); ok && len(sd.SafeDetails()) > 0 {
+			switch errbase.GetTypeKey(c) {
+			case pkgFundamental, pkgWithStackName, ourWithStackName:
+				return true
+			}
+		}
+	}
+	return false
 }
 
 type withStack struct {
@@ -60,6 +83,8 @@ var _ error = (*withStack)(nil)
 var _ fmt.Formatter = (*withStack)(nil)
 var _ errbase.SafeFormatter = (*withStack)(nil)
 var _ errbase.SafeDetailer = (*withStack)(nil)
+var _ errbase.StackCompactor = (*withStack)(nil)
+var _ errbase.Cloner = (*withStack)(nil)
 
 func (w *withStack) Error() string { return w.cause.Error() }
 func (w *withStack) Cause() error  { return w.cause }
@@ -71,7 +96,11 @@ func (w *withStack) Format(s fmt.State, verb rune) { errbase.FormatError(w, s, v
 // SafeFormatError implements the errbase.SafeFormatter interface.
 func (w *withStack) SafeFormatError(p errbase.Printer) error {
 	if p.Detail() {
-		p.Printf("attached stack trace")
+		if w.isCompacted() {
+			p.Printf("attached stack trace (compacted; see the innermost stack trace)")
+		} else {
+			p.Printf("attached stack trace")
+		}
 	}
 	// We do not print the stack trace ourselves - errbase.FormatError()
 	// does this for us.
@@ -80,5 +109,40 @@ func (w *withStack) SafeFormatError(p errbase.Printer) error {
 
 // SafeDetails implements the errbase.SafeDetailer interface.
 func (w *withStack) SafeDetails() []string {
+	if w.isCompacted() {
+		return nil
+	}
 	return []string{fmt.Sprintf("%+v", w.StackTrace())}
 }
+
+// compactedStack is shared by all the withStack instances produced
+// by CompactedWithCause(), which do not hold a stack trace.
+var compactedStack stack
+
+// CompactedWithCause implements the errbase.StackCompactor interface.
+func (w *withStack) CompactedWithCause(cause error) error {
+	return &withStack{cause: cause, stack: &compactedStack}
+}
+
+// CloneWithCause implements the errbase.Cloner interface. The stack
+// trace is immutable and is shared with the copy.
+func (w *withStack) CloneWithCause(cause error) error {
+	return &withStack{cause: cause, stack: w.stack}
+}
+
+func (w *withStack) isCompacted() bool { return w.stack == &compactedStack }
+
+// StackTrace implements the errbase.StackTraceProvider interface.
+func (w *withStack) StackTrace() errbase.StackTrace {
+	if w.isCompacted() {
+		return nil
+	}
+	return w.stack.StackTrace()
+}
+
+func init() {
+	// The stack trace is preserved in the safe details, which is all
+	// that is needed after the error is decoded as an opaque wrapper.
+	errbase.RegisterOpaqueDecoding(errbase.GetTypeKey((*withStack)(nil)))
+	errbase.RegisterStackTraceCarrier(errbase.GetTypeKey((*withStack)(nil)))
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package withstack_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors/errbase"
+	"github.com/cockroachdb/errors/testutils"
+	"github.com/cockroachdb/errors/withstack"
+	pkgErr "github.com/pkg/errors"
+)
+
+func TestHasStack(t *testing.T) {
+	tt := testutils.T{T: t}
+
+	network := func(err error) error {
+		return errbase.DecodeError(context.Background(), errbase.EncodeError(context.Background(), err))
+	}
+
+	simpleErr := errors.New("hello")
+	tt.Check(!withstack.HasStack(nil))
+	tt.Check(!withstack.HasStack(simpleErr))
+	tt.Check(!withstack.HasStack(fmt.Errorf("woo: %w", simpleErr)))
+	tt.Check(!withstack.HasStack(pkgErr.WithMessage(simpleErr, "woo")))
+	tt.Check(!withstack.HasStack(network(simpleErr)))
+
+	testData := []error{
+		withstack.WithStack(simpleErr),
+		pkgErr.WithStack(simpleErr),
+		pkgErr.New("woo"),
+		pkgErr.WithMessage(pkgErr.New("woo"), "waa"),
+		fmt.Errorf("waa: %w", withstack.WithStack(simpleErr)),
+	}
+	for _, err := range testData {
+		tt.Run(err.Error(), func(tt testutils.T) {
+			tt.Check(withstack.HasStack(err))
+			tt.Check(withstack.HasStack(network(err)))
+		})
+	}
+}
@@ -37,6 +37,35 @@ func WithStack(err error) error { return withstack.WithStackDepth(err, 1) }
 // See the documentation of WithStack() for more details.
 func WithStackDepth(err error, depth int) error { return withstack.WithStackDepth(err, depth+1) }
 
+// HasStack returns true iff the error or one of its causes in the
+// direct causal chain carries a stack trace. This can be used to
+// avoid capturing redundant stack traces.
+//
+// This recognizes the same stack traces as GetReportableStackTrace(),
+// including those that have been transferred through the network.
+func HasStack(err error) bool { return withstack.HasStack(err) }
+
+// TrimStackBelow removes the frames of the stack trace carried by err
+// that are deeper than the first frame whose function name contains
+// fnNameSubstring. This can be used to hide framework or runtime
+// frames below a meaningful boundary. The matching frame itself is
+// preserved.
+//
+// TrimStackBelow only operates on the outermost layer of err, which
+// must be a stack trace wrapper of this library, as is the case for
+// the errors produced by New(), Wrap(), WithStack() and their
+// variants. This layer is replaced by a copy carrying the trimmed
+// stack. The trimmed stack is reflected when formatting with `%+v`
+// and by GetReportableStackTrace().
+//
+// If the outermost layer is not a stack trace wrapper of this
+// library, for example if it is another wrapper around the stack
+// trace or a stack trace from github.com/pkg/errors, or if no frame
+// matches, err is returned unchanged.
+func TrimStackBelow(err error, fnNameSubstring string) error {
+	return withstack.TrimStackBelow(err, fnNameSubstring)
+}
+
 // ReportableStackTrace aliases the type of the same name in the sentry
 // package. This is used by SendReport().
 type ReportableStackTrace = withstack.ReportableStackTrace